@@ -0,0 +1,163 @@
+package rqp
+
+// Node is a node of conditions of AST: *AndNode, *OrNode or *ComparisonNode
+type Node interface {
+	node()
+}
+
+// AndNode is conditions joined by AND
+type AndNode struct {
+	Nodes []Node
+}
+
+// OrNode is conditions joined by OR
+type OrNode struct {
+	Nodes []Node
+}
+
+// ComparisonNode is a condition of single filter
+type ComparisonNode struct {
+	Name   string      // name of filter (see Column for name of column)
+	Method Method      // compare method
+	Value  interface{} // value of filter like Filter.Value
+	Filter *Filter     // parsed filter of the node
+}
+
+func (*AndNode) node()        {}
+func (*OrNode) node()         {}
+func (*ComparisonNode) node() {}
+
+// SortSpec is sorting by field
+type SortSpec struct {
+	By   string
+	Desc bool
+}
+
+// Pagination is limit and offset of the Query, zero values aren't set
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// AST is parsed Query as typed tree of conditions with sorting, fields and pagination.
+type AST struct {
+	Where      Node // nil without filters
+	Sort       []SortSpec
+	Fields     []string
+	Pagination Pagination
+}
+
+// AST returns parsed Query as typed tree, eg. to build own backends or inspect conditions
+// without parsing of Where output. OR statements are OrNode, groups of filters are joined
+// by AndNode or OrNode of top level join (see SetTopLevelJoin), forced filters are always
+// joined by AND. Single conditions aren't wrapped by AndNode. Disabled filters are skipped,
+// raw filters and cursor of keyset pagination are ErrMethodNotAllowed like in Groups.
+//
+// Nodes are built on every call, changes of them don't change the Query. Filter of ComparisonNode
+// is the filter of the Query, so changes of it in place change Where and Args.
+func (q *Query) AST() (*AST, error) {
+	filters, err := q.Groups()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]Node, 0, len(filters))
+	for _, group := range filters {
+		or := make([]Node, len(group))
+		for i, f := range group {
+			or[i] = comparison(f)
+		}
+		groups = append(groups, joinNodes(or, "OR"))
+	}
+
+	and := []Node{joinNodes(groups, q.topLevelJoin())}
+	if len(groups) == 0 {
+		and = and[:0]
+	}
+	for _, f := range q.forcedFilters() {
+		and = append(and, comparison(f))
+	}
+
+	ast := &AST{
+		Where:      joinNodes(and, "AND"),
+		Fields:     append([]string(nil), q.Fields...),
+		Pagination: Pagination{Limit: q.Limit, Offset: q.Offset},
+	}
+	for _, s := range q.Sorts {
+		ast.Sort = append(ast.Sort, SortSpec{By: s.By, Desc: s.Desc})
+	}
+	return ast, nil
+}
+
+// comparison returns node of filter
+func comparison(f *Filter) *ComparisonNode {
+	return &ComparisonNode{Name: f.Name, Method: f.Method, Value: f.Value, Filter: f}
+}
+
+// joinNodes returns nodes joined by logical operator, single node is returned as is
+func joinNodes(nodes []Node, join string) Node {
+	switch {
+	case len(nodes) == 0:
+		return nil
+	case len(nodes) == 1:
+		return nodes[0]
+	case join == "OR":
+		return &OrNode{Nodes: nodes}
+	default:
+		return &AndNode{Nodes: nodes}
+	}
+}
+
+// Visitor visits nodes of Walk, like ast.Visitor of go/ast:
+// if result w of Visit(node) isn't nil, Walk visits each child of node with w, then calls w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses tree of conditions in depth-first order: it starts by calling v.Visit(node),
+// node could be nil.
+func Walk(v Visitor, node Node) {
+	if node == nil {
+		return
+	}
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *AndNode:
+		for _, child := range n.Nodes {
+			Walk(v, child)
+		}
+	case *OrNode:
+		for _, child := range n.Nodes {
+			Walk(v, child)
+		}
+	}
+
+	v.Visit(nil)
+}
+
+// inspector is Visitor of Inspect
+type inspector func(Node) bool
+
+// Visit calls f of inspector
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses tree of conditions in depth-first order like Walk: it starts by calling f(node),
+// if f returns true, Inspect visits each child of node, followed by a call of f(nil).
+//
+//	rqp.Inspect(ast.Where, func(n rqp.Node) bool {
+//		if c, ok := n.(*rqp.ComparisonNode); ok {
+//			names = append(names, c.Name)
+//		}
+//		return true
+//	})
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,116 @@
+package rqp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAST(t *testing.T) {
+	q := NewQV(nil, Validations{
+		"fields": In("id", "name"),
+		"sort":   In("id", "name"),
+		"id:int": nil,
+		"name":   nil,
+		"status": nil,
+	})
+	assert.NoError(t, q.SetUrlString("?id[in]=1,2&name[like]=t*|status=a&fields=id,name&sort=-name&limit=10&offset=5"))
+	assert.NoError(t, q.Parse())
+	q.AddForcedFilter("tenant_id", EQ, 7)
+
+	ast, err := q.AST()
+	assert.NoError(t, err)
+	assert.Equal(t, &AndNode{Nodes: []Node{
+		&AndNode{Nodes: []Node{
+			&ComparisonNode{Name: "id", Method: IN, Value: []int{1, 2}, Filter: q.Filters[0]},
+			&OrNode{Nodes: []Node{
+				&ComparisonNode{Name: "name", Method: LIKE, Value: "t*", Filter: q.Filters[1]},
+				&ComparisonNode{Name: "status", Method: EQ, Value: "a", Filter: q.Filters[2]},
+			}},
+		}},
+		&ComparisonNode{Name: "tenant_id", Method: EQ, Value: 7, Filter: q.ForcedFilters()[0]},
+	}}, ast.Where)
+	assert.Equal(t, []SortSpec{{By: "name", Desc: true}}, ast.Sort)
+	assert.Equal(t, []string{"id", "name"}, ast.Fields)
+	assert.Equal(t, Pagination{Limit: 10, Offset: 5}, ast.Pagination)
+
+	// raw filters can't be in AST
+	q.AddFilterRaw("1 = 1")
+	_, err = q.AST()
+	assert.Equal(t, ErrMethodNotAllowed, errors.Cause(err))
+
+	// single condition isn't wrapped, top level OR
+	q = New().AddFilter("id", EQ, 1)
+	ast, err = q.AST()
+	assert.NoError(t, err)
+	assert.Equal(t, &ComparisonNode{Name: "id", Method: EQ, Value: 1, Filter: q.Filters[0]}, ast.Where)
+	ast, err = New().AST()
+	assert.NoError(t, err)
+	assert.Nil(t, ast.Where)
+
+	q = New().AddFilter("id", EQ, 1).AddFilter("name", EQ, "a")
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	ast, err = q.AST()
+	assert.NoError(t, err)
+	assert.IsType(t, &OrNode{}, ast.Where)
+}
+
+// printer prints nodes of Walk
+type printer struct {
+	b     *strings.Builder
+	depth int
+}
+
+func (p printer) Visit(node Node) Visitor {
+	switch n := node.(type) {
+	case nil:
+		p.b.WriteString(strings.Repeat(" ", p.depth-1) + "end\n")
+		return nil
+	case *AndNode:
+		p.b.WriteString(strings.Repeat(" ", p.depth) + "and\n")
+	case *OrNode:
+		p.b.WriteString(strings.Repeat(" ", p.depth) + "or\n")
+	case *ComparisonNode:
+		p.b.WriteString(strings.Repeat(" ", p.depth) + n.Name + " " + string(n.Method) + "\n")
+	}
+	return printer{b: p.b, depth: p.depth + 1}
+}
+
+func TestWalk(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "name": nil, "status": nil})
+	assert.NoError(t, q.SetUrlString("?id=1&name=a|status[ne]=b"))
+	assert.NoError(t, q.Parse())
+	ast, err := q.AST()
+	assert.NoError(t, err)
+
+	var b strings.Builder
+	Walk(printer{b: &b}, ast.Where)
+	assert.Equal(t, `and
+ id EQ
+ end
+ or
+  name EQ
+  end
+  status NE
+  end
+ end
+end
+`, b.String())
+
+	// skipping of children
+	var names []string
+	Inspect(ast.Where, func(n Node) bool {
+		switch n := n.(type) {
+		case *OrNode:
+			return false
+		case *ComparisonNode:
+			names = append(names, n.Name)
+		}
+		return true
+	})
+	assert.Equal(t, []string{"id"}, names)
+
+	Walk(printer{b: &b}, nil)
+}
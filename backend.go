@@ -0,0 +1,91 @@
+package rqp
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Functions of this file are used to render parsed Query for other backends than SQL,
+// eg. by rqpmongo package.
+
+// Groups returns filters split into OR statements, single filters are groups of one filter.
+// Disabled filters are skipped. Raw filters (see AddFilterRaw) and cursor of keyset pagination
+// are SQL so they can't be rendered for other backends: Groups returns ErrMethodNotAllowed
+// instead of result set wider than of SQL.
+// Forced filters aren't in groups, see ForcedFilters.
+func (q *Query) Groups() ([][]*Filter, error) {
+	groups := make([][]*Filter, 0, len(q.Filters))
+	for _, group := range q.groups() {
+		filters := make([]*Filter, 0, len(group))
+		for _, f := range group {
+			if f.Disabled {
+				continue
+			}
+			if f.Method == raw || f.Method == cursor {
+				return nil, errors.Wrap(ErrMethodNotAllowed, f.Name)
+			}
+			filters = append(filters, f)
+		}
+		if len(filters) > 0 {
+			groups = append(groups, filters)
+		}
+	}
+	return groups, nil
+}
+
+// TopLevelJoin returns logical operator of groups of filters: AND or OR, see SetTopLevelJoin
+func (q *Query) TopLevelJoin() string {
+	return q.topLevelJoin()
+}
+
+// Column returns field of backend for name of filter, sort or field, see SetNameMapping.
+// Unlike columns of SQL statements it's never quoted. Columns of the main table are qualified
+// if the Query is qualified by it (see Qualified).
+func (q *Query) Column(name string) string {
+	if c, ok := q.nameMapping[name]; ok {
+		return q.qualify(c)
+	}
+	return q.qualify(name)
+}
+
+// Relations returns sorted names of relations used by filters, sorts, fields and group, see SetRelations.
+// Query builders join them and qualify columns of the main table by Qualified.
+func (q *Query) Relations() []string {
+	return q.relationNames()
+}
+
+// Relation returns relation by name with default values: "id" referenced column and "LEFT" type of JOIN
+func (q *Query) Relation(name string) (Relation, bool) {
+	r, ok := q.relations[name]
+	if !ok {
+		return r, false
+	}
+	r.Join = strings.ToUpper(r.Join)
+	if r.Join == "" {
+		r.Join = "LEFT"
+	}
+	if r.References == "" {
+		r.References = "id"
+	}
+	return r, true
+}
+
+// Qualifier returns alias or name of the main table which qualifies columns, see Qualified.
+// It's empty if the Query isn't qualified.
+func (q *Query) Qualifier() string {
+	return q.qualifier
+}
+
+// FilterArgs returns arguments of filter like Args does: patterns of LIKE methods
+// with `%` and `_` wildcards, values of IN and NIN, bounds of BETWEEN and RANGE, etc.
+// Values of IN, NIN and CONTAINS are always separate arguments.
+func (q *Query) FilterArgs(f *Filter) ([]interface{}, error) {
+	switch f.Method {
+	case IN, NIN, CONTAINS:
+		_, args, err := in("?", toSlice(f.Value))
+		return args, err
+	default:
+		return f.args(q)
+	}
+}
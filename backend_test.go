@@ -0,0 +1,80 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackend(t *testing.T) {
+	q := NewQV(nil, Validations{
+		"id:int":    nil,
+		"name":      nil,
+		"createdAt": nil,
+	}).SetAnyIN(true).SetNameMapping(Replacer{"createdAt": "created_at"}).SetQuoteIdentifiers(true)
+	assert.NoError(t, q.SetUrlString("?id[in]=1,2&name[like]=tim*|createdAt=a"))
+	assert.NoError(t, q.Parse())
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+
+	groups, err := q.Groups()
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, "id", groups[0][0].Name)
+		assert.Len(t, groups[1], 2)
+	}
+	assert.Equal(t, "OR", q.TopLevelJoin())
+	assert.Equal(t, "created_at", q.Column("createdAt"))
+	assert.Equal(t, "name", q.Column("name"))
+
+	args, err := q.FilterArgs(groups[0][0])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	args, err = q.FilterArgs(groups[1][0])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"tim%"}, args)
+
+	assert.NoError(t, q.DisableFilter("id"))
+	groups, err = q.Groups()
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+
+	// raw filters and cursor are SQL, they aren't skipped silently
+	q.AddFilterRaw("1 = 1")
+	_, err = q.Groups()
+	assert.Equal(t, ErrMethodNotAllowed, errors.Cause(err))
+	assert.EqualError(t, err, "1 = 1: method are not allowed")
+
+	c, _ := EncodeCursor(10)
+	q = NewQV(nil, Validations{"sort": In("id")}).SetCursorPagination(true)
+	assert.NoError(t, q.SetUrlString("?sort=id&after="+c))
+	assert.NoError(t, q.Parse())
+	_, err = q.Groups()
+	assert.Equal(t, ErrMethodNotAllowed, errors.Cause(err))
+}
+
+func TestBackendRelations(t *testing.T) {
+	q := NewQV(nil, Validations{"author.name": nil, "id:int": nil}).SetRelations(map[string]Relation{
+		"author":   {Table: "users", ForeignKey: "author_id"},
+		"category": {Table: "categories", ForeignKey: "category_id", References: "cid", Join: "inner"},
+	}).SetNameMapping(Replacer{"id": "post_id"})
+	assert.NoError(t, q.SetUrlString("?author.name=tim&id=1"))
+	assert.NoError(t, q.Parse())
+
+	assert.Equal(t, []string{"author"}, q.Relations())
+	r, ok := q.Relation("author")
+	assert.True(t, ok)
+	assert.Equal(t, Relation{Table: "users", ForeignKey: "author_id", References: "id", Join: "LEFT"}, r)
+	r, _ = q.Relation("category")
+	assert.Equal(t, "INNER", r.Join)
+	_, ok = q.Relation("tag")
+	assert.False(t, ok)
+
+	assert.Equal(t, "", q.Qualifier())
+	assert.Equal(t, "post_id", q.Column("id"))
+	p := q.Qualified("posts AS p")
+	assert.Equal(t, "p", p.Qualifier())
+	assert.Equal(t, "p.post_id", p.Column("id"))
+	assert.Equal(t, "author.name", p.Column("author.name"))
+}
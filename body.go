@@ -0,0 +1,137 @@
+package rqp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// filtersKey is a key of filters in JSON document of ParseJSON
+const filtersKey = "filters"
+
+// ParseJSON parses JSON document of search request, eg. body of POST /search:
+//
+//	{"filters": {"age": {"gte": 18}, "status": "active"}, "sort": ["-created_at"], "limit": 20}
+//
+// It's the same as the query `?age[gte]=18&status=active&sort=-created_at&limit=20`, so
+// validations and options are used in the same way. Filters are objects of methods with values
+// or values for EQ method, arrays are lists of values of IN, NIN, BETWEEN, etc. null is NULL.
+// Other keys (fields, sort, limit, offset, page, etc.) are parameters of the query.
+// Values are parsed as values of query, so strings containing delimiter of OR
+// and elements of arrays containing delimiter of IN are ErrBadFormat.
+func (q *Query) ParseJSON(r io.Reader) error {
+	values, err := q.jsonValues(r)
+	if err != nil {
+		return err
+	}
+	return q.SetUrlQuery(values).Parse()
+}
+
+// jsonValues converts JSON document to values of query
+func (q *Query) jsonValues(r io.Reader) (url.Values, error) {
+	var doc map[string]interface{}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.Wrapf(ErrBadFormat, "body: %v", err)
+	}
+
+	values := make(url.Values, len(doc))
+	for key, value := range doc {
+		if key != filtersKey {
+			s, err := q.jsonValue(value)
+			if err != nil {
+				return nil, errors.Wrap(err, key)
+			}
+			values.Add(key, s)
+			continue
+		}
+
+		filters, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.Wrap(ErrBadFormat, key)
+		}
+		for name, v := range filters {
+			methods, ok := v.(map[string]interface{})
+			if !ok {
+				s, err := q.jsonValue(v)
+				if err != nil {
+					return nil, errors.Wrap(err, name)
+				}
+				values.Add(name, s)
+				continue
+			}
+			for m, v := range methods {
+				key := name + "[" + m + "]"
+				s, err := q.jsonValue(v)
+				if err != nil {
+					return nil, errors.Wrap(err, key)
+				}
+				values.Add(key, s)
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// jsonValue returns JSON value as value of query, lists are joined by delimiter of IN
+func (q *Query) jsonValue(value interface{}) (string, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		s, err := jsonScalar(value)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(s, q.delimiterOR) {
+			return "", ErrBadFormat
+		}
+		return s, nil
+	}
+	if len(list) == 0 {
+		return "", ErrEmptyValue
+	}
+
+	parts := make([]string, len(list))
+	for i, v := range list {
+		s, err := jsonScalar(v)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(s, q.delimiterOR) || strings.Contains(s, q.delimiterIN) {
+			return "", ErrBadFormat
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, q.delimiterIN), nil
+}
+
+// jsonScalar returns JSON string, number, boolean or null as value of query
+func jsonScalar(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return NULL, nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	default:
+		return "", ErrBadFormat
+	}
+}
+
+// NewParseJSON creates new Query instance and parses JSON document data, see ParseJSON
+func NewParseJSON(data []byte, v Validations) (*Query, error) {
+	query := New().SetValidations(v)
+	return query, query.ParseJSON(bytes.NewReader(data))
+}
@@ -0,0 +1,75 @@
+package rqp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSON(t *testing.T) {
+	validations := Validations{
+		"fields":      In("id", "name"),
+		"sort":        In("id", "created_at"),
+		"age:int":     nil,
+		"id:int":      nil,
+		"name":        nil,
+		"active:bool": nil,
+		"deleted_at":  nil,
+		"price:float": nil,
+		"status":      In("a", "b"),
+		"created_at":  nil,
+	}
+
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.ParseJSON(strings.NewReader(`{
+		"filters": {
+			"age": {"gte": 18, "lt": 65},
+			"id": {"in": [1, 2, 3]},
+			"name": {"like": "tim*"},
+			"active": true,
+			"deleted_at": {"is": null},
+			"price": {"between": [9.5, 100]},
+			"status": "a"
+		},
+		"fields": ["id", "name"],
+		"sort": ["-created_at", "id"],
+		"limit": 20,
+		"offset": 40
+	}`)))
+	assert.Equal(t, "SELECT id, name FROM t WHERE active = ? AND age >= ? AND age < ? AND deleted_at IS NULL AND id IN (?, ?, ?) AND name LIKE ? AND price BETWEEN ? AND ? AND status = ? ORDER BY created_at DESC, id LIMIT 20 OFFSET 40", q.SQL("t"))
+	assert.Equal(t, []interface{}{true, 18, 65, 1, 2, 3, "tim%", 9.5, 100.0, "a"}, q.Args())
+
+	// strings with delimiter of IN are kept whole
+	q, err := NewParseJSON([]byte(`{"filters": {"name": "a,b"}}`), validations)
+	assert.NoError(t, err)
+	assert.Equal(t, "name = ?", q.Where())
+	assert.Equal(t, []interface{}{"a,b"}, q.Args())
+
+	// sort and fields could be strings
+	q, err = NewParseJSON([]byte(`{"sort": "-id", "fields": "id"}`), validations)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t ORDER BY id DESC", q.SQL("t"))
+
+	cases := []struct {
+		body string
+		err  string
+	}{
+		{body: `{"filters": {"age": {"gte": "a"}}}`, err: "age[gte]: bad format"},
+		{body: `{"filters": {"status": "c"}}`, err: "status: c: not in scope"},
+		{body: `{"filters": {"x": 1}}`, err: "x: filter not found"},
+		{body: `{"filters": {"id": {"in": []}}}`, err: "id[in]: empty value"},
+		{body: `{"filters": {"id": {"eq": {"a": 1}}}}`, err: "id[eq]: bad format"},
+		{body: `{"filters": [1]}`, err: "filters: bad format"},
+		{body: `{"limit": [[1]]}`, err: "limit: bad format"},
+		{body: `{"filters": `, err: "body: unexpected EOF: bad format"},
+		{body: `{"filters": {"name": "x|id=5"}}`, err: "name: bad format"},
+		{body: `{"filters": {"status": {"in": ["a,b", "c"]}}}`, err: "status[in]: bad format"},
+	}
+	for _, c := range cases {
+		t.Run(c.body, func(t *testing.T) {
+			_, err := NewParseJSON([]byte(c.body), validations)
+			assert.EqualError(t, err, c.err)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package rqp
+
+import "reflect"
+
+// built is WHERE statement and arguments built by Parse
+type built struct {
+	filters []filterState // states of filters which the statement is built for
+	where   string
+	args    []interface{}
+}
+
+// filterState is a filter with copy of its fields which change WHERE statement or arguments
+type filterState struct {
+	filter   *Filter
+	name     string
+	method   Method
+	value    interface{}
+	or       StateOR
+	disabled bool
+}
+
+// stateOf returns state of filter, lists of values are copied so changes of their elements are detected
+func stateOf(f *Filter) filterState {
+	return filterState{
+		filter:   f,
+		name:     f.Name,
+		method:   f.Method,
+		value:    copyValue(f.Value),
+		or:       f.OR,
+		disabled: f.Disabled,
+	}
+}
+
+// copyValue returns copy of slice value or the same value
+func copyValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice || v.IsNil() {
+		return value
+	}
+	c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(c, v)
+	return c.Interface()
+}
+
+// equal returns true if filter isn't changed since its state was taken
+func (s *filterState) equal(f *Filter) bool {
+	return s.filter == f &&
+		s.name == f.Name &&
+		s.method == f.Method &&
+		s.or == f.OR &&
+		s.disabled == f.Disabled &&
+		reflect.DeepEqual(s.value, f.Value)
+}
+
+// build builds WHERE statement and arguments once so Where() and Args() don't
+// rebuild them on every call
+func (q *Query) build() {
+	q.cache = nil
+	c := &built{
+		filters: make([]filterState, len(q.Filters)),
+		where:   q.Where(),
+		args:    q.Args(),
+	}
+	for i, f := range q.Filters {
+		c.filters[i] = stateOf(f)
+	}
+	q.cache = c
+}
+
+// invalidate drops WHERE statement and arguments built by Parse.
+// It must be called by methods which change options of statement.
+func (q *Query) invalidate() {
+	q.cache = nil
+}
+
+// cached returns statement built by Parse if filters weren't added, removed or changed since that
+func (q *Query) cached() (*built, bool) {
+	c := q.cache
+	if c == nil || len(c.filters) != len(q.Filters) {
+		return nil, false
+	}
+	for i := range c.filters {
+		if !c.filters[i].equal(q.Filters[i]) {
+			return nil, false
+		}
+	}
+	return c, true
+}
@@ -0,0 +1,75 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCache(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "name": nil, "s": nil})
+	assert.NoError(t, q.SetUrlString("?id[in]=1,2,3&name=tim&s[like]=a*"))
+	assert.NoError(t, q.Parse())
+
+	_, ok := q.cached()
+	assert.True(t, ok)
+	assert.Equal(t, "id IN (?, ?, ?) AND name = ? AND s LIKE ?", q.Where())
+	assert.Equal(t, []interface{}{1, 2, 3, "tim", "a%"}, q.Args())
+
+	// cached statement isn't rebuilt
+	allocs := testing.AllocsPerRun(10, func() {
+		q.Where()
+		q.Args()
+	})
+	assert.Zero(t, allocs)
+
+	// appending to arguments doesn't change the cache
+	args := append(q.Args(), "x")
+	assert.Len(t, args, 6)
+	assert.Len(t, q.Args(), 5)
+
+	// filters are changed
+	q.AddFilter("age", GT, 10)
+	assert.Equal(t, "id IN (?, ?, ?) AND name = ? AND s LIKE ? AND age > ?", q.Where())
+	assert.Equal(t, []interface{}{1, 2, 3, "tim", "a%", 10}, q.Args())
+
+	assert.NoError(t, q.RemoveFilter("age"))
+	assert.NoError(t, q.DisableFilter("name"))
+	assert.Equal(t, "id IN (?, ?, ?) AND s LIKE ?", q.Where())
+	assert.Equal(t, []interface{}{1, 2, 3, "a%"}, q.Args())
+
+	// filters are changed in place
+	assert.NoError(t, q.Parse())
+	f, err := q.GetFilter("name")
+	assert.NoError(t, err)
+	f.Value = "bob"
+	assert.Equal(t, []interface{}{1, 2, 3, "bob", "a%"}, q.Args())
+	f.Method = NE
+	assert.Equal(t, "id IN (?, ?, ?) AND name != ? AND s LIKE ?", q.Where())
+	f.Disabled = true
+	assert.Equal(t, "id IN (?, ?, ?) AND s LIKE ?", q.Where())
+	assert.Equal(t, []interface{}{1, 2, 3, "a%"}, q.Args())
+	f, err = q.GetFilter("id")
+	assert.NoError(t, err)
+	f.Value.([]int)[0] = 42
+	assert.Equal(t, []interface{}{42, 2, 3, "a%"}, q.Args())
+
+	// options are changed
+	assert.NoError(t, q.Parse())
+	q.SetPlaceholder(PlaceholderDollar)
+	assert.Equal(t, "id IN ($1, $2, $3) AND name = $4 AND s LIKE $5", q.Where())
+	q.SetDialect(DialectSQLite)
+	assert.Equal(t, `id IN ($1, $2, $3) AND name = $4 AND s LIKE $5 ESCAPE '\'`, q.Where())
+
+	// relations are changed
+	assert.NoError(t, q.Parse())
+	q.SetRelations(map[string]Relation{"author": {Table: "users", ForeignKey: "author_id"}})
+	_, ok = q.cached()
+	assert.False(t, ok)
+
+	// failed parse doesn't keep the cache
+	assert.NoError(t, q.SetUrlString("?id=a"))
+	assert.Error(t, q.Parse())
+	_, ok = q.cached()
+	assert.False(t, ok)
+}
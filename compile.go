@@ -0,0 +1,70 @@
+package rqp
+
+import "sort"
+
+// compiledFilter is index of keys of validations of one filter
+type compiledFilter struct {
+	typ     string            // type tag, eg. "int"
+	key     string            // key of validation of the whole filter
+	methods map[Method]string // keys of validations of methods, eg. "age:gte"
+	allowed map[Method]bool   // methods allowed by "methods(...)" tag
+}
+
+// compiledValidations is index of validations by names of filters.
+// Keys are split once and validations are looked up by name in O(1) while parsing.
+type compiledValidations struct {
+	filters map[string]*compiledFilter
+}
+
+// compileValidations builds index of validations. Keys are handled in sorted order,
+// so the first type tag of the name is used if there are several ones.
+func compileValidations(validations Validations) *compiledValidations {
+	keys := make([]string, 0, len(validations))
+	for k := range validations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	c := &compiledValidations{
+		filters: make(map[string]*compiledFilter, len(validations)),
+	}
+	for _, k := range keys {
+		name, typ, method := splitValidationKey(k)
+		f, ok := c.filters[name]
+		if !ok {
+			f = &compiledFilter{}
+			c.filters[name] = f
+		}
+		if f.typ == "" {
+			f.typ = typ
+		}
+		if method != "" {
+			if f.methods == nil {
+				f.methods = make(map[Method]string)
+			}
+			if _, ok := f.methods[method]; !ok {
+				f.methods[method] = k
+			}
+		} else if f.key == "" {
+			f.key = k
+		}
+		if f.allowed == nil {
+			f.allowed, _ = allowedMethodsTag(k)
+		}
+	}
+	return c
+}
+
+// compile builds index of validations if it isn't built yet. The index is dropped
+// by AddValidation, RemoveValidation and SetValidations.
+func (q *Query) compile() *compiledValidations {
+	if q.index == nil {
+		q.index = compileValidations(q.validations)
+	}
+	return q.index
+}
+
+// compiled returns index of validations of filter name or nil if there are no validations
+func (q *Query) compiled(name string) *compiledFilter {
+	return q.compile().filters[name]
+}
@@ -0,0 +1,70 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileValidations(t *testing.T) {
+	min := func(interface{}) error { return nil }
+	c := compileValidations(Validations{
+		"id:int":             nil,
+		"age:int":            nil,
+		"age:gte":            min,
+		"status:methods(eq)": nil,
+	})
+
+	assert.Equal(t, 3, len(c.filters))
+	assert.Equal(t, &compiledFilter{typ: "int", key: "id:int"}, c.filters["id"])
+	assert.Equal(t, &compiledFilter{typ: "int", key: "age:int", methods: map[Method]string{GTE: "age:gte"}}, c.filters["age"])
+	assert.Equal(t, map[Method]bool{EQ: true}, c.filters["status"].allowed)
+}
+
+func TestCompiledIndexUpdate(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil})
+	assert.NoError(t, q.SetUrlString("?id=1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "int", q.detectType("id"))
+
+	// index is rebuilt when validations are changed
+	q.AddValidation("name", nil)
+	assert.NoError(t, q.SetUrlString("?id=1&name=tim"))
+	assert.NoError(t, q.Parse())
+
+	assert.NoError(t, q.RemoveValidation("name"))
+	assert.EqualError(t, q.Parse(), "name: filter not found")
+
+	q.SetValidations(Validations{"id": nil})
+	assert.NoError(t, q.SetUrlString("?id=a"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "string", q.detectType("id"))
+
+	// changes of the map in place aren't tracked, SetValidations rebuilds the index
+	v := Validations{"id:int": nil}
+	q.SetValidations(v)
+	assert.NoError(t, q.SetUrlString("?id=1&name=tim"))
+	assert.EqualError(t, q.Parse(), "name: filter not found")
+	v["name"] = nil
+	q.SetValidations(v)
+	assert.NoError(t, q.Parse())
+}
+
+func BenchmarkParse(b *testing.B) {
+	validations := Validations{"fields": In("id", "name"), "sort": In("id")}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		validations[name+":int"] = nil
+		validations[name+":gte"] = nil
+		validations[name+"_s"] = nil
+	}
+	f := NewFactory(validations)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := f.Get()
+		_ = q.SetUrlString("?fields=id,name&sort=-id&a=1&b[gte]=2&c[in]=1,2&d_s=x&e_s[like]=y*")
+		_ = q.Parse()
+		f.Put(q)
+	}
+}
@@ -0,0 +1,34 @@
+package rqp
+
+import "strings"
+
+// splitComputed splits computed field into aggregate expression and its alias:
+//
+//	count(*) AS total -> count(*), total
+//	min(price)        -> min(price), ""
+//
+// It returns false if the field isn't computed.
+func splitComputed(field string) (exp string, alias string, ok bool) {
+	exp = field
+	if i := strings.LastIndex(strings.ToUpper(field), " AS "); i != -1 {
+		exp, alias = strings.TrimSpace(field[:i]), strings.TrimSpace(field[i+len(" AS "):])
+		if !isIdentifier(alias) {
+			return "", "", false
+		}
+	}
+	if !isAggregate(exp) {
+		return "", "", false
+	}
+	return exp, alias, true
+}
+
+// computedColumn returns computed field for SELECT statement with quoted alias if names are quoted
+func (q *Query) computedColumn(exp, alias string) string {
+	if alias == "" {
+		return exp
+	}
+	if q.quoteNames {
+		alias = q.quote(alias)
+	}
+	return exp + " AS " + alias
+}
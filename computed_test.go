@@ -0,0 +1,58 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputedFields(t *testing.T) {
+	validations := Validations{
+		"fields": In("country", "count(*) AS total", "min(price)", "count(DISTINCT user_id)", "(select 1)"),
+		"group":  In("country"),
+	}
+
+	cases := []struct {
+		url string
+		sql string
+		err string
+	}{
+		{url: "?fields=count(*)%20AS%20total,min(price)", sql: "SELECT count(*) AS total, min(price) FROM t"},
+		{url: "?fields=country,count(DISTINCT%20user_id)&group=country", sql: "SELECT country, count(DISTINCT user_id) FROM t GROUP BY country"},
+		{url: "?fields=count(*)", err: "fields: count(*): not in scope"},
+		{url: "?fields=(select%201)", err: "fields: invalid identifier"},
+		{url: "?fields=-count(*)%20AS%20total", err: "fields: invalid identifier"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			URL, _ := url.Parse(c.url)
+			q := NewQV(URL.Query(), validations).SetStrictIdentifiers(true)
+			err := q.Parse()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.sql, q.SQL("t"))
+		})
+	}
+
+	// alias is quoted, expression isn't
+	q := New().SetQuoteIdentifiers(true)
+	q.Fields = []string{"id", "count(*) AS total", "max(price)"}
+	assert.Equal(t, `"id", count(*) AS "total", max(price)`, q.Select())
+
+	for field, ok := range map[string]bool{
+		"count(*)":             true,
+		"sum(amount) as total": true,
+		"count(*) AS 1x":       false,
+		"sum(a)-1":             false,
+		"sum((select 1))":      false,
+		"sum(a); drop":         false,
+		"users.sum(a)":         false,
+	} {
+		_, _, got := splitComputed(field)
+		assert.Equal(t, ok, got, field)
+	}
+}
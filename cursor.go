@@ -0,0 +1,217 @@
+package rqp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// cursor is an internal method of keyset pagination filter, see SetCursorPagination
+const cursor Method = "cursor"
+
+// keyset is a value of cursor filter
+type keyset struct {
+	cursor  string // encoded cursor from query part of URL
+	columns []string
+	desc    []bool
+	values  []interface{}
+	before  bool
+}
+
+// SetCursorPagination enables keyset pagination by "after" and "before" parameters
+// instead of OFFSET. Cursor contains values of sort columns of the last (or the first for "before")
+// row of the previous page and it's encoded by EncodeCursor. Sort is required:
+//
+//	?sort=-created_at,-id&limit=20&after=WyIyMDIwLTAxLTAxIiwxMF0
+//	WHERE (created_at, id) < (?, ?) ORDER BY created_at DESC, id DESC LIMIT 20
+//
+// For "before" sorting is reversed to take the nearest rows so the rows must be reversed by caller.
+// Cursor can't be used together with offset. It's always joined by AND like forced filters.
+func (q *Query) SetCursorPagination(enabled bool) *Query {
+	q.useCursor = enabled
+	return q
+}
+
+// isCursorKey returns true if key is a parameter of keyset pagination
+func (q *Query) isCursorKey(key string) bool {
+	return q.useCursor && (key == "after" || key == "before")
+}
+
+// EncodeCursor returns cursor for "after" or "before" parameters with values of sort columns
+func EncodeCursor(values ...interface{}) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor returns values of sort columns from cursor encoded by EncodeCursor.
+// Numbers are decoded as int if it's possible and as float64 otherwise.
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrBadFormat
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var values []interface{}
+	if err := d.Decode(&values); err != nil {
+		return nil, ErrBadFormat
+	}
+
+	for i, v := range values {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+		if x, err := n.Int64(); err == nil && int64(int(x)) == x {
+			values[i] = int(x)
+			continue
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return nil, ErrBadFormat
+		}
+		values[i] = f
+	}
+
+	return values, nil
+}
+
+// parseCursor adds filter of keyset pagination from "after" or "before" parameters
+func (q *Query) parseCursor(cursors map[string][]string) error {
+	if len(cursors) > 1 {
+		return errors.Wrap(ErrBadFormat, "after and before can't be used together")
+	}
+
+	for key, value := range cursors {
+		value, err := q.singleValue(value)
+		if err != nil {
+			return errors.Wrap(err, key)
+		}
+		if q.Offset > 0 {
+			return errors.Wrapf(ErrBadFormat, "%s: can't be used with offset", key)
+		}
+		if len(q.Sorts) == 0 {
+			return errors.Wrapf(ErrBadFormat, "%s: sort is required", key)
+		}
+
+		values, err := DecodeCursor(value[0])
+		if err != nil {
+			return errors.Wrap(err, key)
+		}
+		if len(values) != len(q.Sorts) {
+			return errors.Wrapf(ErrBadFormat, "%s: expected %d values, got %d", key, len(q.Sorts), len(values))
+		}
+
+		k := keyset{
+			cursor: value[0],
+			values: values,
+			before: key == "before",
+		}
+		for _, s := range q.Sorts {
+			k.columns = append(k.columns, q.sortColumn(s.By))
+			k.desc = append(k.desc, s.Desc)
+		}
+
+		q.cursorBefore = k.before
+		q.Filters = append(q.Filters, &Filter{
+			Key:    key,
+			Name:   key,
+			Method: cursor,
+			Value:  k,
+		})
+	}
+
+	return nil
+}
+
+// conditions returns filters of the Query without cursor of keyset pagination and forced filters
+// after the cursor. Cursor limits any result set so it's joined by AND like forced filters
+// even if filters are joined by OR (see SetTopLevelJoin).
+func (q *Query) conditions() (filters, forced []*Filter) {
+	var cursors []*Filter
+	for _, f := range q.Filters {
+		if f.Method == cursor {
+			cursors = append(cursors, f)
+		}
+	}
+	if len(cursors) == 0 {
+		return q.Filters, q.forcedFilters()
+	}
+
+	filters = make([]*Filter, 0, len(q.Filters)-len(cursors))
+	for _, f := range q.Filters {
+		if f.Method != cursor {
+			filters = append(filters, f)
+		}
+	}
+	return filters, append(cursors, q.forcedFilters()...)
+}
+
+// where returns keyset condition. Row values are compared if all columns are sorted
+// in the same direction: `(a, b) > (?, ?)`, otherwise the condition is expanded:
+// `(a > ? OR (a = ? AND b < ?))`
+func (k keyset) where(q *Query) string {
+	ops := make([]string, len(k.columns))
+	for i := range k.columns {
+		if k.desc[i] == k.before {
+			ops[i] = q.translate(GT)
+		} else {
+			ops[i] = q.translate(LT)
+		}
+	}
+
+	if len(k.columns) == 1 {
+		return fmt.Sprintf("%s %s ?", k.columns[0], ops[0])
+	}
+
+	if k.sameDirection() {
+		return fmt.Sprintf("(%s) %s (?%s)", strings.Join(k.columns, ", "), ops[0], strings.Repeat(", ?", len(k.columns)-1))
+	}
+
+	terms := make([]string, len(k.columns))
+	for i := range k.columns {
+		var and []string
+		for j := 0; j < i; j++ {
+			and = append(and, fmt.Sprintf("%s %s ?", k.columns[j], q.translate(EQ)))
+		}
+		and = append(and, fmt.Sprintf("%s %s ?", k.columns[i], ops[i]))
+		if len(and) == 1 {
+			terms[i] = and[0]
+		} else {
+			terms[i] = "(" + strings.Join(and, " AND ") + ")"
+		}
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+// args returns arguments of keyset condition in order of where
+func (k keyset) args() []interface{} {
+	if k.sameDirection() {
+		return k.values
+	}
+
+	var args []interface{}
+	for i := range k.values {
+		args = append(args, k.values[:i+1]...)
+	}
+	return args
+}
+
+// sameDirection returns true if all columns are sorted in the same direction
+func (k keyset) sameDirection() bool {
+	for i := range k.desc {
+		if k.desc[i] != k.desc[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	c, err := EncodeCursor("2020-01-01", 10, 1.5)
+	assert.NoError(t, err)
+
+	values, err := DecodeCursor(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"2020-01-01", 10, 1.5}, values)
+
+	_, err = DecodeCursor("%%%")
+	assert.Equal(t, ErrBadFormat, err)
+	_, err = DecodeCursor("e30") // {}
+	assert.Equal(t, ErrBadFormat, err)
+}
+
+func TestCursorPagination(t *testing.T) {
+	validations := Validations{"sort": In("created_at", "id"), "status": nil}
+	c, _ := EncodeCursor("2020-01-01", 10)
+	one, _ := EncodeCursor(10)
+
+	cases := []struct {
+		url   string
+		where string
+		order string
+		args  []interface{}
+	}{
+		{
+			url:   "?sort=-created_at,-id&limit=20&after=" + c,
+			where: "(created_at, id) < (?, ?)",
+			order: "created_at DESC, id DESC",
+			args:  []interface{}{"2020-01-01", 10},
+		},
+		{
+			url:   "?sort=created_at,id&after=" + c,
+			where: "(created_at, id) > (?, ?)",
+			order: "created_at, id",
+			args:  []interface{}{"2020-01-01", 10},
+		},
+		{
+			url:   "?sort=-created_at,-id&before=" + c,
+			where: "(created_at, id) > (?, ?)",
+			order: "created_at, id",
+			args:  []interface{}{"2020-01-01", 10},
+		},
+		{
+			url:   "?sort=-created_at,id&status=new&after=" + c,
+			where: "status = ? AND (created_at < ? OR (created_at = ? AND id > ?))",
+			order: "created_at DESC, id",
+			args:  []interface{}{"new", "2020-01-01", "2020-01-01", 10},
+		},
+		{
+			url:   "?sort=id&before=" + one,
+			where: "id < ?",
+			order: "id DESC",
+			args:  []interface{}{10},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations).SetCursorPagination(true)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.order, q.Order())
+			assert.Equal(t, c.args, q.Args())
+
+			// the same query is printed back
+			p := NewQV(nil, validations).SetCursorPagination(true)
+			assert.NoError(t, p.SetUrlString("?"+q.ToQueryString()))
+			assert.NoError(t, p.Parse())
+			assert.Equal(t, q.Where(), p.Where())
+		})
+	}
+
+	errCases := []struct {
+		url string
+		err string
+	}{
+		{url: "?after=" + c, err: "after: sort is required: bad format"},
+		{url: "?sort=id&after=" + c, err: "after: expected 1 values, got 2: bad format"},
+		{url: "?sort=id&offset=10&after=" + one, err: "after: can't be used with offset: bad format"},
+		{url: "?sort=id&after=" + one + "&before=" + one, err: "after and before can't be used together: bad format"},
+		{url: "?sort=id&after=" + one + "&after=" + one, err: "after: expected 1 value, got 2: bad format"},
+		{url: "?sort=id&after=bad", err: "after: bad format"},
+	}
+	for _, c := range errCases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations).SetCursorPagination(true)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			assert.EqualError(t, err, c.err)
+			assert.Equal(t, ErrBadFormat, errors.Cause(err))
+		})
+	}
+
+	// parameters are filters if cursor pagination isn't enabled
+	q := NewQV(nil, Validations{"after": nil})
+	assert.NoError(t, q.SetUrlString("?after=1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "after = ?", q.Where())
+
+	// placeholders are numbered
+	q = NewQV(nil, validations).SetCursorPagination(true).SetPlaceholder(PlaceholderDollar)
+	assert.NoError(t, q.SetUrlString("?sort=-created_at,-id&status=new&after="+c))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "status = $1 AND (created_at, id) < ($2, $3)", q.Where())
+	QueryEqual(t, q, q.Clone())
+
+	// cursor is joined by AND like forced filters
+	q = NewQV(nil, Validations{"sort": In("id"), "status": nil, "name": nil}).SetCursorPagination(true)
+	assert.NoError(t, q.SetUrlString("?sort=id&status=new&name=a&after="+one))
+	assert.NoError(t, q.Parse())
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	q.AddFilter("id", NE, 1).AddForcedFilter("tenant_id", EQ, 7)
+	assert.Equal(t, "(name = ? OR status = ? OR id != ?) AND id > ? AND tenant_id = ?", q.Where())
+	assert.Equal(t, []interface{}{"a", "new", 1, 10, 7}, q.Args())
+}
@@ -0,0 +1,23 @@
+package rqp
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Queryer executes SQL queries, it's implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Query executes the statement and arguments returned by SQLWithArgs(table).
+// Use SQL(table) and Args() if you need more control over the statement.
+func (q *Query) Query(db Queryer, table string) (*sql.Rows, error) {
+	return q.QueryContext(context.Background(), db, table)
+}
+
+// QueryContext executes the statement and arguments returned by SQLWithArgs(table)
+func (q *Query) QueryContext(ctx context.Context, db Queryer, table string) (*sql.Rows, error) {
+	query, args := q.SQLWithArgs(table)
+	return db.QueryContext(ctx, query, args...)
+}
@@ -0,0 +1,62 @@
+package rqp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordDriver is a fake database/sql driver which remembers the last query
+type recordDriver struct {
+	query string
+	args  []driver.NamedValue
+}
+
+func (d *recordDriver) Open(name string) (driver.Conn, error) { return &recordConn{d}, nil }
+
+type recordConn struct{ d *recordDriver }
+
+func (c *recordConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *recordConn) Close() error                              { return nil }
+func (c *recordConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+func (c *recordConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.query, c.d.args = query, args
+	return &recordRows{}, nil
+}
+
+type recordRows struct{}
+
+func (r *recordRows) Columns() []string              { return []string{"id"} }
+func (r *recordRows) Close() error                   { return nil }
+func (r *recordRows) Next(dest []driver.Value) error { return io.EOF }
+
+var testDriver = &recordDriver{}
+
+func init() {
+	sql.Register("rqp_record", testDriver)
+}
+
+func TestQuery(t *testing.T) {
+	db, err := sql.Open("rqp_record", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	q := New().AddFilter("id", IN, []int{1, 2}).AddFilter("name", EQ, "tim").SetLimit(10)
+
+	rows, err := q.Query(db, "users")
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, "SELECT * FROM users WHERE id IN (?, ?) AND name = ? LIMIT 10", testDriver.query)
+	assert.Len(t, testDriver.args, 3)
+	assert.Equal(t, int64(1), testDriver.args[0].Value)
+	assert.Equal(t, "tim", testDriver.args[2].Value)
+
+	rows, err = q.QueryContext(context.Background(), db, "accounts")
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, "SELECT * FROM accounts WHERE id IN (?, ?) AND name = ? LIMIT 10", testDriver.query)
+}
@@ -0,0 +1,61 @@
+package rqp
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// SetDeniedFields sets columns which are never allowed in fields, sort, group and filters
+// even if validations allow them, eg. `q.SetDeniedFields("password_hash")`.
+// Names are denied if they are, contain or are mapped (see SetNameMapping) to a denied column,
+// eg. `max(password_hash) AS h`, `users.password_hash` or `pw` mapped to `password_hash`.
+// Parse returns ErrFilterNotAllowed for them, excluded fields (see SetAvailableFields) skip them.
+func (q *Query) SetDeniedFields(fields ...string) *Query {
+	q.deniedFields = fields
+	return q
+}
+
+// checkDenied returns ErrFilterNotAllowed wrapped by name if the name is denied
+func (q *Query) checkDenied(name string) error {
+	if q.isDenied(name) {
+		return errors.Wrap(ErrFilterNotAllowed, name)
+	}
+	return nil
+}
+
+// isDenied returns true if name, its column of name mapping or expression of sort alias
+// refers to a denied column
+func (q *Query) isDenied(name string) bool {
+	if len(q.deniedFields) == 0 {
+		return false
+	}
+	if q.refersDenied(name) {
+		return true
+	}
+	if c, ok := q.nameMapping[name]; ok && q.refersDenied(c) {
+		return true
+	}
+	if exp, ok := q.sortAliases[name]; ok && q.refersDenied(exp) {
+		return true
+	}
+	return false
+}
+
+// refersDenied returns true if a word of expression is a denied column,
+// qualified words are compared with and without table: `users.password_hash`
+func (q *Query) refersDenied(exp string) bool {
+	words := strings.FieldsFunc(exp, func(c rune) bool {
+		return c != '_' && c != '.' && !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	for _, w := range words {
+		if stringInSlice(w, q.deniedFields) {
+			return true
+		}
+		if i := strings.LastIndexByte(w, '.'); i != -1 && stringInSlice(w[i+1:], q.deniedFields) {
+			return true
+		}
+	}
+	return false
+}
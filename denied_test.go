@@ -0,0 +1,62 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeniedFields(t *testing.T) {
+	any := func(interface{}) error { return nil }
+	validations := Validations{
+		"fields":             any,
+		"sort":               any,
+		"group":              any,
+		"id:int":             nil,
+		"password_hash":      nil,
+		"pw":                 nil,
+		"max(password_hash)": nil,
+	}
+
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?fields=id,password_hash", err: "fields: password_hash: filter are not allowed"},
+		{url: "?sort=-password_hash", err: "sort: password_hash: filter are not allowed"},
+		{url: "?group=password_hash", err: "group: password_hash: filter are not allowed"},
+		{url: "?password_hash=x", err: "password_hash: filter are not allowed"},
+		{url: "?id=1|password_hash[like]=a*", err: "password_hash[like]: filter are not allowed"},
+		{url: "?fields=id,max(password_hash)%20AS%20h", err: "fields: max(password_hash) AS h: filter are not allowed"},
+		{url: "?fields=users.password_hash", err: "fields: users.password_hash: filter are not allowed"},
+		{url: "?pw[like]=a*", err: "pw[like]: filter are not allowed"},
+		{url: "?fields=pw", err: "fields: pw: filter are not allowed"},
+		{url: "?sort=secret", err: "sort: secret: filter are not allowed"},
+		{url: "?having[max(password_hash)][gt]=a", err: "having[max(password_hash)][gt]: filter are not allowed"},
+		{url: "?fields=id&sort=id&id=1"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			URL, _ := url.Parse(c.url)
+			q := NewQV(URL.Query(), validations).
+				SetDeniedFields("password_hash").
+				SetNameMapping(Replacer{"pw": "password_hash"}).
+				SetSortAliases(map[string]string{"secret": "length(password_hash)"})
+			err := q.Parse()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	// denied fields are skipped by exclusion of available fields
+	q := NewQV(url.Values{"fields": {"-id"}}, validations).
+		SetAvailableFields("id", "name", "password_hash").
+		SetDeniedFields("password_hash")
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []string{"name"}, q.Fields)
+	QueryEqual(t, q, q.Clone())
+}
@@ -0,0 +1,186 @@
+package rqp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is a SQL dialect of generated statements
+type Dialect string
+
+// Dialects:
+const (
+	DialectDefault  Dialect = ""
+	DialectPostgres Dialect = "postgres"
+	DialectMySQL    Dialect = "mysql"
+	DialectSQLite   Dialect = "sqlite"
+	DialectMSSQL    Dialect = "mssql"
+)
+
+// mysqlMaxLimit is the biggest LIMIT in MySQL, it's used for OFFSET without LIMIT
+const mysqlMaxLimit = "18446744073709551615"
+
+// dialectMethods are SQL operators of methods which differ from translateMethods in dialect
+var dialectMethods = map[Dialect]map[Method]string{
+	DialectMySQL:  {REGEX: "REGEXP"},
+	DialectSQLite: {REGEX: "REGEXP"},
+}
+
+// SetDialect sets SQL dialect of generated statements
+func (q *Query) SetDialect(d Dialect) *Query {
+	q.invalidate()
+	q.dialect = d
+	return q
+}
+
+// Dialect returns SQL dialect set by SetDialect
+func (q *Query) Dialect() Dialect {
+	return q.dialect
+}
+
+// SetAnyIN sets behavior for IN filters to use `id = ANY(?)` with whole list of values
+// as a single argument instead of `id IN (?, ?, ?)`. So the statement is the same
+// for any number of values and prepared statements could be reused.
+// It works only with DialectPostgres, other dialects use IN.
+func (q *Query) SetAnyIN(a bool) *Query {
+	q.invalidate()
+	q.useAnyIN = a
+	return q
+}
+
+// SetArrayValuer sets function which wraps array arguments of `= ANY(?)` (see SetAnyIN)
+// and CONTAINS filters for database driver. pgx supports slices natively, lib/pq requires pq.Array:
+//
+//	q.SetArrayValuer(func(a interface{}) interface{} { return pq.Array(a) })
+func (q *Query) SetArrayValuer(fn func(interface{}) interface{}) *Query {
+	q.invalidate()
+	q.arrayValuer = fn
+	return q
+}
+
+// array returns array argument wrapped by function of SetArrayValuer
+func (q *Query) array(a interface{}) interface{} {
+	if q.arrayValuer != nil {
+		return q.arrayValuer(a)
+	}
+	return a
+}
+
+// SetQuoteIdentifiers sets quoting of names of filters, sorts and fields in statements
+// depending on dialect: "id" for DialectPostgres, DialectSQLite and DialectDefault,
+// `id` for DialectMySQL and [id] for DialectMSSQL. Qualified names are quoted by parts: "users"."id".
+// Columns of SetNameMapping which aren't identifiers (eg. expressions) are kept as is.
+func (q *Query) SetQuoteIdentifiers(quote bool) *Query {
+	q.invalidate()
+	q.quoteNames = quote
+	return q
+}
+
+// SetStrictIdentifiers sets validation of names of filters, sorts and fields in query part of URL.
+// Parse returns ErrInvalidIdentifier for names which aren't SQL identifiers, eg. "id", "users.id".
+func (q *Query) SetStrictIdentifiers(strict bool) *Query {
+	q.strictNames = strict
+	return q
+}
+
+// checkIdentifier returns ErrInvalidIdentifier if strict identifiers are enabled and name isn't identifier
+func (q *Query) checkIdentifier(name string) error {
+	if q.strictNames && !isIdentifier(name) {
+		return ErrInvalidIdentifier
+	}
+	return nil
+}
+
+// quote returns identifier quoted depending on dialect
+func (q *Query) quote(name string) string {
+	open, close := `"`, `"`
+	switch q.dialect {
+	case DialectMySQL:
+		open, close = "`", "`"
+	case DialectMSSQL:
+		open, close = "[", "]"
+	}
+
+	parts := strings.Split(name, ".")
+	for i := range parts {
+		parts[i] = open + strings.ReplaceAll(parts[i], close, close+close) + close
+	}
+	return strings.Join(parts, ".")
+}
+
+// anyIN returns true if IN filters must be built as `= ANY(?)`
+func (q *Query) anyIN() bool {
+	return q.useAnyIN && q.dialect == DialectPostgres
+}
+
+// lowerILIKE returns true if ILIKE and NOT ILIKE of method m must be built by LOWER()
+// for dialects which don't support them: `LOWER(name) LIKE LOWER(?)`.
+// Operator overridden by SetMethodSQL is used as is.
+func (q *Query) lowerILIKE(m Method) bool {
+	if _, ok := q.methods[m]; ok {
+		return false
+	}
+	switch q.dialect {
+	case DialectMySQL, DialectSQLite, DialectMSSQL:
+		return true
+	default:
+		return false
+	}
+}
+
+// likeEscape returns ESCAPE clause of LIKE for dialects which have no default escape character
+func (q *Query) likeEscape() string {
+	switch q.dialect {
+	case DialectSQLite, DialectMSSQL:
+		return ` ESCAPE '\'`
+	default:
+		return ""
+	}
+}
+
+// PAGINATION returns LIMIT and OFFSET statements depending on dialect.
+// Some dialects don't support OFFSET without LIMIT so the biggest limit is used:
+//
+// DialectDefault: ` LIMIT 10 OFFSET 20`, ` OFFSET 20`
+//
+// DialectPostgres: ` LIMIT 10 OFFSET 20`, ` LIMIT ALL OFFSET 20`
+//
+// DialectMySQL: ` LIMIT 10 OFFSET 20`, ` LIMIT 18446744073709551615 OFFSET 20`
+//
+// DialectSQLite: ` LIMIT 10 OFFSET 20`, ` LIMIT -1 OFFSET 20`
+//
+// DialectMSSQL: ` OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`, ` OFFSET 20 ROWS`
+// (MSSQL requires ORDER BY statement for OFFSET)
+func (q *Query) PAGINATION() string {
+	if q.dialect == DialectMSSQL {
+		switch {
+		case q.Limit > 0:
+			return fmt.Sprintf(" OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", q.offset(), q.Limit)
+		case q.Offset > 0:
+			return fmt.Sprintf(" OFFSET %d ROWS", q.Offset)
+		default:
+			return ""
+		}
+	}
+
+	if q.Limit <= 0 && q.Offset > 0 {
+		switch q.dialect {
+		case DialectPostgres:
+			return fmt.Sprintf(" LIMIT ALL OFFSET %d", q.Offset)
+		case DialectMySQL:
+			return fmt.Sprintf(" LIMIT %s OFFSET %d", mysqlMaxLimit, q.Offset)
+		case DialectSQLite:
+			return fmt.Sprintf(" LIMIT -1 OFFSET %d", q.Offset)
+		}
+	}
+
+	return q.LIMIT() + q.OFFSET()
+}
+
+// offset returns Offset which isn't lower then zero
+func (q *Query) offset() int {
+	if q.Offset > 0 {
+		return q.Offset
+	}
+	return 0
+}
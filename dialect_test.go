@@ -0,0 +1,167 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPAGINATION(t *testing.T) {
+	cases := []struct {
+		dialect            Dialect
+		limit, offset      int
+		expected, sqlOrder string
+	}{
+		{dialect: DialectDefault, expected: ""},
+		{dialect: DialectDefault, limit: 10, expected: " LIMIT 10"},
+		{dialect: DialectDefault, offset: 20, expected: " OFFSET 20"},
+		{dialect: DialectDefault, limit: 10, offset: 20, expected: " LIMIT 10 OFFSET 20"},
+		{dialect: DialectPostgres, offset: 20, expected: " LIMIT ALL OFFSET 20"},
+		{dialect: DialectPostgres, limit: 10, offset: 20, expected: " LIMIT 10 OFFSET 20"},
+		{dialect: DialectMySQL, offset: 20, expected: " LIMIT 18446744073709551615 OFFSET 20"},
+		{dialect: DialectMySQL, limit: 10, expected: " LIMIT 10"},
+		{dialect: DialectSQLite, offset: 20, expected: " LIMIT -1 OFFSET 20"},
+		{dialect: DialectMSSQL, expected: ""},
+		{dialect: DialectMSSQL, offset: 20, expected: " OFFSET 20 ROWS", sqlOrder: " ORDER BY (SELECT NULL)"},
+		{dialect: DialectMSSQL, limit: 10, expected: " OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", sqlOrder: " ORDER BY (SELECT NULL)"},
+		{dialect: DialectMSSQL, limit: 10, offset: 20, expected: " OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", sqlOrder: " ORDER BY (SELECT NULL)"},
+	}
+	for _, c := range cases {
+		q := New().SetDialect(c.dialect).SetLimit(c.limit).SetOffset(c.offset)
+		assert.Equal(t, c.expected, q.PAGINATION(), "%s %d %d", c.dialect, c.limit, c.offset)
+		assert.Equal(t, "SELECT * FROM test"+c.sqlOrder+c.expected, q.SQL("test"))
+	}
+
+	q := New().SetDialect(DialectMSSQL).SetLimit(10).AddSortBy("id", true)
+	assert.Equal(t, "SELECT * FROM test ORDER BY id DESC OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", q.SQL("test"))
+}
+
+func TestSetAnyIN(t *testing.T) {
+	q := New().
+		AddFilter("id", IN, []int{1, 2, 3}).
+		AddFilter("name", IN, "tim").
+		AddFilter("s", NIN, []string{"a", "b"}).
+		SetAnyIN(true)
+
+	// other dialects keep IN
+	assert.Equal(t, "id IN (?, ?, ?) AND name IN (?) AND s NOT IN (?, ?)", q.Where())
+	assert.Equal(t, []interface{}{1, 2, 3, "tim", "a", "b"}, q.Args())
+
+	q.SetDialect(DialectPostgres)
+	assert.Equal(t, "id = ANY(?) AND name = ANY(?) AND s NOT IN (?, ?)", q.Where())
+	assert.Equal(t, []interface{}{[]int{1, 2, 3}, []string{"tim"}, "a", "b"}, q.Args())
+}
+
+type testArray struct {
+	a interface{}
+}
+
+func TestSetArrayValuer(t *testing.T) {
+	q := New().
+		SetDialect(DialectPostgres).
+		SetAnyIN(true).
+		SetArrayValuer(func(a interface{}) interface{} { return testArray{a} }).
+		AddFilter("id", IN, []int{1, 2}).
+		AddFilter("tags", CONTAINS, []string{"go"}).
+		AddFilter("s", NIN, []string{"a"})
+
+	assert.Equal(t, "id = ANY(?) AND tags @> ?::text[] AND s NOT IN (?)", q.Where())
+	assert.Equal(t, []interface{}{testArray{[]int{1, 2}}, testArray{[]string{"go"}}, "a"}, q.Args())
+	assert.Equal(t, q.Args(), q.Clone().Args())
+}
+
+func TestSetQuoteIdentifiers(t *testing.T) {
+	validations := Validations{
+		"fields": In("id", "users.name"),
+		"sort":   In("id"),
+		"id:int": nil,
+		"name":   nil,
+	}
+
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{DialectDefault, `SELECT "id", "users"."name" FROM t WHERE "id" = ? AND "name" LIKE ? ORDER BY "id" DESC`},
+		{DialectPostgres, `SELECT "id", "users"."name" FROM t WHERE "id" = ? AND "name" LIKE ? ORDER BY "id" DESC`},
+		{DialectSQLite, `SELECT "id", "users"."name" FROM t WHERE "id" = ? AND "name" LIKE ? ESCAPE '\' ORDER BY "id" DESC`},
+		{DialectMySQL, "SELECT `id`, `users`.`name` FROM t WHERE `id` = ? AND `name` LIKE ? ORDER BY `id` DESC"},
+		{DialectMSSQL, `SELECT [id], [users].[name] FROM t WHERE [id] = ? AND [name] LIKE ? ESCAPE '\' ORDER BY [id] DESC`},
+	}
+	for _, c := range cases {
+		t.Run(string(c.dialect), func(t *testing.T) {
+			q := NewQV(nil, validations).SetDialect(c.dialect).SetQuoteIdentifiers(true)
+			assert.NoError(t, q.SetUrlString("?fields=id,users.name&sort=-id&id=1&name[like]=tim*"))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.expected, q.SQL("t"))
+		})
+	}
+
+	// quotes inside names are escaped
+	q := New().SetQuoteIdentifiers(true)
+	q.AddFilter(`id" = 1 OR "1`, EQ, 1)
+	assert.Equal(t, `"id"" = 1 OR ""1" = ?`, q.Where())
+	q.SetDialect(DialectMSSQL)
+	assert.Equal(t, `[id" = 1 OR "1] = ?`, q.Where())
+
+	// expressions of name mapping aren't quoted
+	q = New().SetQuoteIdentifiers(true).SetNameMapping(Replacer{"day": "DATE(created_at)", "author": "users.name"})
+	q.AddFilter("day", EQ, "2020-01-01").AddFilter("author", EQ, "tim")
+	assert.Equal(t, `DATE(created_at) = ? AND "users"."name" = ?`, q.Where())
+}
+
+func TestSetStrictIdentifiers(t *testing.T) {
+	any := func(interface{}) error { return nil }
+	validations := Validations{
+		"fields":   any,
+		"sort":     any,
+		"id--:int": nil,
+	}
+
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?fields=id,(select%201)", err: "fields: invalid identifier"},
+		{url: "?sort=-id)", err: "sort: invalid identifier"},
+		{url: "?id--=1", err: "id--: invalid identifier"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations).SetStrictIdentifiers(true)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.EqualError(t, q.Parse(), c.err)
+		})
+	}
+
+	q := NewQV(nil, Validations{"fields": any, "sort": any, "users.id:int": nil}).SetStrictIdentifiers(true)
+	assert.NoError(t, q.SetUrlString("?fields=id,users.name&sort=-users.id&users.id=1"))
+	assert.NoError(t, q.Parse())
+	QueryEqual(t, q, q.Clone())
+}
+
+func TestILIKEFallback(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{DialectDefault, "name ILIKE ? AND email NOT ILIKE ?"},
+		{DialectPostgres, "name ILIKE ? AND email NOT ILIKE ?"},
+		{DialectMySQL, "LOWER(name) LIKE LOWER(?) AND LOWER(email) NOT LIKE LOWER(?)"},
+		{DialectSQLite, `LOWER(name) LIKE LOWER(?) ESCAPE '\' AND LOWER(email) NOT LIKE LOWER(?) ESCAPE '\'`},
+		{DialectMSSQL, `LOWER(name) LIKE LOWER(?) ESCAPE '\' AND LOWER(email) NOT LIKE LOWER(?) ESCAPE '\'`},
+	}
+	for _, c := range cases {
+		t.Run(string(c.dialect), func(t *testing.T) {
+			q := New().SetDialect(c.dialect)
+			q.AddFilter("name", ILIKE, "*tim*").AddFilter("email", NILIKE, "*@example.com")
+			assert.Equal(t, c.expected, q.Where())
+			assert.Equal(t, []interface{}{"%tim%", "%@example.com"}, q.Args())
+		})
+	}
+
+	// overridden operator is used as is
+	q := New().SetDialect(DialectMySQL).SetMethodSQL(ILIKE, "LIKE")
+	q.AddFilter("name", ILIKE, "tim")
+	assert.Equal(t, "name LIKE ?", q.Where())
+}
@@ -0,0 +1,38 @@
+package rqp
+
+import "strconv"
+
+// parseDistinct parses "distinct" parameter: `distinct=true`.
+// It's allowed only if validations have "distinct" key: `"distinct": nil` allows any value,
+// validation func gets bool value.
+func (q *Query) parseDistinct(value []string) error {
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
+	validate, ok := q.validations["distinct"]
+	if !ok {
+		return ErrValidationNotFound
+	}
+
+	distinct, err := strconv.ParseBool(value[0])
+	if err != nil {
+		return ErrBadFormat
+	}
+
+	if validate != nil {
+		if err := validate(distinct); err != nil {
+			return err
+		}
+	}
+
+	q.Distinct = distinct
+	return nil
+}
+
+// SetDistinct sets DISTINCT selection of SELECT statement
+func (q *Query) SetDistinct(distinct bool) *Query {
+	q.Distinct = distinct
+	return q
+}
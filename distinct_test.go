@@ -0,0 +1,63 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinct(t *testing.T) {
+	validations := Validations{
+		"distinct":    nil,
+		"fields":      In("email", "name"),
+		"active:bool": nil,
+	}
+
+	cases := []struct {
+		url   string
+		sql   string
+		count string
+		err   string
+	}{
+		{
+			url:   "?distinct=true&fields=email&active=true",
+			sql:   "SELECT DISTINCT email FROM t WHERE active = ?",
+			count: "SELECT COUNT(*) FROM (SELECT DISTINCT email FROM t WHERE active = ?) AS distinct_rows",
+		},
+		{
+			url:   "?distinct=false&fields=email",
+			sql:   "SELECT email FROM t",
+			count: "SELECT COUNT(*) FROM t",
+		},
+		{url: "?distinct=yes", err: "distinct: bad format"},
+		{url: "?distinct=true&distinct=false", err: "distinct: expected 1 value, got 2: bad format"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			URL, _ := url.Parse(c.url)
+			q, err := NewParse(URL.Query(), validations)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.sql, q.SQL("t"))
+			assert.Equal(t, c.count, q.CountSQL("t"))
+		})
+	}
+
+	// distinct is allowed only by validations
+	_, err := NewParse(url.Values{"distinct": {"true"}}, Validations{})
+	assert.EqualError(t, err, "distinct: validation not found")
+
+	// validation gets bool value
+	_, err = NewParse(url.Values{"distinct": {"true"}}, Validations{"distinct": In(false)})
+	assert.EqualError(t, err, "distinct: true: not in scope")
+
+	q := New().SetDistinct(true)
+	assert.Equal(t, "SELECT DISTINCT *", q.SELECT())
+	assert.True(t, q.Clone().Distinct)
+	assert.Equal(t, "distinct=true", q.ToQueryString())
+	assert.False(t, q.Reset().Distinct)
+}
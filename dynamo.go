@@ -0,0 +1,201 @@
+package rqp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DynamoExpression is expressions of DynamoDB Query or Scan request
+// with placeholders of attribute names and values
+type DynamoExpression struct {
+	KeyCondition string                 // KeyConditionExpression, empty without keys
+	Filter       string                 // FilterExpression
+	Projection   string                 // ProjectionExpression of Fields
+	Names        map[string]string      // ExpressionAttributeNames, eg. "#n0": "age"
+	Values       map[string]interface{} // ExpressionAttributeValues, eg. ":v0": 18 (see attributevalue.MarshalMap)
+}
+
+// Dynamo returns expressions of DynamoDB for filters and fields of the Query.
+// Filters of key attributes (partition and sort keys) with methods which are allowed
+// in key condition (eq, gt, gte, lt, lte, between, starts) are in KeyCondition
+// unless filters are joined by top level OR, the rest is in Filter:
+//
+//	// ?user_id=7&created_at[gte]=2020-01-01&status[in]=a,b
+//	q.Dynamo("user_id", "created_at")
+//	// KeyCondition: #n0 = :v0 AND #n1 >= :v1
+//	// Filter:       #n2 IN (:v2, :v3)
+//
+// is null and not null are attribute_not_exists and attribute_exists, starts and contains_str
+// are begins_with and contains. LIKE methods, regex and fts can't be expressed
+// so they are ErrMethodNotAllowed, raw filters too (see Groups).
+func (q *Query) Dynamo(keys ...string) (DynamoExpression, error) {
+	e := &dynamoExpression{
+		DynamoExpression: DynamoExpression{
+			Names:  make(map[string]string),
+			Values: make(map[string]interface{}),
+		},
+		names: make(map[string]string),
+	}
+
+	isKey := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		isKey[key] = true
+	}
+
+	groups, err := q.Groups()
+	if err != nil {
+		return DynamoExpression{}, err
+	}
+
+	var keyConditions, filters []string
+	for _, group := range groups {
+		if len(group) == 1 && isKey[q.Column(group[0].Name)] && dynamoKeyMethod(group[0].Method) && q.topLevelJoin() != "OR" {
+			c, err := e.condition(q, group[0])
+			if err != nil {
+				return DynamoExpression{}, err
+			}
+			keyConditions = append(keyConditions, c)
+			continue
+		}
+
+		or := make([]string, len(group))
+		for i, f := range group {
+			c, err := e.condition(q, f)
+			if err != nil {
+				return DynamoExpression{}, err
+			}
+			or[i] = c
+		}
+		if len(or) == 1 {
+			filters = append(filters, or[0])
+		} else {
+			filters = append(filters, "("+strings.Join(or, " OR ")+")")
+		}
+	}
+
+	filter := strings.Join(filters, " "+q.topLevelJoin()+" ")
+	if q.topLevelJoin() == "OR" && len(filters) > 1 && len(q.forcedFilters()) > 0 {
+		filter = "(" + filter + ")"
+	}
+	for _, f := range q.forcedFilters() {
+		c, err := e.condition(q, f)
+		if err != nil {
+			return DynamoExpression{}, err
+		}
+		if filter != "" {
+			filter += " AND "
+		}
+		filter += c
+	}
+
+	e.KeyCondition = strings.Join(keyConditions, " AND ")
+	e.Filter = filter
+
+	projection := make([]string, len(q.Fields))
+	for i, field := range q.Fields {
+		projection[i] = e.name(q.Column(field))
+	}
+	e.Projection = strings.Join(projection, ", ")
+
+	return e.DynamoExpression, nil
+}
+
+// dynamoKeyMethod returns true for methods of key condition expression
+func dynamoKeyMethod(m Method) bool {
+	switch m {
+	case EQ, GT, LT, GTE, LTE, BETWEEN, STARTS:
+		return true
+	default:
+		return false
+	}
+}
+
+// dynamoOperators are comparison operators of DynamoDB
+var dynamoOperators = map[Method]string{
+	EQ:  "=",
+	NE:  "<>",
+	GT:  ">",
+	LT:  "<",
+	GTE: ">=",
+	LTE: "<=",
+}
+
+// dynamoExpression builds DynamoExpression
+type dynamoExpression struct {
+	DynamoExpression
+	names map[string]string // placeholders of names
+}
+
+// name returns placeholders of attribute name, parts of nested path are separate names: `#n0.#n1`
+func (e *dynamoExpression) name(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		p, ok := e.names[part]
+		if !ok {
+			p = "#n" + strconv.Itoa(len(e.names))
+			e.names[part] = p
+			e.Names[p] = part
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, ".")
+}
+
+// value returns placeholder of value
+func (e *dynamoExpression) value(v interface{}) string {
+	p := ":v" + strconv.Itoa(len(e.Values))
+	e.Values[p] = v
+	return p
+}
+
+// condition returns condition expression of single filter
+func (e *dynamoExpression) condition(q *Query, f *Filter) (string, error) {
+	name := e.name(q.Column(f.Name))
+
+	args, err := q.FilterArgs(f)
+	if err != nil {
+		return "", errors.Wrap(err, f.Name)
+	}
+
+	switch f.Method {
+	case EQ, NE, GT, LT, GTE, LTE:
+		return name + " " + dynamoOperators[f.Method] + " " + e.value(args[0]), nil
+	case IS:
+		return "attribute_not_exists(" + name + ")", nil
+	case NOT:
+		return "attribute_exists(" + name + ")", nil
+	case IN, NIN:
+		values := make([]string, len(args))
+		for i, arg := range args {
+			values[i] = e.value(arg)
+		}
+		in := name + " IN (" + strings.Join(values, ", ") + ")"
+		if f.Method == NIN {
+			return "NOT (" + in + ")", nil
+		}
+		return in, nil
+	case STARTS:
+		return "begins_with(" + name + ", " + e.value(f.Value) + ")", nil
+	case CONTAINS_STR:
+		return "contains(" + name + ", " + e.value(f.Value) + ")", nil
+	case CONTAINS:
+		all := make([]string, len(args))
+		for i, arg := range args {
+			all[i] = "contains(" + name + ", " + e.value(arg) + ")"
+		}
+		if len(all) == 1 {
+			return all[0], nil
+		}
+		return "(" + strings.Join(all, " AND ") + ")", nil
+	case BETWEEN:
+		return name + " BETWEEN " + e.value(args[0]) + " AND " + e.value(args[1]), nil
+	case RANGE:
+		from, to := f.Value.(Range).methods()
+		return "(" + name + " " + dynamoOperators[from] + " " + e.value(args[0]) + " AND " +
+			name + " " + dynamoOperators[to] + " " + e.value(args[1]) + ")", nil
+	default:
+		return "", errors.Wrap(ErrMethodNotAllowed, f.Name)
+	}
+}
@@ -0,0 +1,87 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamo(t *testing.T) {
+	validations := Validations{
+		"fields":     In("id", "name"),
+		"userId:int": nil,
+		"created_at": nil,
+		"status":     nil,
+		"name":       nil,
+		"tags":       nil,
+		"price:int":  nil,
+		"deleted_at": nil,
+	}
+
+	q := NewQV(nil, validations).SetNameMapping(Replacer{"userId": "user_id"})
+	assert.NoError(t, q.SetUrlString("?userId=7&created_at[gte]=2020-01-01&status[in]=a,b&name[starts]=ti|deleted_at[is]=null&fields=id,name"))
+	assert.NoError(t, q.Parse())
+	q.AddForcedFilter("tenant.id", EQ, 1)
+
+	e, err := q.Dynamo("user_id", "created_at")
+	assert.NoError(t, err)
+	assert.Equal(t, DynamoExpression{
+		KeyCondition: "#n0 >= :v0 AND #n4 = :v4",
+		Filter:       "(begins_with(#n1, :v1) OR attribute_not_exists(#n2)) AND #n3 IN (:v2, :v3) AND #n5.#n6 = :v5",
+		Projection:   "#n6, #n1",
+		Names: map[string]string{
+			"#n0": "created_at", "#n1": "name", "#n2": "deleted_at", "#n3": "status",
+			"#n4": "user_id", "#n5": "tenant", "#n6": "id",
+		},
+		Values: map[string]interface{}{
+			":v0": "2020-01-01", ":v1": "ti", ":v2": "a", ":v3": "b", ":v4": 7, ":v5": 1,
+		},
+	}, e)
+
+	cases := []struct {
+		url    string
+		filter string
+		values map[string]interface{}
+	}{
+		{"?", "", map[string]interface{}{}},
+		{"?status[ne]=a", "#n0 <> :v0", map[string]interface{}{":v0": "a"}},
+		{"?status[nin]=a", "NOT (#n0 IN (:v0))", map[string]interface{}{":v0": "a"}},
+		{"?status[not]=null", "attribute_exists(#n0)", map[string]interface{}{}},
+		{"?name[contains_str]=a_b", "contains(#n0, :v0)", map[string]interface{}{":v0": "a_b"}},
+		{"?tags[contains]=a,b", "(contains(#n0, :v0) AND contains(#n0, :v1))", map[string]interface{}{":v0": "a", ":v1": "b"}},
+		{"?price[between]=1,5", "#n0 BETWEEN :v0 AND :v1", map[string]interface{}{":v0": 1, ":v1": 5}},
+		{"?price=[1,5)", "(#n0 >= :v0 AND #n0 < :v1)", map[string]interface{}{":v0": 1, ":v1": 5}},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			e, err := q.Dynamo()
+			assert.NoError(t, err)
+			assert.Equal(t, c.filter, e.Filter)
+			assert.Equal(t, c.values, e.Values)
+		})
+	}
+
+	// keys aren't key condition with top level OR
+	q = NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?userId=1&status=a"))
+	assert.NoError(t, q.Parse())
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	q.AddForcedFilter("price", GT, 0)
+	e, err = q.Dynamo("userId")
+	assert.NoError(t, err)
+	assert.Equal(t, "", e.KeyCondition)
+	assert.Equal(t, "(#n0 = :v0 OR #n1 = :v1) AND #n2 > :v2", e.Filter)
+
+	q = NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?name[like]=a*"))
+	assert.NoError(t, q.Parse())
+	_, err = q.Dynamo()
+	assert.EqualError(t, err, "name: method are not allowed")
+
+	// raw filters are SQL
+	_, err = New().AddFilterRaw("1 = 1").Dynamo()
+	assert.EqualError(t, err, "1 = 1: method are not allowed")
+}
@@ -0,0 +1,220 @@
+package rqp
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Elastic returns body of search request of Elasticsearch for the Query:
+// filters are bool query, Sorts are sort, Offset and Limit are from and size,
+// Fields are _source. Example:
+//
+//	// ?name[like]=tim*&age[gte]=18&status[in]=a,b&sort=-id&limit=10
+//	{
+//	  "query": {"bool": {"must": [
+//	    {"range": {"age": {"gte": 18}}},
+//	    {"wildcard": {"name": {"value": "tim*"}}},
+//	    {"terms": {"status": ["a", "b"]}}
+//	  ]}},
+//	  "sort": [{"id": {"order": "desc"}}],
+//	  "size": 10
+//	}
+//
+// Negative methods (ne, nin, nlike, is null) are must_not, OR statements are should,
+// forced filters are filter of bool query. LIKE methods are wildcard queries,
+// fts is match query with AND operator, regex is regexp query.
+// Raw filters (see Groups) and custom methods of SetMethodSQL are ErrMethodNotAllowed.
+func (q *Query) Elastic() (map[string]interface{}, error) {
+	var must, mustNot, should, filter []interface{}
+
+	groups, err := q.Groups()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		clause, negative, err := q.elasticGroup(group)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case q.topLevelJoin() == "OR":
+			if negative {
+				clause = elasticBool("must_not", clause)
+			}
+			should = append(should, clause)
+		case negative:
+			mustNot = append(mustNot, clause)
+		default:
+			must = append(must, clause)
+		}
+	}
+
+	for _, f := range q.forcedFilters() {
+		clause, negative, err := q.elasticClause(f)
+		if err != nil {
+			return nil, err
+		}
+		if negative {
+			clause = elasticBool("must_not", clause)
+		}
+		filter = append(filter, clause)
+	}
+
+	b := make(map[string]interface{})
+	if must != nil {
+		b["must"] = must
+	}
+	if mustNot != nil {
+		b["must_not"] = mustNot
+	}
+	if should != nil {
+		b["should"] = should
+		b["minimum_should_match"] = 1
+	}
+	if filter != nil {
+		b["filter"] = filter
+	}
+
+	body := map[string]interface{}{}
+	if len(b) == 0 {
+		body["query"] = map[string]interface{}{"match_all": map[string]interface{}{}}
+	} else {
+		body["query"] = map[string]interface{}{"bool": b}
+	}
+
+	if len(q.Sorts) > 0 {
+		sort := make([]interface{}, len(q.Sorts))
+		for i, s := range q.Sorts {
+			order := "asc"
+			if s.Desc {
+				order = "desc"
+			}
+			sort[i] = map[string]interface{}{q.Column(s.By): map[string]interface{}{"order": order}}
+		}
+		body["sort"] = sort
+	}
+	if len(q.Fields) > 0 {
+		source := make([]string, len(q.Fields))
+		for i, field := range q.Fields {
+			source[i] = q.Column(field)
+		}
+		body["_source"] = source
+	}
+	if q.Offset > 0 {
+		body["from"] = q.Offset
+	}
+	if q.Limit > 0 {
+		body["size"] = q.Limit
+	}
+
+	return body, nil
+}
+
+// elasticGroup returns clause of OR statement, single filter is returned as its clause
+func (q *Query) elasticGroup(group []*Filter) (clause map[string]interface{}, negative bool, err error) {
+	if len(group) == 1 {
+		return q.elasticClause(group[0])
+	}
+
+	should := make([]interface{}, len(group))
+	for i, f := range group {
+		c, negative, err := q.elasticClause(f)
+		if err != nil {
+			return nil, false, err
+		}
+		if negative {
+			c = elasticBool("must_not", c)
+		}
+		should[i] = c
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{
+		"should":               should,
+		"minimum_should_match": 1,
+	}}, false, nil
+}
+
+// elasticClause returns query of single filter, negative is true for must_not queries
+func (q *Query) elasticClause(f *Filter) (clause map[string]interface{}, negative bool, err error) {
+	name := q.Column(f.Name)
+
+	args, err := q.FilterArgs(f)
+	if err != nil {
+		return nil, false, errors.Wrap(err, f.Name)
+	}
+
+	field := func(query string, value interface{}) map[string]interface{} {
+		return map[string]interface{}{query: map[string]interface{}{name: value}}
+	}
+
+	switch f.Method {
+	case EQ, NE:
+		return field("term", args[0]), f.Method == NE, nil
+	case GT, LT, GTE, LTE:
+		return field("range", map[string]interface{}{strings.ToLower(string(f.Method)): args[0]}), false, nil
+	case IS, NOT:
+		return map[string]interface{}{"exists": map[string]interface{}{"field": name}}, f.Method == IS, nil
+	case IN, NIN:
+		return field("terms", args), f.Method == NIN, nil
+	case CONTAINS:
+		terms := make([]interface{}, len(args))
+		for i, arg := range args {
+			terms[i] = field("term", arg)
+		}
+		return elasticBool("must", terms...), false, nil
+	case LIKE, NLIKE, STARTS, ENDS, CONTAINS_STR:
+		value := map[string]interface{}{"value": elasticWildcard(args[0])}
+		return field("wildcard", value), f.Method == NLIKE, nil
+	case ILIKE, NILIKE:
+		value := map[string]interface{}{"value": elasticWildcard(args[0]), "case_insensitive": true}
+		return field("wildcard", value), f.Method == NILIKE, nil
+	case REGEX:
+		return field("regexp", args[0]), false, nil
+	case FTS:
+		return field("match", map[string]interface{}{"query": args[0], "operator": "and"}), false, nil
+	case BETWEEN:
+		return field("range", map[string]interface{}{"gte": args[0], "lte": args[1]}), false, nil
+	case RANGE:
+		from, to := f.Value.(Range).methods()
+		return field("range", map[string]interface{}{
+			strings.ToLower(string(from)): args[0],
+			strings.ToLower(string(to)):   args[1],
+		}), false, nil
+	default:
+		return nil, false, errors.Wrap(ErrMethodNotAllowed, f.Name)
+	}
+}
+
+// elasticBool returns bool query with clauses of occurrence type
+func elasticBool(occur string, clauses ...interface{}) map[string]interface{} {
+	return map[string]interface{}{"bool": map[string]interface{}{occur: clauses}}
+}
+
+// elasticWildcard converts LIKE pattern to pattern of wildcard query: `%` is `*`, `_` is `?`
+func elasticWildcard(pattern interface{}) string {
+	s, _ := pattern.(string)
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '%':
+			b.WriteByte('*')
+		case '_':
+			b.WriteByte('?')
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+			if c = s[i]; c == '*' || c == '?' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		case '*', '?':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
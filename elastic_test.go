@@ -0,0 +1,111 @@
+package rqp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElastic(t *testing.T) {
+	validations := Validations{
+		"fields":      In("id", "name"),
+		"sort":        In("id", "createdAt"),
+		"id:int":      nil,
+		"age:int":     nil,
+		"name":        nil,
+		"status":      nil,
+		"deleted_at":  nil,
+		"price:float": nil,
+		"body:fts":    nil,
+		"createdAt":   nil,
+	}
+
+	cases := []struct {
+		url  string
+		body string
+	}{
+		{
+			url:  "?",
+			body: `{"query": {"match_all": {}}}`,
+		},
+		{
+			url: "?name[like]=tim*&age[gte]=18&status[in]=a,b&sort=-createdAt,id&fields=id,name&limit=10&offset=20",
+			body: `{
+				"query": {"bool": {"must": [
+					{"range": {"age": {"gte": 18}}},
+					{"wildcard": {"name": {"value": "tim*"}}},
+					{"terms": {"status": ["a", "b"]}}
+				]}},
+				"sort": [{"created_at": {"order": "desc"}}, {"id": {"order": "asc"}}],
+				"_source": ["id", "name"],
+				"from": 20,
+				"size": 10
+			}`,
+		},
+		{
+			url: "?id[ne]=1&deleted_at[is]=null&name[ilike]=a_b*&status[nin]=c",
+			body: `{"query": {"bool": {
+				"must": [{"wildcard": {"name": {"value": "a_b*", "case_insensitive": true}}}],
+				"must_not": [
+					{"exists": {"field": "deleted_at"}},
+					{"term": {"id": 1}},
+					{"terms": {"status": ["c"]}}
+				]
+			}}}`,
+		},
+		{
+			url: "?status=a|status[not]=null|name[nlike]=x?*&price[between]=1,2.5&body[fts]=red%20car",
+			body: `{"query": {"bool": {"must": [
+				{"match": {"body": {"query": "red car", "operator": "and"}}},
+				{"range": {"price": {"gte": 1, "lte": 2.5}}},
+				{"bool": {"minimum_should_match": 1, "should": [
+					{"term": {"status": "a"}},
+					{"exists": {"field": "status"}},
+					{"bool": {"must_not": [{"wildcard": {"name": {"value": "x\\?*"}}}]}}
+				]}}
+			]}}}`,
+		},
+		{
+			url:  "?price=(1,5]",
+			body: `{"query": {"bool": {"must": [{"range": {"price": {"gt": 1, "lte": 5}}}]}}}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations).SetNameMapping(Replacer{"createdAt": "created_at"})
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			body, err := q.Elastic()
+			assert.NoError(t, err)
+			data, err := json.Marshal(body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, c.body, string(data))
+		})
+	}
+
+	// top level OR and forced filters
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?id=1&status[ne]=a"))
+	assert.NoError(t, q.Parse())
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	q.AddForcedFilter("tenant", NE, 7)
+	body, err := q.Elastic()
+	assert.NoError(t, err)
+	data, _ := json.Marshal(body)
+	assert.JSONEq(t, `{"query": {"bool": {
+		"minimum_should_match": 1,
+		"should": [{"term": {"id": 1}}, {"bool": {"must_not": [{"term": {"status": "a"}}]}}],
+		"filter": [{"bool": {"must_not": [{"term": {"tenant": 7}}]}}]
+	}}}`, string(data))
+
+	q = NewQV(nil, validations).SetMethodSQL("SIMILAR", "SIMILAR TO")
+	assert.NoError(t, q.SetUrlString("?name[similar]=a"))
+	assert.NoError(t, q.Parse())
+	_, err = q.Elastic()
+	assert.EqualError(t, err, "name: method are not allowed")
+
+	// raw filters are SQL
+	_, err = New().AddFilterRaw("1 = 1").Elastic()
+	assert.EqualError(t, err, "1 = 1: method are not allowed")
+}
@@ -1,5 +1,11 @@
 package rqp
 
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // Error special rqp.Error type
 type Error struct {
 	s string
@@ -26,4 +32,32 @@ var (
 	ErrFilterNotAllowed   = NewError("filter are not allowed")
 	ErrFilterNotFound     = NewError("filter not found")
 	ErrValidationNotFound = NewError("validation not found")
+	ErrTooManyFilters     = NewError("too many filters")
+	ErrTooManySortKeys    = NewError("too many sort keys")
+	ErrTooManyFields      = NewError("too many fields")
+	ErrTooManyValues      = NewError("too many values")
+	ErrValueTooLong       = NewError("value too long")
+	ErrInvalidIdentifier  = NewError("invalid identifier")
+	ErrLeadingWildcard    = NewError("leading wildcard is not allowed")
+	ErrNotQualified       = NewError("relations are joined, query must be qualified by table")
+
+	// errSkipFilter is used internally to skip filter without error
+	errSkipFilter = NewError("skip filter")
 )
+
+// ErrorDetails splits error returned by Parse into key of query part of URL and cause of the error:
+//
+//	"id: bad format" -> "id", ErrBadFormat
+//
+// Key is empty for errors which aren't related to a key.
+func ErrorDetails(err error) (key string, cause error) {
+	cause = errors.Cause(err)
+	if cause == err {
+		return "", cause
+	}
+	msg := err.Error()
+	if i := strings.Index(msg, ": "); i > 0 && !strings.ContainsAny(msg[:i], " \t") {
+		key = msg[:i]
+	}
+	return key, cause
+}
@@ -0,0 +1,45 @@
+package rqp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorDetails(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "status": In("a", "b")})
+
+	cases := []struct {
+		url   string
+		key   string
+		cause error
+	}{
+		{url: "?id=a", key: "id", cause: ErrBadFormat},
+		{url: "?id[like]=1", key: "id[like]", cause: ErrMethodNotAllowed},
+		{url: "?name=1", key: "name", cause: ErrFilterNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			assert.NoError(t, q.SetUrlString(c.url))
+			key, cause := ErrorDetails(q.Parse())
+			assert.Equal(t, c.key, key)
+			assert.Equal(t, c.cause, cause)
+		})
+	}
+
+	// errors of validation functions are causes too
+	assert.NoError(t, q.SetUrlString("?status=c"))
+	key, cause := ErrorDetails(q.Parse())
+	assert.Equal(t, "status", key)
+	assert.Equal(t, ErrNotInScope, cause)
+
+	key, cause = ErrorDetails(ErrTooManyFilters)
+	assert.Equal(t, "", key)
+	assert.Equal(t, ErrTooManyFilters, cause)
+
+	err := errors.New("other")
+	key, cause = ErrorDetails(err)
+	assert.Equal(t, "", key)
+	assert.Equal(t, err, cause)
+}
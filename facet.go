@@ -0,0 +1,93 @@
+package rqp
+
+import "strings"
+
+// Facet is a statement which counts rows matched by filters for each value of field
+type Facet struct {
+	Field string
+	SQL   string
+	Args  []interface{}
+}
+
+// Facets returns statements which count rows matched by filters of the Query
+// for each value of fields, eg. for counts of values of filters on listing pages.
+// Sorts, pagination and fields of the Query aren't used.
+//
+// Statement example: `SELECT status, COUNT(*) FROM table WHERE age > ? GROUP BY status ORDER BY COUNT(*) DESC`
+func (q *Query) Facets(table string, fields ...string) []Facet {
+	if q.joined() {
+		return q.Qualified(table).Facets(table, fields...)
+	}
+
+	join, where, args := q.JOIN(table), q.WHERE(), q.Args()
+
+	facets := make([]Facet, len(fields))
+	for i, field := range fields {
+		column := q.column(field)
+		facets[i] = Facet{
+			Field: field,
+			SQL:   "SELECT " + column + ", COUNT(*) FROM " + table + join + where + " GROUP BY " + column + " ORDER BY COUNT(*) DESC",
+			Args:  args,
+		}
+	}
+	return facets
+}
+
+// FacetsSQL returns one statement of all facets (see Facets) joined by UNION ALL and arguments for it.
+// Rows are name of field, value as string and number of rows: `facet, value, count`.
+//
+// Statement example:
+//
+//	SELECT 'status' AS facet, CAST(status AS VARCHAR(255)) AS value, COUNT(*) AS count FROM table WHERE age > ? GROUP BY status
+//	UNION ALL SELECT 'city' AS facet, ...
+//	ORDER BY facet, count DESC
+func (q *Query) FacetsSQL(table string, fields ...string) (string, []interface{}) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+	if q.joined() {
+		return q.Qualified(table).FacetsSQL(table, fields...)
+	}
+
+	join, args := q.JOIN(table), q.Args()
+	all := make([]interface{}, 0, len(args)*len(fields))
+
+	var b strings.Builder
+	n := 1
+	for i, field := range fields {
+		column := q.column(field)
+		where, next := q.whereFrom(n)
+		n = next
+		all = append(all, args...)
+
+		if i > 0 {
+			b.WriteString(" UNION ALL ")
+		}
+		b.WriteString("SELECT '")
+		b.WriteString(strings.ReplaceAll(field, "'", "''"))
+		b.WriteString("' AS facet, CAST(")
+		b.WriteString(column)
+		b.WriteString(" AS ")
+		b.WriteString(q.textType())
+		b.WriteString(") AS value, COUNT(*) AS count FROM ")
+		b.WriteString(table)
+		b.WriteString(join)
+		if len(where) > 0 {
+			b.WriteString(" WHERE ")
+			b.WriteString(where)
+		}
+		b.WriteString(" GROUP BY ")
+		b.WriteString(column)
+	}
+	b.WriteString(" ORDER BY facet, count DESC")
+
+	return b.String(), all
+}
+
+// textType returns type of values of facets depending on dialect
+func (q *Query) textType() string {
+	if q.dialect == DialectMySQL {
+		return "CHAR"
+	}
+	return "VARCHAR(255)"
+}
@@ -0,0 +1,49 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacets(t *testing.T) {
+	URL, _ := url.Parse("?age[gt]=18&status[in]=a,b&sort=-age&limit=10")
+	q, err := NewParse(URL.Query(), Validations{
+		"age:int": nil,
+		"status":  nil,
+		"sort":    In("age"),
+	})
+	assert.NoError(t, err)
+
+	facets := q.Facets("users", "status", "city")
+	assert.Equal(t, []Facet{
+		{
+			Field: "status",
+			SQL:   "SELECT status, COUNT(*) FROM users WHERE age > ? AND status IN (?, ?) GROUP BY status ORDER BY COUNT(*) DESC",
+			Args:  []interface{}{18, "a", "b"},
+		},
+		{
+			Field: "city",
+			SQL:   "SELECT city, COUNT(*) FROM users WHERE age > ? AND status IN (?, ?) GROUP BY city ORDER BY COUNT(*) DESC",
+			Args:  []interface{}{18, "a", "b"},
+		},
+	}, facets)
+
+	q.SetPlaceholder(PlaceholderDollar)
+	sql, args := q.FacetsSQL("users", "status", "city")
+	assert.Equal(t, "SELECT 'status' AS facet, CAST(status AS VARCHAR(255)) AS value, COUNT(*) AS count FROM users WHERE age > $1 AND status IN ($2, $3) GROUP BY status"+
+		" UNION ALL SELECT 'city' AS facet, CAST(city AS VARCHAR(255)) AS value, COUNT(*) AS count FROM users WHERE age > $4 AND status IN ($5, $6) GROUP BY city"+
+		" ORDER BY facet, count DESC", sql)
+	assert.Equal(t, []interface{}{18, "a", "b", 18, "a", "b"}, args)
+
+	// without filters
+	q = New().SetDialect(DialectMySQL)
+	sql, args = q.FacetsSQL("users", "status")
+	assert.Equal(t, "SELECT 'status' AS facet, CAST(status AS CHAR) AS value, COUNT(*) AS count FROM users GROUP BY status ORDER BY facet, count DESC", sql)
+	assert.Empty(t, args)
+
+	sql, args = q.FacetsSQL("users")
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+}
@@ -0,0 +1,82 @@
+package rqp
+
+import (
+	"net/url"
+	"sync"
+)
+
+// Factory holds validations and options of parser once and creates parsers for requests,
+// so Validations aren't constructed on every request. It's safe for concurrent use.
+// Example:
+//
+//	var users = rqp.NewFactory(rqp.Validations{"id:int": nil}, func(q *rqp.Query) {
+//		q.SetDialect(rqp.DialectPostgres).SetMaxLimit(100)
+//	})
+//
+//	q := users.Get()
+//	defer users.Put(q)
+type Factory struct {
+	proto *Query
+	pool  sync.Pool
+}
+
+// NewFactory creates Factory of parsers with validations v and options set by configure functions
+func NewFactory(v Validations, configure ...func(q *Query)) *Factory {
+	proto := New().SetValidations(v)
+	for _, fn := range configure {
+		fn(proto)
+	}
+	// required marks are removed and validations are indexed once for all parsers
+	proto.requiredNames()
+	proto.compile()
+
+	f := &Factory{proto: proto}
+	f.pool.New = func() interface{} {
+		return f.proto.Clone()
+	}
+	return f
+}
+
+// New returns a new parser with validations and options of the factory.
+// Options of the parser could be changed without affecting the factory.
+func (f *Factory) New() *Query {
+	return f.proto.Clone()
+}
+
+// Parse returns a new parser with query q parsed
+func (f *Factory) Parse(q url.Values) (*Query, error) {
+	query := f.New().SetUrlQuery(q)
+	return query, query.Parse()
+}
+
+// Get returns parser from the pool. It must be returned by Put when statements
+// and arguments aren't used anymore. Options of the parser must not be changed,
+// use New for parsers with own options.
+func (f *Factory) Get() *Query {
+	return f.pool.Get().(*Query)
+}
+
+// Put resets parser and returns it to the pool
+func (f *Factory) Put(q *Query) {
+	q.Reset()
+	f.pool.Put(q)
+}
+
+// Reset clears parsed state of Query: query of URL, fields, sorts, filters, limit, offset and error.
+// Validations and options are kept, so the Query could be used for another request.
+func (q *Query) Reset() *Query {
+	q.cleanFilters()
+	q.query = nil
+	q.Fields = nil
+	q.Sorts = nil
+	q.Group = nil
+	q.Distinct = false
+	q.includes = nil
+	q.Offset = 0
+	q.Limit = 0
+	q.unknown = nil
+	q.cursorBefore = false
+	q.withDeleted = false
+	q.Error = nil
+	return q
+}
@@ -0,0 +1,69 @@
+package rqp
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFactory(t *testing.T) {
+	f := NewFactory(Validations{"id:int!": nil, "name": nil, "sort": In("id")}, func(q *Query) {
+		q.SetDialect(DialectPostgres).SetPlaceholder(PlaceholderDollar)
+	})
+
+	q, err := f.Parse(url.Values{"id": {"1"}, "name": {"tim"}, "sort": {"-id"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = $1 AND name = $2 ORDER BY id DESC", q.SQL("t"))
+	assert.Equal(t, []interface{}{1, "tim"}, q.Args())
+
+	// required filters are kept
+	_, err = f.Parse(url.Values{"name": {"tim"}})
+	assert.EqualError(t, err, "id: required")
+
+	// options of new parser don't affect the factory
+	q = f.New().SetPlaceholder(PlaceholderQuestion)
+	q.AddFilter("id", EQ, 1)
+	assert.Equal(t, "id = ?", q.Where())
+	q = f.New()
+	q.AddFilter("id", EQ, 1)
+	assert.Equal(t, "id = $1", q.Where())
+}
+
+func TestFactoryPool(t *testing.T) {
+	f := NewFactory(Validations{"id:int": nil, "name": nil})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				q := f.Get()
+				assert.Empty(t, q.Filters)
+				assert.NoError(t, q.SetUrlString("?id=1&name=tim"))
+				assert.NoError(t, q.Parse())
+				assert.Equal(t, "id = ? AND name = ?", q.Where())
+				f.Put(q)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestReset(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "fields": In("id"), "sort": In("id")}).SetDialect(DialectMySQL)
+	assert.NoError(t, q.SetUrlString("?id=1&fields=id&sort=id&limit=10&offset=5"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "SELECT id FROM t WHERE id = ? ORDER BY id LIMIT 10 OFFSET 5", q.SQL("t"))
+
+	q.Reset()
+	assert.Equal(t, "SELECT * FROM t", q.SQL("t"))
+	assert.Empty(t, q.Args())
+
+	// validations and options are kept
+	assert.NoError(t, q.SetUrlString("?id=2&offset=5"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "SELECT * FROM t WHERE id = ? LIMIT 18446744073709551615 OFFSET 5", q.SQL("t"))
+}
@@ -2,8 +2,14 @@ package rqp
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 type StateOR byte
@@ -22,89 +28,306 @@ type Filter struct {
 	Method Method // compare method, takes from Key (eg. EQ)
 	Value  interface{}
 	OR     StateOR
+
+	Disabled bool // filter is parsed but isn't added to WHERE statement (see DisableFilter)
 }
 
-// detectValidation
-// name - only name without method
-// validations - must be q.validations
-func detectValidation(name string, validations Validations) (ValidationFunc, bool) {
-
-	for k, v := range validations {
-		if strings.Contains(k, ":") {
-			split := strings.Split(k, ":")
-			if split[0] == name {
-				return v, true
-			}
-		} else if k == name {
-			return v, true
+// splitValidationKey splits key of validations into name of filter, type and method tags.
+//   age:int:gte -> "age", "int", GTE
+//   age:gte     -> "age", "", GTE
+//   age:int     -> "age", "int", ""
+func splitValidationKey(key string) (name, typ string, method Method) {
+	parts := strings.Split(key, ":")
+	name = parts[0]
+	for _, tag := range parts[1:] {
+		if tag == "required" {
+			continue
+		}
+		if _, ok := methodsTag(tag); ok {
+			continue
+		}
+		if _, ok := translateMethods[Method(strings.ToUpper(tag))]; ok {
+			method = Method(strings.ToUpper(tag))
+			continue
+		}
+		if typ == "" {
+			typ = tag
 		}
 	}
+	return
+}
 
+// detectValidation returns validation func for the method of filter if it defined
+// or validation func for the whole filter otherwise
+// name - only name without method
+func (q *Query) detectValidation(name string, method Method) (ValidationFunc, bool) {
+	c := q.compiled(name)
+	if c == nil {
+		return nil, false
+	}
+	if k, ok := c.methods[method]; ok {
+		return q.validations[k], true
+	}
+	if c.key != "" {
+		return q.validations[c.key], true
+	}
 	return nil, false
 }
 
-// detectType
-func detectType(name string, validations Validations) string {
-
-	for k := range validations {
-		if strings.Contains(k, ":") {
-			split := strings.Split(k, ":")
-			if split[0] == name {
-				switch split[1] {
-				case "int", "i":
-					return "int"
-				case "bool", "b":
-					return "bool"
-				default:
-					return "string"
-				}
-			}
+// isExplicitMethod returns true if validation key of filter name contains method
+// or the method is in allowed methods of the filter
+func (q *Query) isExplicitMethod(name string, method Method) bool {
+	c := q.compiled(name)
+	if c == nil {
+		return false
+	}
+	if _, ok := c.methods[method]; ok {
+		return true
+	}
+	return c.allowed[method]
+}
+
+// methodsTag parses tag of allowed methods, eg. "methods(eq,in)"
+func methodsTag(tag string) (map[Method]bool, bool) {
+	if !strings.HasPrefix(tag, "methods(") || !strings.HasSuffix(tag, ")") {
+		return nil, false
+	}
+	methods := make(map[Method]bool)
+	for _, s := range strings.Split(tag[len("methods("):len(tag)-1], ",") {
+		m := Method(strings.ToUpper(strings.TrimSpace(s)))
+		if alias, ok := methodAliases[m]; ok {
+			m = alias
+		}
+		methods[m] = true
+	}
+	return methods, true
+}
+
+// allowedMethodsTag returns methods allowed by "methods(...)" tag of validation key
+func allowedMethodsTag(key string) (map[Method]bool, bool) {
+	for _, tag := range strings.Split(key, ":")[1:] {
+		if methods, ok := methodsTag(strings.TrimSuffix(tag, "!")); ok {
+			return methods, true
 		}
 	}
+	return nil, false
+}
 
-	return "string"
+// allowedMethods returns methods allowed for filter name by "methods(...)" tag of validation key
+func (q *Query) allowedMethods(name string) (map[Method]bool, bool) {
+	c := q.compiled(name)
+	if c == nil || c.allowed == nil {
+		return nil, false
+	}
+	return c.allowed, true
 }
 
-func isNotNull(f *Filter) bool {
+// detectType returns type of values of filter name by type tag of validation key
+func (q *Query) detectType(name string) string {
+	c := q.compiled(name)
+	if c == nil {
+		return "string"
+	}
+
+	switch typ := c.typ; typ {
+	case "":
+		return "string"
+	case "int", "i":
+		return "int"
+	case "bool", "b":
+		return "bool"
+	case "float", "f":
+		return "float"
+	case "decimal":
+		return "decimal"
+	case "time", "date":
+		return typ
+	case "uuid":
+		return "uuid"
+	default:
+		if _, ok := enumValues(typ); ok {
+			return typ
+		}
+		if _, ok := registeredType(typ); ok {
+			return typ
+		}
+		return "string"
+	}
+}
+
+// isNullComparison returns true for IS NULL and IS NOT NULL filters which aren't validated
+func isNullComparison(f *Filter) bool {
 	s, ok := f.Value.(string)
 	if !ok {
 		return false
 	}
-	return f.Method == NOT && strings.ToUpper(s) == NULL
+	return (f.Method == IS || f.Method == NOT) && strings.ToUpper(s) == NULL
 }
 
+// newFilter creates a filter from url key and its value
 // rawKey - url key
-// value - must be one value (if need IN method then values must be separated by comma (,))
-func newFilter(rawKey string, value string, delimiter string, validations Validations) (*Filter, error) {
+// value - must be one value (if need IN method then values must be separated by q.delimiterIN)
+func (q *Query) newFilter(rawKey string, value string) (*Filter, error) {
 	f := &Filter{
 		Key: rawKey,
 	}
 
 	// set Key, Name, Method
-	if err := f.parseKey(rawKey); err != nil {
+	if err := f.parseKey(rawKey, q.methods); err != nil {
+		return nil, err
+	}
+
+	// aggregate expressions are filters of HAVING only, see parseHaving
+	if isAggregate(f.Name) {
+		return nil, ErrValidationNotFound
+	}
+
+	if q.isDenied(f.Name) {
+		return nil, ErrFilterNotAllowed
+	}
+
+	if err := q.checkIdentifier(f.Name); err != nil {
 		return nil, err
 	}
 
+	if err := q.setFilter(f, value); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// setFilter validates method and value of filter with parsed key and sets the value
+func (q *Query) setFilter(f *Filter, value string) error {
 	// detect have we validator func definition on this parameter or not
-	validate, ok := detectValidation(f.Name, validations)
+	validate, ok := q.detectValidation(f.Name, f.Method)
 	if !ok {
-		return nil, ErrValidationNotFound
+		return ErrValidationNotFound
+	}
+
+	if explicitMethods[f.Method] && !q.isExplicitMethod(f.Name, f.Method) {
+		return ErrMethodNotAllowed
+	}
+
+	if allowed, ok := q.allowedMethods(f.Name); ok && !allowed[f.Method] {
+		return ErrMethodNotAllowed
 	}
 
 	// detect type by key names in validations
-	valueType := detectType(f.Name, validations)
+	valueType := q.detectType(f.Name)
 
-	if err := f.parseValue(valueType, value, delimiter); err != nil {
-		return nil, err
+	if f.Method == EQ && isNumericType(valueType) && isRangeValue(value) {
+		if err := q.checkValueLength(value); err != nil {
+			return err
+		}
+		if err := f.setRange(q, valueType, value); err != nil {
+			return err
+		}
+	} else {
+		list, err := q.cleanEmptyValues(q.splitValue(f.Method, value))
+		if err != nil {
+			return err
+		}
+
+		if err := q.checkINSize(f.Method, list); err != nil {
+			return err
+		}
+
+		for _, v := range list {
+			if err := q.checkValueLength(v); err != nil {
+				return err
+			}
+		}
+
+		if err := f.parseValue(q, valueType, list); err != nil {
+			return err
+		}
 	}
 
-	if !isNotNull(f) && validate != nil {
+	if err := q.checkLike(f); err != nil {
+		return err
+	}
+
+	if !isNullComparison(f) && validate != nil {
 		if err := f.validate(validate); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return f, nil
+	return nil
+}
+
+// checkINSize returns ErrTooManyValues if list of IN, NIN or CONTAINS filter is longer than q.maxINSize
+func (q *Query) checkINSize(method Method, list []string) error {
+	switch method {
+	case IN, NIN, CONTAINS:
+		if q.maxINSize > 0 && len(list) > q.maxINSize {
+			return ErrTooManyValues
+		}
+	}
+	return nil
+}
+
+// cleanEmptyValues handles empty elements of list depending on q.emptyValue
+func (q *Query) cleanEmptyValues(list []string) ([]string, error) {
+	switch q.emptyValue {
+	case EmptyValueSkip:
+		clean := list[:0]
+		for _, v := range list {
+			if len(v) > 0 {
+				clean = append(clean, v)
+			}
+		}
+		if len(clean) == 0 {
+			return nil, errSkipFilter
+		}
+		return clean, nil
+	case EmptyValueError:
+		for _, v := range list {
+			if len(v) == 0 {
+				return nil, ErrEmptyValue
+			}
+		}
+	}
+	return list, nil
+}
+
+// checkValueLength returns ErrValueTooLong if value is longer then q.maxValueLen
+func (q *Query) checkValueLength(value string) error {
+	if q.maxValueLen > 0 && len(value) > q.maxValueLen {
+		return ErrValueTooLong
+	}
+	return nil
+}
+
+// isListMethod returns true for methods of lists of values
+func isListMethod(method Method) bool {
+	switch method {
+	case IN, NIN, CONTAINS, BETWEEN:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitValue splits value of filter of list method (IN, NIN, CONTAINS, BETWEEN) by q.delimiterIN
+// and trims every element if q.trimValues is set. Values of other methods are kept whole.
+func (q *Query) splitValue(method Method, value string) []string {
+	var list []string
+
+	if isListMethod(method) && strings.Contains(value, q.delimiterIN) {
+		list = strings.Split(value, q.delimiterIN)
+	} else {
+		list = append(list, value)
+	}
+
+	if q.trimValues {
+		for i := range list {
+			list[i] = strings.TrimSpace(list[i])
+		}
+	}
+
+	return list
 }
 
 func (f *Filter) validate(validate ValidationFunc) error {
@@ -124,11 +347,44 @@ func (f *Filter) validate(validate ValidationFunc) error {
 				return err
 			}
 		}
-	case int, bool, string:
+	case []float64:
+		for _, v := range f.Value.([]float64) {
+			err := validate(v)
+			if err != nil {
+				return err
+			}
+		}
+	case []time.Time:
+		for _, v := range f.Value.([]time.Time) {
+			err := validate(v)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, v := range f.Value.([]interface{}) {
+			err := validate(v)
+			if err != nil {
+				return err
+			}
+		}
+	case int, bool, string, float64, time.Time:
 		err := validate(f.Value)
 		if err != nil {
 			return err
 		}
+	case Range:
+		r := f.Value.(Range)
+		if err := validate(r.From); err != nil {
+			return err
+		}
+		if err := validate(r.To); err != nil {
+			return err
+		}
+	default: // values of registered types
+		if f.Value != nil {
+			return validate(f.Value)
+		}
 	}
 
 	return nil
@@ -136,7 +392,8 @@ func (f *Filter) validate(validate ValidationFunc) error {
 
 // parseKey parses key to set f.Name and f.Method
 //   id[eq] -> f.Name = "id", f.Method = EQ
-func (f *Filter) parseKey(key string) error {
+// custom are methods added by SetMethodSQL
+func (f *Filter) parseKey(key string, custom map[Method]string) error {
 
 	// default Method is EQ
 	f.Method = EQ
@@ -152,8 +409,13 @@ func (f *Filter) parseKey(key string) error {
 
 			if epos-spos > 0 {
 				f.Method = Method(strings.ToUpper(string(key[spos:epos])))
+				if m, ok := methodAliases[f.Method]; ok {
+					f.Method = m
+				}
 				if _, ok := translateMethods[f.Method]; !ok {
-					return ErrUnknownMethod
+					if _, ok := custom[f.Method]; !ok {
+						return ErrUnknownMethod
+					}
 				}
 			}
 		}
@@ -164,15 +426,23 @@ func (f *Filter) parseKey(key string) error {
 	return nil
 }
 
-// parseValue parses value depends on its type
-func (f *Filter) parseValue(valueType string, value string, delimiter string) error {
+// parseValue parses list of values depends on its type
+func (f *Filter) parseValue(q *Query, valueType string, list []string) error {
 
-	var list []string
+	// values of methods added by SetMethodSQL are parsed like EQ
+	if q.customMethod(f.Method) {
+		m := f.Method
+		f.Method = EQ
+		defer func() { f.Method = m }()
+	}
 
-	if strings.Contains(value, delimiter) && (f.Method == IN || f.Method == NIN) {
-		list = strings.Split(value, delimiter)
-	} else {
-		list = append(list, value)
+	// NULL comparisons are the same for all types
+	if f.Method == IS || f.Method == NOT {
+		return f.setString(list)
+	}
+
+	if values, ok := enumValues(valueType); ok {
+		return f.setEnum(values, list)
 	}
 
 	switch valueType {
@@ -186,7 +456,30 @@ func (f *Filter) parseValue(valueType string, value string, delimiter string) er
 		if err != nil {
 			return err
 		}
+	case "float":
+		err := f.setFloat(list)
+		if err != nil {
+			return err
+		}
+	case "decimal":
+		err := f.setDecimal(list)
+		if err != nil {
+			return err
+		}
+	case "time", "date":
+		err := f.setTime(q, valueType, list)
+		if err != nil {
+			return err
+		}
+	case "uuid":
+		err := f.setUUID(list)
+		if err != nil {
+			return err
+		}
 	default: // str, string and all other unknown types will handle as string
+		if conv, ok := registeredType(valueType); ok {
+			return f.setCustom(conv, list)
+		}
 		err := f.setString(list)
 		if err != nil {
 			return err
@@ -198,31 +491,109 @@ func (f *Filter) parseValue(valueType string, value string, delimiter string) er
 
 // Where returns condition expression
 func (f *Filter) Where() (string, error) {
+	return f.where(New())
+}
+
+// where returns condition expression with methods translated by q
+func (f *Filter) where(q *Query) (string, error) {
 	var exp string
+	name := q.column(f.Name)
 
 	switch f.Method {
-	case EQ, NE, GT, LT, GTE, LTE, LIKE, ILIKE, NLIKE, NILIKE:
-		exp = fmt.Sprintf("%s %s ?", f.Name, translateMethods[f.Method])
+	case EQ, NE, GT, LT, GTE, LTE:
+		exp = name + " " + q.translate(f.Method) + " ?"
+		return exp, nil
+	case LIKE, ILIKE, NLIKE, NILIKE, STARTS, ENDS, CONTAINS_STR:
+		if _, ok := f.Value.(string); !ok {
+			return exp, ErrMethodNotAllowed
+		}
+		if (f.Method == ILIKE || f.Method == NILIKE) && q.lowerILIKE(f.Method) {
+			op := q.translate(LIKE)
+			if f.Method == NILIKE {
+				op = q.translate(NLIKE)
+			}
+			exp = fmt.Sprintf("LOWER(%s) %s LOWER(?)", name, op) + q.likeEscape()
+			return exp, nil
+		}
+		exp = name + " " + q.translate(f.Method) + " ?" + q.likeEscape()
+		return exp, nil
+	case FTS:
+		if _, ok := f.Value.(string); !ok {
+			return exp, ErrMethodNotAllowed
+		}
+		return q.fullTextSearch(name), nil
+	case REGEX:
+		if _, ok := f.Value.(string); !ok {
+			return exp, ErrMethodNotAllowed
+		}
+		exp = fmt.Sprintf("%s %s ?", name, q.translate(f.Method))
 		return exp, nil
 	case IS, NOT:
 		if f.Value == NULL {
-			exp = fmt.Sprintf("%s %s NULL", f.Name, translateMethods[f.Method])
+			exp = fmt.Sprintf("%s %s NULL", name, q.translate(f.Method))
 			return exp, nil
 		}
 		return exp, ErrUnknownMethod
 	case IN, NIN:
-		exp = fmt.Sprintf("%s %s (?)", f.Name, translateMethods[f.Method])
+		if f.Method == IN && q.anyIN() {
+			exp = fmt.Sprintf("%s = ANY(?)", name)
+			return exp, nil
+		}
+		if n, ok := valuesCount(f.Value); ok {
+			if n == 0 {
+				// like in() for empty slice, the filter is skipped
+				return exp, ErrBadFormat
+			}
+			exp = name + " " + q.translate(f.Method) + " (" + strings.Repeat(", ?", n)[2:] + ")"
+			return exp, nil
+		}
+		exp = fmt.Sprintf("%s %s (?)", name, q.translate(f.Method))
 		exp, _, _ = in(exp, f.Value)
 		return exp, nil
+	case CONTAINS:
+		switch f.Value.(type) {
+		case []int:
+			exp = fmt.Sprintf("%s %s ?::int[]", name, q.translate(f.Method))
+		case []string:
+			exp = fmt.Sprintf("%s %s ?::text[]", name, q.translate(f.Method))
+		default:
+			return exp, ErrMethodNotAllowed
+		}
+		return exp, nil
+	case BETWEEN:
+		exp = fmt.Sprintf("%s %s ? AND ?", name, q.translate(f.Method))
+		return exp, nil
+	case RANGE:
+		r, ok := f.Value.(Range)
+		if !ok {
+			return exp, ErrBadFormat
+		}
+		from, to := r.methods()
+		exp = fmt.Sprintf("(%s %s ? AND %s %s ?)", name, q.translate(from), name, q.translate(to))
+		return exp, nil
+	case cursor:
+		k, ok := f.Value.(keyset)
+		if !ok {
+			return exp, ErrBadFormat
+		}
+		return k.where(q), nil
 	case raw:
 		return f.Name, nil
 	default:
+		if q.customMethod(f.Method) {
+			return fmt.Sprintf("%s %s ?", name, q.translate(f.Method)), nil
+		}
 		return exp, ErrUnknownMethod
 	}
 }
 
 // Args returns arguments slice depending on filter condition
 func (f *Filter) Args() ([]interface{}, error) {
+	return f.args(New())
+}
+
+// args returns arguments slice depending on filter condition and options of q
+func (f *Filter) args(q *Query) ([]interface{}, error) {
 
 	args := make([]interface{}, 0)
 
@@ -237,26 +608,101 @@ func (f *Filter) Args() ([]interface{}, error) {
 		}
 		return nil, ErrUnknownMethod
 	case LIKE, ILIKE, NLIKE, NILIKE:
-		value := f.Value.(string)
-		if len(value) >= 2 && strings.HasPrefix(value, "*") {
-			value = "%" + value[1:]
+		value, ok := f.Value.(string)
+		if !ok {
+			return nil, ErrMethodNotAllowed
 		}
-		if len(value) >= 2 && strings.HasSuffix(value, "*") {
-			value = value[:len(value)-1] + "%"
+		args = append(args, q.likeValue(value))
+		return args, nil
+	case FTS, REGEX:
+		value, ok := f.Value.(string)
+		if !ok {
+			return nil, ErrMethodNotAllowed
+		}
+		args = append(args, value)
+		return args, nil
+	case STARTS, ENDS, CONTAINS_STR:
+		value, ok := f.Value.(string)
+		if !ok {
+			return nil, ErrMethodNotAllowed
+		}
+		value = escapeLike(value)
+		switch f.Method {
+		case STARTS:
+			value = value + "%"
+		case ENDS:
+			value = "%" + value
+		default:
+			value = "%" + value + "%"
 		}
 		args = append(args, value)
 		return args, nil
 	case IN, NIN:
+		if f.Method == IN && q.anyIN() {
+			args = append(args, q.array(toSlice(f.Value)))
+			return args, nil
+		}
 		_, params, _ := in("?", f.Value)
 		args = append(args, params...)
 		return args, nil
+	case CONTAINS:
+		switch f.Value.(type) {
+		case []int, []string:
+			args = append(args, q.array(f.Value))
+			return args, nil
+		default:
+			return nil, ErrMethodNotAllowed
+		}
+	case BETWEEN:
+		_, params, _ := in("?", f.Value)
+		if len(params) != 2 {
+			return nil, ErrBadFormat
+		}
+		args = append(args, params...)
+		return args, nil
+	case RANGE:
+		r, ok := f.Value.(Range)
+		if !ok {
+			return nil, ErrBadFormat
+		}
+		args = append(args, r.From, r.To)
+		return args, nil
+	case cursor:
+		k, ok := f.Value.(keyset)
+		if !ok {
+			return nil, ErrBadFormat
+		}
+		args = append(args, k.args()...)
+		return args, nil
 	case raw:
 		return args, nil
 	default:
+		if q.customMethod(f.Method) {
+			args = append(args, f.Value)
+			return args, nil
+		}
 		return nil, ErrUnknownMethod
 	}
 }
 
+// toSlice returns single value as slice with one element
+func toSlice(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return []int{v}
+	case string:
+		return []string{v}
+	case float64:
+		return []float64{v}
+	case time.Time:
+		return []time.Time{v}
+	case []int, []string, []float64, []time.Time, []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
 func (f *Filter) setInt(list []string) error {
 	if len(list) == 1 {
 		switch f.Method {
@@ -266,11 +712,19 @@ func (f *Filter) setInt(list []string) error {
 				return ErrBadFormat
 			}
 			f.Value = i
+		case CONTAINS:
+			i, err := strconv.Atoi(list[0])
+			if err != nil {
+				return ErrBadFormat
+			}
+			f.Value = []int{i}
+		case BETWEEN:
+			return ErrBadFormat
 		default:
 			return ErrMethodNotAllowed
 		}
 	} else {
-		if f.Method != IN && f.Method != NIN {
+		if f.Method != IN && f.Method != NIN && f.Method != BETWEEN && f.Method != CONTAINS {
 			return ErrMethodNotAllowed
 		}
 		intSlice := make([]int, len(list))
@@ -281,6 +735,14 @@ func (f *Filter) setInt(list []string) error {
 			}
 			intSlice[i] = v
 		}
+		if f.Method == BETWEEN {
+			if len(intSlice) != 2 {
+				return ErrBadFormat
+			}
+			if intSlice[0] > intSlice[1] {
+				return ErrNotInScope
+			}
+		}
 		f.Value = intSlice
 	}
 	return nil
@@ -288,7 +750,7 @@ func (f *Filter) setInt(list []string) error {
 
 func (f *Filter) setBool(list []string) error {
 	if len(list) == 1 {
-		if f.Method != EQ {
+		if f.Method != EQ && f.Method != NE {
 			return ErrMethodNotAllowed
 		}
 
@@ -303,10 +765,185 @@ func (f *Filter) setBool(list []string) error {
 	return nil
 }
 
+func (f *Filter) setFloat(list []string) error {
+	floats := make([]float64, len(list))
+	for i, s := range list {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return ErrBadFormat
+		}
+		floats[i] = v
+	}
+
+	switch {
+	case len(list) == 1 && isNumericMethod(f.Method):
+		f.Value = floats[0]
+	case len(list) > 1 && (f.Method == IN || f.Method == NIN):
+		f.Value = floats
+	case f.Method == BETWEEN:
+		if len(floats) != 2 {
+			return ErrBadFormat
+		}
+		if floats[0] > floats[1] {
+			return ErrNotInScope
+		}
+		f.Value = floats
+	default:
+		return ErrMethodNotAllowed
+	}
+	return nil
+}
+
+// setDecimal keeps values as strings to preserve precision, eg. for NUMERIC columns
+func (f *Filter) setDecimal(list []string) error {
+	rats := make([]*big.Rat, len(list))
+	for i, s := range list {
+		if !isDecimal(s) {
+			return ErrBadFormat
+		}
+		rats[i], _ = new(big.Rat).SetString(s)
+	}
+
+	switch {
+	case len(list) == 1 && isNumericMethod(f.Method):
+		f.Value = list[0]
+	case len(list) > 1 && (f.Method == IN || f.Method == NIN):
+		f.Value = list
+	case f.Method == BETWEEN:
+		if len(list) != 2 {
+			return ErrBadFormat
+		}
+		if rats[0].Cmp(rats[1]) > 0 {
+			return ErrNotInScope
+		}
+		f.Value = list
+	default:
+		return ErrMethodNotAllowed
+	}
+	return nil
+}
+
+// isNumericMethod returns true for methods with single value which are allowed for numeric types
+func isNumericMethod(m Method) bool {
+	switch m {
+	case EQ, NE, GT, LT, GTE, LTE, IN, NIN:
+		return true
+	default:
+		return false
+	}
+}
+
+// valuesCount returns number of values of IN filter for parsed types of values.
+// It returns false for other types, they are expanded by in().
+func valuesCount(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case []int:
+		return len(v), true
+	case []string:
+		return len(v), true
+	case []float64:
+		return len(v), true
+	case []time.Time:
+		return len(v), true
+	case int, string, float64, bool, time.Time:
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// isNumericType returns true for types which support ranges (numbers and time)
+func isNumericType(valueType string) bool {
+	switch valueType {
+	case "int", "float", "decimal", "time", "date":
+		return true
+	default:
+		return false
+	}
+}
+
+// isDecimal returns true for decimal numbers like "10", "-0.5", "12.30"
+func isDecimal(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	parts := strings.SplitN(s, ".", 2)
+	for _, p := range parts {
+		if p == "" {
+			return false
+		}
+		for _, c := range p {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// setUUID normalizes values to canonical lowercase form with dashes,
+// uuid could be also provided without dashes or in upper case
+func (f *Filter) setUUID(list []string) error {
+	ids := make([]string, len(list))
+	for i, s := range list {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return ErrBadFormat
+		}
+		ids[i] = id.String()
+	}
+
+	switch {
+	case len(list) == 1 && (f.Method == EQ || f.Method == NE || f.Method == IN || f.Method == NIN):
+		f.Value = ids[0]
+	case len(list) > 1 && (f.Method == IN || f.Method == NIN):
+		f.Value = ids
+	default:
+		return ErrMethodNotAllowed
+	}
+	return nil
+}
+
+// enumValues returns allowed values of enum type, eg. "enum(active,archived)"
+func enumValues(valueType string) ([]string, bool) {
+	if !strings.HasPrefix(valueType, "enum(") || !strings.HasSuffix(valueType, ")") {
+		return nil, false
+	}
+	return strings.Split(valueType[len("enum("):len(valueType)-1], ","), true
+}
+
+// setEnum sets string values which must be one of values of enum
+func (f *Filter) setEnum(values, list []string) error {
+	switch {
+	case len(list) == 1 && (f.Method == EQ || f.Method == NE || f.Method == IN || f.Method == NIN):
+	case len(list) > 1 && (f.Method == IN || f.Method == NIN):
+	default:
+		return ErrMethodNotAllowed
+	}
+
+	for _, s := range list {
+		found := false
+		for _, v := range values {
+			if s == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Wrapf(ErrNotInScope, "%v", s)
+		}
+	}
+
+	if len(list) == 1 {
+		f.Value = list[0]
+	} else {
+		f.Value = list
+	}
+	return nil
+}
+
 func (f *Filter) setString(list []string) error {
 	if len(list) == 1 {
 		switch f.Method {
-		case EQ, NE, GT, LT, GTE, LTE, LIKE, ILIKE, NLIKE, NILIKE, IN, NIN:
+		case EQ, NE, GT, LT, GTE, LTE, LIKE, ILIKE, NLIKE, NILIKE, STARTS, ENDS, CONTAINS_STR, IN, NIN, FTS, REGEX:
 			f.Value = list[0]
 			return nil
 		case IS, NOT:
@@ -314,12 +951,26 @@ func (f *Filter) setString(list []string) error {
 				f.Value = NULL
 				return nil
 			}
+		case CONTAINS:
+			f.Value = list
+			return nil
+		case BETWEEN:
+			return ErrBadFormat
 		default:
 			return ErrMethodNotAllowed
 		}
 	} else {
 		switch f.Method {
-		case IN, NIN:
+		case IN, NIN, CONTAINS:
+			f.Value = list
+			return nil
+		case BETWEEN:
+			if len(list) != 2 {
+				return ErrBadFormat
+			}
+			if list[0] > list[1] {
+				return ErrNotInScope
+			}
 			f.Value = list
 			return nil
 		}
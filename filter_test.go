@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -100,3 +101,292 @@ func Test_RemoveOrEntries(t *testing.T) {
 		})
 	}
 }
+
+func Test_LikeNotString(t *testing.T) {
+	filter := Filter{
+		Key:    "age[like]",
+		Name:   "age",
+		Method: LIKE,
+		Value:  10,
+	}
+	_, err := filter.Where()
+	assert.Equal(t, ErrMethodNotAllowed, err)
+	_, err = filter.Args()
+	assert.Equal(t, ErrMethodNotAllowed, err)
+
+	// such filter is skipped in WHERE and arguments
+	q := New().AddFilter("age", ILIKE, 10).AddFilter("name", LIKE, "*tim")
+	assert.Equal(t, "name LIKE ?", q.Where())
+	assert.Equal(t, []interface{}{"%tim"}, q.Args())
+
+	// parser doesn't allow LIKE for int filters
+	q = NewQV(nil, Validations{"age:int": nil})
+	assert.NoError(t, q.SetUrlString("?age[like]=1"))
+	assert.EqualError(t, q.Parse(), "age[like]: method are not allowed")
+}
+
+func Test_NullComparison(t *testing.T) {
+	validations := Validations{
+		"age:int":     Min(18),
+		"name":        In("tim", "bob"),
+		"active:bool": nil,
+	}
+
+	cases := []struct {
+		url   string
+		where string
+	}{
+		{url: "?name[is]=null", where: "name IS NULL"},
+		{url: "?name[not]=NULL", where: "name IS NOT NULL"},
+		{url: "?name[isnot]=null", where: "name IS NOT NULL"},
+		{url: "?age[is]=null", where: "age IS NULL"},
+		{url: "?age[isnot]=null", where: "age IS NOT NULL"},
+		{url: "?active[is]=null&age=20", where: "active IS NULL AND age = ?"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.where, q.Where())
+		})
+	}
+
+	// NULL comparisons have no arguments
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?age[isnot]=null&name=tim"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{"tim"}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?age[is]=10"))
+	assert.Error(t, q.Parse())
+}
+
+func Test_NotIN(t *testing.T) {
+	q := NewQV(nil, Validations{"tags": nil, "id:int": nil})
+
+	assert.NoError(t, q.SetUrlString("?tags[nin]=a,b,c"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "tags NOT IN (?, ?, ?)", q.Where())
+	assert.Equal(t, []interface{}{"a", "b", "c"}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?id[nin]=1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id NOT IN (?)", q.Where())
+	assert.Equal(t, []interface{}{1}, q.Args())
+
+	q.SetDelimiterIN("!")
+	assert.NoError(t, q.SetUrlString("?id[nin]=1!2"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id NOT IN (?, ?)", q.Where())
+	assert.Equal(t, []interface{}{1, 2}, q.Args())
+}
+
+func Test_Between(t *testing.T) {
+	validations := Validations{"price:int": Max(1000), "day": nil}
+
+	cases := []struct {
+		url  string
+		args []interface{}
+		err  error
+	}{
+		{url: "?price[between]=10,100", args: []interface{}{10, 100}},
+		{url: "?price[between]=10,10", args: []interface{}{10, 10}},
+		{url: "?day[between]=2020-01-01,2020-02-01", args: []interface{}{"2020-01-01", "2020-02-01"}},
+		{url: "?price[between]=100,10", err: ErrNotInScope},
+		{url: "?day[between]=b,a", err: ErrNotInScope},
+		{url: "?price[between]=10", err: ErrBadFormat},
+		{url: "?day[between]=a", err: ErrBadFormat},
+		{url: "?price[between]=10,20,30", err: ErrBadFormat},
+		{url: "?price[between]=a,20", err: ErrBadFormat},
+		{url: "?price[between]=10,2000", err: ErrNotInScope},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if c.err != nil {
+				assert.Equal(t, c.err, errors.Cause(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Regexp(t, `^(price|day) BETWEEN \? AND \?$`, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?price[between]=10,100"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "price%5Bbetween%5D=10%2C100", q.ToQueryString())
+	assert.True(t, q.Match(struct{ Price int }{100}))
+	assert.False(t, q.Match(struct{ Price int }{101}))
+}
+
+func Test_FloatDecimal(t *testing.T) {
+	validations := Validations{"price:float": nil, "amount:decimal": nil}
+
+	cases := []struct {
+		url   string
+		where string
+		args  []interface{}
+		err   error
+	}{
+		{url: "?price=10.5", where: "price = ?", args: []interface{}{10.5}},
+		{url: "?price[gte]=-1", where: "price >= ?", args: []interface{}{-1.0}},
+		{url: "?price[in]=1.5,2", where: "price IN (?, ?)", args: []interface{}{1.5, 2.0}},
+		{url: "?price[between]=1.5,2.5", where: "price BETWEEN ? AND ?", args: []interface{}{1.5, 2.5}},
+		{url: "?price=[1.5,10)", where: "(price >= ? AND price < ?)", args: []interface{}{1.5, 10.0}},
+		{url: "?amount[lt]=10.10", where: "amount < ?", args: []interface{}{"10.10"}},
+		{url: "?amount[in]=0.1,0.20", where: "amount IN (?, ?)", args: []interface{}{"0.1", "0.20"}},
+		{url: "?amount[between]=9.99,10.1", where: "amount BETWEEN ? AND ?", args: []interface{}{"9.99", "10.1"}},
+		{url: "?amount=(0,1.5]", where: "(amount > ? AND amount <= ?)", args: []interface{}{"0", "1.5"}},
+		{url: "?price=abc", err: ErrBadFormat},
+		{url: "?price=NaN", err: ErrBadFormat},
+		{url: "?price[like]=1", err: ErrMethodNotAllowed},
+		{url: "?price[between]=2,1", err: ErrNotInScope},
+		{url: "?amount=1e5", err: ErrBadFormat},
+		{url: "?amount=1.", err: ErrBadFormat},
+		{url: "?amount[between]=10.1,9.99", err: ErrNotInScope},
+		{url: "?amount[eq]=1,2", err: ErrBadFormat},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if c.err != nil {
+				assert.Equal(t, c.err, errors.Cause(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?price[in]=1.5,2&amount[gt]=10.5"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "amount%5Bgt%5D=10.5&price%5Bin%5D=1.5%2C2", q.ToQueryString())
+	assert.True(t, q.Match(struct {
+		Price  float64
+		Amount float64
+	}{2, 11}))
+}
+
+func Test_UUID(t *testing.T) {
+	validations := Validations{"id:uuid": nil}
+	const id = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	cases := []struct {
+		url   string
+		where string
+		args  []interface{}
+		err   error
+	}{
+		{url: "?id=" + id, where: "id = ?", args: []interface{}{id}},
+		{url: "?id=6BA7B810-9DAD-11D1-80B4-00C04FD430C8", where: "id = ?", args: []interface{}{id}},
+		{url: "?id[ne]=6ba7b8109dad11d180b400c04fd430c8", where: "id != ?", args: []interface{}{id}},
+		{url: "?id[in]=" + id + ",6ba7b811-9dad-11d1-80b4-00c04fd430c8", where: "id IN (?, ?)", args: []interface{}{id, "6ba7b811-9dad-11d1-80b4-00c04fd430c8"}},
+		{url: "?id[is]=null", where: "id IS NULL", args: []interface{}{}},
+		{url: "?id=6ba7b810", err: ErrBadFormat},
+		{url: "?id[in]=" + id + ",x", err: ErrBadFormat},
+		{url: "?id[gt]=" + id, err: ErrMethodNotAllowed},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if c.err != nil {
+				assert.Equal(t, c.err, errors.Cause(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+}
+
+func Test_Enum(t *testing.T) {
+	validations := Validations{"status:enum(active,archived,draft)": nil}
+
+	cases := []struct {
+		url   string
+		where string
+		args  []interface{}
+		err   string
+	}{
+		{url: "?status=active", where: "status = ?", args: []interface{}{"active"}},
+		{url: "?status[ne]=draft", where: "status != ?", args: []interface{}{"draft"}},
+		{url: "?status[in]=active,draft", where: "status IN (?, ?)", args: []interface{}{"active", "draft"}},
+		{url: "?status[not]=null", where: "status IS NOT NULL", args: []interface{}{}},
+		{url: "?status=deleted", err: "status: deleted: not in scope"},
+		{url: "?status[in]=active,Draft", err: "status[in]: Draft: not in scope"},
+		{url: "?status[like]=act*", err: "status[like]: method are not allowed"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	// required mark
+	q := NewQV(nil, Validations{"status:enum(active,archived)!": nil})
+	assert.NoError(t, q.SetUrlString("?status=archived"))
+	assert.NoError(t, q.Parse())
+	assert.NoError(t, q.SetUrlString("?"))
+	assert.EqualError(t, q.Parse(), "status: required")
+}
+
+func Test_AllowedMethods(t *testing.T) {
+	validations := Validations{
+		"status:methods(eq,in)":          nil,
+		"email:methods(eq, in, nin)":     nil,
+		"age:int:methods(gte,lte,isnot)": nil,
+		"name:methods(eq,regex)":         nil,
+		"id:int":                         nil,
+	}
+
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?status=active"},
+		{url: "?status[in]=active,draft"},
+		{url: "?status[ne]=active", err: "status[ne]: method are not allowed"},
+		{url: "?status[is]=null", err: "status[is]: method are not allowed"},
+		{url: "?email[nin]=a@b.c"},
+		{url: "?email[like]=*@b.c", err: "email[like]: method are not allowed"},
+		{url: "?age[gte]=18"},
+		{url: "?age[not]=null"},
+		{url: "?age=18", err: "age: method are not allowed"},
+		{url: "?age[gte]=a", err: "age[gte]: bad format"},
+		{url: "?name[regex]=^jo"},
+		{url: "?id[ne]=1"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package rqp
+
+// AddForcedFilter adds filter which is always added to WHERE statement with AND,
+// eg. to scope rows by tenant. Forced filters aren't changed by Parse, can't be removed
+// by RemoveFilter and aren't joined by top level OR (see SetTopLevelJoin):
+//
+//	q.AddForcedFilter("tenant_id", rqp.EQ, tenantID)
+//	// ?id=1 -> WHERE id = ? AND tenant_id = ?
+func (q *Query) AddForcedFilter(name string, m Method, value interface{}) *Query {
+	q.invalidate()
+	q.forced = append(q.forced, &Filter{
+		Name:   name,
+		Method: m,
+		Value:  value,
+	})
+	return q
+}
+
+// ForcedFilters returns filters added by AddForcedFilter and condition of soft delete (see SetSoftDelete)
+func (q *Query) ForcedFilters() []*Filter {
+	return q.forcedFilters()
+}
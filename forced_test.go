@@ -0,0 +1,52 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddForcedFilter(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "tenant_id:int": nil}).
+		AddForcedFilter("tenant_id", EQ, 7).
+		AddForcedFilter("deleted_at", IS, NULL)
+
+	// without filters of client
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "tenant_id = ? AND deleted_at IS NULL", q.Where())
+	assert.Equal(t, []interface{}{7}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?id=1&tenant_id=8"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id = ? AND tenant_id = ? AND tenant_id = ? AND deleted_at IS NULL", q.Where())
+	assert.Equal(t, []interface{}{1, 8, 7}, q.Args())
+
+	// can't be removed
+	assert.NoError(t, q.RemoveFilter("tenant_id"))
+	assert.Equal(t, "id = ? AND tenant_id = ? AND deleted_at IS NULL", q.Where())
+	assert.Len(t, q.ForcedFilters(), 2)
+
+	// top level OR doesn't affect forced filters
+	assert.NoError(t, q.SetUrlString("?id=1&tenant_id=8"))
+	assert.NoError(t, q.Parse())
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	assert.Equal(t, "(id = ? OR tenant_id = ?) AND tenant_id = ? AND deleted_at IS NULL", q.Where())
+	assert.Equal(t, q.Where(), q.Clone().Where())
+
+	q.SetPlaceholder(PlaceholderDollar)
+	assert.Equal(t, " WHERE (id = $1 OR tenant_id = $2) AND tenant_id = $3 AND deleted_at IS NULL", q.WHERE())
+	assert.Equal(t, "(id = :id OR tenant_id = :tenant_id) AND tenant_id = :tenant_id_2 AND deleted_at IS NULL", q.WhereNamed())
+
+	// in-memory filtering
+	type row struct {
+		ID        int `db:"id"`
+		TenantID  int `db:"tenant_id"`
+		DeletedAt *string
+	}
+	q = New().AddForcedFilter("tenant_id", EQ, 7)
+	assert.True(t, q.Match(row{TenantID: 7}))
+	assert.False(t, q.Match(row{TenantID: 8}))
+	q.AddFilter("id", EQ, 1)
+	assert.False(t, q.Match(row{ID: 1, TenantID: 8}))
+	assert.True(t, q.Match(row{ID: 1, TenantID: 7}))
+}
@@ -0,0 +1,37 @@
+package rqp
+
+import "fmt"
+
+// SetFullTextSearch sets SQL template of FTS filters, it overrides template of dialect.
+// Template must contain `%s` for the column and `?` for the value. Example:
+//
+//	q.SetFullTextSearch("to_tsvector('english', %s) @@ plainto_tsquery('english', ?)")
+func (q *Query) SetFullTextSearch(template string) *Query {
+	q.invalidate()
+	q.ftsTemplate = template
+	return q
+}
+
+// fullTextSearch returns condition of FTS filter for column name.
+// Templates of dialects:
+//
+//	DialectPostgres, DialectDefault: to_tsvector(name) @@ plainto_tsquery(?)
+//	DialectMySQL:                    MATCH(name) AGAINST (?)
+//	DialectSQLite:                   name MATCH ?
+//	DialectMSSQL:                    CONTAINS(name, ?)
+func (q *Query) fullTextSearch(name string) string {
+	template := q.ftsTemplate
+	if template == "" {
+		switch q.dialect {
+		case DialectMySQL:
+			template = "MATCH(%s) AGAINST (?)"
+		case DialectSQLite:
+			template = "%s MATCH ?"
+		case DialectMSSQL:
+			template = "CONTAINS(%s, ?)"
+		default:
+			template = "to_tsvector(%s) @@ plainto_tsquery(?)"
+		}
+	}
+	return fmt.Sprintf(template, name)
+}
@@ -0,0 +1,45 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullTextSearch(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{DialectDefault, "to_tsvector(title) @@ plainto_tsquery(?)"},
+		{DialectPostgres, "to_tsvector(title) @@ plainto_tsquery(?)"},
+		{DialectMySQL, "MATCH(title) AGAINST (?)"},
+		{DialectSQLite, "title MATCH ?"},
+		{DialectMSSQL, "CONTAINS(title, ?)"},
+	}
+	for _, c := range cases {
+		t.Run(string(c.dialect), func(t *testing.T) {
+			q := New().AddValidation("title", nil).SetDialect(c.dialect)
+			assert.NoError(t, q.SetUrlString("?title[fts]=quick+fox"))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.expected, q.Where())
+			assert.Equal(t, []interface{}{"quick fox"}, q.Args())
+		})
+	}
+
+	// MATCH is an alias
+	q := New().AddValidation("title", nil)
+	assert.NoError(t, q.SetUrlString("?title[match]=fox"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "to_tsvector(title) @@ plainto_tsquery(?)", q.Where())
+
+	// template of instance
+	q.SetFullTextSearch("to_tsvector('english', %s) @@ websearch_to_tsquery('english', ?)")
+	assert.Equal(t, "to_tsvector('english', title) @@ websearch_to_tsquery('english', ?)", q.Where())
+	assert.Equal(t, q.Where(), q.Clone().Where())
+
+	// only for strings
+	q = New().AddValidation("id:int", nil)
+	assert.NoError(t, q.SetUrlString("?id[fts]=1"))
+	assert.EqualError(t, q.Parse(), "id[fts]: method are not allowed")
+}
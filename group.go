@@ -0,0 +1,94 @@
+package rqp
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// parseGroup parses "group" parameter: `group=country,plan`. Names must be allowed
+// by validation of "group" like names of fields: `"group": rqp.In("country", "plan")`.
+func (q *Query) parseGroup(value []string, validate ValidationFunc) error {
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
+	if validate == nil {
+		return ErrValidationNotFound
+	}
+
+	list := cleanSliceString(strings.Split(value[0], q.delimiterIN))
+
+	if q.maxFields > 0 && len(list) > q.maxFields {
+		return ErrTooManyFields
+	}
+
+	for _, v := range list {
+		if err := q.checkIdentifier(v); err != nil {
+			return err
+		}
+		if err := q.checkDenied(v); err != nil {
+			return err
+		}
+		if err := validate(v); err != nil {
+			return err
+		}
+	}
+
+	q.Group = list
+	return nil
+}
+
+// checkGroup returns error if fields aren't in group
+func (q *Query) checkGroup() error {
+	if len(q.Group) == 0 {
+		return nil
+	}
+	for _, field := range q.Fields {
+		if _, _, ok := splitComputed(field); ok {
+			continue
+		}
+		if !stringInSlice(field, q.Group) {
+			return errors.Wrap(errors.Wrap(ErrNotInScope, field), "fields")
+		}
+	}
+	return nil
+}
+
+// GroupBy returns list of elements for GROUP BY statement
+//
+// Return example: `country, plan`
+func (q *Query) GroupBy() string {
+	if len(q.Group) == 0 {
+		return ""
+	}
+	return q.joinColumns(q.Group)
+}
+
+// GROUPBY returns words GROUP BY with list of elements for grouping
+//
+// Return example: ` GROUP BY country, plan`
+func (q *Query) GROUPBY() string {
+	if len(q.Group) == 0 {
+		return ""
+	}
+	return " GROUP BY " + q.GroupBy()
+}
+
+// SetGroup sets elements of GROUP BY statement
+func (q *Query) SetGroup(fields ...string) *Query {
+	q.Group = fields
+	return q
+}
+
+// star returns elements of SELECT statement without fields: columns of group or a star ("*")
+func (q *Query) star() string {
+	if len(q.Group) > 0 {
+		return q.GroupBy()
+	}
+	if q.qualifier != "" {
+		return q.identifier(q.qualifier) + ".*"
+	}
+	return "*"
+}
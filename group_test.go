@@ -0,0 +1,69 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	validations := Validations{
+		"group":       In("country", "plan"),
+		"fields":      In("country", "plan", "id"),
+		"sort":        In("country"),
+		"active:bool": nil,
+	}
+
+	cases := []struct {
+		url   string
+		sql   string
+		count string
+		err   string
+	}{
+		{
+			url:   "?group=country,plan&fields=country&active=true&sort=country",
+			sql:   "SELECT country FROM t WHERE active = ? GROUP BY country, plan ORDER BY country",
+			count: "SELECT COUNT(*) FROM (SELECT 1 FROM t WHERE active = ? GROUP BY country, plan) AS grouped",
+		},
+		{
+			url:   "?group[in]=plan",
+			sql:   "SELECT plan FROM t GROUP BY plan",
+			count: "SELECT COUNT(*) FROM (SELECT 1 FROM t GROUP BY plan) AS grouped",
+		},
+		{
+			url:   "?active=false",
+			sql:   "SELECT * FROM t WHERE active = ?",
+			count: "SELECT COUNT(*) FROM t WHERE active = ?",
+		},
+		{url: "?group=country&fields=id", err: "fields: id: not in scope"},
+		{url: "?group=id", err: "group: id: not in scope"},
+		{url: "?group=country&group=plan", err: "group: expected 1 value, got 2: bad format"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			URL, _ := url.Parse(c.url)
+			q, err := NewParse(URL.Query(), validations)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.sql, q.SQL("t"))
+			assert.Equal(t, c.count, q.CountSQL("t"))
+		})
+	}
+
+	// group without validation isn't allowed
+	_, err := NewParse(url.Values{"group": {"country"}}, Validations{})
+	assert.EqualError(t, err, "group: validation not found")
+
+	q := New().SetGroup("country", "plan")
+	assert.Equal(t, "country, plan", q.GroupBy())
+	assert.Equal(t, " GROUP BY country, plan", q.GROUPBY())
+	assert.Equal(t, "country, plan", q.Select())
+	assert.Equal(t, []string{"country", "plan"}, q.Clone().Group)
+	assert.Equal(t, "group=country%2Cplan", q.ToQueryString())
+	assert.Nil(t, q.Reset().Group)
+	assert.Equal(t, "", q.GROUPBY())
+}
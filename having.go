@@ -0,0 +1,197 @@
+package rqp
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// havingKey is a key of HAVING filters in query: `having[count(*)][gte]=10`
+const havingKey = "having"
+
+// isAggregate returns true if name of filter is an aggregate expression, eg. `count(*)` or `sum(amount)`:
+// function name with arguments of names, stars and spaces only, eg. `count(DISTINCT user_id)`
+func isAggregate(name string) bool {
+	i := strings.IndexByte(name, '(')
+	if i < 1 || !strings.HasSuffix(name, ")") || !isIdentifier(name[:i]) || strings.Contains(name[:i], ".") {
+		return false
+	}
+	for _, c := range name[i+1 : len(name)-1] {
+		if c != '*' && c != ' ' && c != '.' && c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// havingFilterKey returns key of filter of HAVING key: `having[count(*)][gte]` -> `count(*)[gte]`
+func havingFilterKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, havingKey+"[") {
+		return "", false
+	}
+	rest := key[len(havingKey)+1:]
+	depth := 0
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ']':
+			if depth == 0 {
+				if i == 0 {
+					return "", false
+				}
+				return rest[:i] + rest[i+1:], true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseHaving parses HAVING filters of key: `having[count(*)][gte]=10`.
+// Filters of OR statement must be HAVING filters too: `having[count(*)][gte]=10|having[sum(amount)][gt]=100`.
+func (q *Query) parseHaving(key string, values []string) error {
+	if len(values) == 0 {
+		return errors.Wrap(ErrBadFormat, key)
+	}
+
+	for _, value := range values {
+		parts := strings.Split(value, q.delimiterOR)
+		filters := make([]*Filter, 0, len(parts))
+		k := key
+		for i, v := range parts {
+			if i > 0 {
+				if u := strings.SplitN(v, "=", 2); len(u) == 2 {
+					k = u[0]
+					v = u[1]
+				}
+			}
+
+			filter, err := q.newHaving(k, strings.TrimSpace(v))
+			if err != nil {
+				if err == errSkipFilter {
+					continue
+				}
+				return errors.Wrap(err, k)
+			}
+			filters = append(filters, filter)
+		}
+
+		setOR(filters)
+		q.Havings = append(q.Havings, filters...)
+	}
+
+	return nil
+}
+
+// newHaving creates a HAVING filter from url key and its value.
+// Name of the filter must be an aggregate expression declared in validations, eg. `"count(*):int"`.
+func (q *Query) newHaving(key, value string) (*Filter, error) {
+	name, ok := havingFilterKey(key)
+	if !ok {
+		return nil, ErrBadFormat
+	}
+
+	if len(value) == 0 {
+		if q.emptyValue == EmptyValueSkip {
+			return nil, errSkipFilter
+		}
+		return nil, ErrEmptyValue
+	}
+
+	f := &Filter{
+		Key: key,
+	}
+
+	if err := f.parseKey(name, q.methods); err != nil {
+		return nil, err
+	}
+
+	if !isAggregate(f.Name) {
+		return nil, ErrFilterNotFound
+	}
+
+	if q.isDenied(f.Name) {
+		return nil, ErrFilterNotAllowed
+	}
+
+	if err := q.setFilter(f, value); err != nil {
+		if err == ErrValidationNotFound {
+			return nil, ErrFilterNotFound
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// HaveHaving returns true if request has HAVING filter of aggregate expression
+func (q *Query) HaveHaving(name string) bool {
+	for _, f := range q.Havings {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Having returns list of filters for HAVING statement: `count(*) >= ? AND sum(amount) > ?`.
+// Numbered placeholders continue numbers of WHERE statement, so the statement
+// is used with arguments of Args followed by HavingArgs.
+func (q *Query) Having() string {
+	n := len(q.Args()) + 1
+
+	var b strings.Builder
+	b.Grow(24 * len(q.Havings))
+
+	q.renderGroups(&b, orGroups(q.Havings), "AND", func(filter *Filter) (string, bool) {
+		a, err := filter.where(q)
+		if err != nil {
+			return "", false
+		}
+		a, n = q.rebind(a, n)
+		return a, true
+	})
+
+	return b.String()
+}
+
+// HAVING returns list of filters for HAVING statement with `HAVING` word
+//
+// Return example: ` HAVING count(*) >= ?`
+func (q *Query) HAVING() string {
+	having := q.Having()
+	if len(having) == 0 {
+		return ""
+	}
+
+	return " HAVING " + having
+}
+
+// HavingArgs returns slice of arguments for HAVING statement
+func (q *Query) HavingArgs() []interface{} {
+	args := make([]interface{}, 0, len(q.Havings))
+
+	for _, filter := range q.Havings {
+		if filter.Disabled || isNullComparison(filter) {
+			continue
+		}
+		if a, err := filter.args(q); err == nil {
+			args = append(args, a...)
+		}
+	}
+
+	return args
+}
+
+// havingQueryKey returns key of HAVING filter for query part of URL: `having[count(*)][gte]`
+func havingQueryKey(f *Filter) string {
+	key := havingKey + "[" + f.Name + "]"
+	if f.Method == EQ || f.Method == RANGE {
+		return key
+	}
+	return fmt.Sprintf("%s[%s]", key, strings.ToLower(string(f.Method)))
+}
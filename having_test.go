@@ -0,0 +1,92 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHaving(t *testing.T) {
+	validations := Validations{
+		"group":              In("country"),
+		"fields":             In("country"),
+		"count(*):int":       nil,
+		"sum(amount):float":  nil,
+		"max(created_at)":    nil,
+		"active:bool":        nil,
+		"avg(score):int:gte": Min(1),
+	}
+
+	cases := []struct {
+		url  string
+		sql  string
+		args []interface{}
+		err  string
+	}{
+		{
+			url:  "?group=country&active=true&having[count(*)][gte]=10&having[sum(amount)][lt]=99.5",
+			sql:  "SELECT country FROM t WHERE active = $1 GROUP BY country HAVING count(*) >= $2 AND sum(amount) < $3",
+			args: []interface{}{true, 10, 99.5},
+		},
+		{
+			url:  "?group=country&having[count(*)]=1|having[sum(amount)][gt]=5",
+			sql:  "SELECT country FROM t GROUP BY country HAVING (count(*) = $1 OR sum(amount) > $2)",
+			args: []interface{}{1, 5.0},
+		},
+		{
+			url:  "?group=country&having[count(*)][in]=1,2",
+			sql:  "SELECT country FROM t GROUP BY country HAVING count(*) IN ($1, $2)",
+			args: []interface{}{1, 2},
+		},
+		{url: "?having[count(*)][gt]=a", err: "having[count(*)][gt]: bad format"},
+		{url: "?having[count(*)]=1&having[avg(score)][gte]=0", err: "having[avg(score)][gte]: 0: not in scope"},
+		{url: "?having[count(*)]=1&having[min(id)]=1", err: "having[min(id)]: filter not found"},
+		{url: "?having[count(*)]=1&having[active]=true", err: "having[active]: filter not found"},
+		{url: "?having[count(*)]=1&having[]=1", err: "having[]: bad format"},
+		{url: "?count(*)=1", err: "count(*): filter not found"},
+		{url: "?active=true|count(*)=1", err: "count(*): filter not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			URL, _ := url.Parse(c.url)
+			q := NewQV(URL.Query(), validations).SetPlaceholder(PlaceholderDollar)
+			err := q.Parse()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			sql, args := q.SQLWithArgs("t")
+			assert.Equal(t, c.sql, sql)
+			assert.Equal(t, c.args, args)
+		})
+	}
+
+	q, err := NewParse(url.Values{
+		"group":                {"country"},
+		"active":               {"true"},
+		"having[count(*)][gt]": {"1"},
+	}, validations)
+	assert.NoError(t, err)
+	assert.Equal(t, "active = ?", q.Where())
+	assert.Equal(t, []interface{}{true}, q.Args())
+	assert.Equal(t, "count(*) > ?", q.Having())
+	assert.Equal(t, []interface{}{1}, q.HavingArgs())
+	assert.True(t, q.HaveHaving("count(*)"))
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT 1 FROM t WHERE active = ? GROUP BY country HAVING count(*) > ?) AS grouped", q.CountSQL("t"))
+	assert.Equal(t, "active=true&group=country&having%5Bcount%28%2A%29%5D%5Bgt%5D=1", q.ToQueryString())
+	assert.Len(t, q.Clone().Havings, 1)
+
+	// aggregates aren't quoted
+	q.SetQuoteIdentifiers(true)
+	assert.Equal(t, " HAVING count(*) > ?", q.HAVING())
+	assert.Equal(t, `"active" = ?`, q.Where())
+
+	assert.NoError(t, q.Reset().Parse())
+	assert.Equal(t, "", q.HAVING())
+
+	// aggregates could be required
+	_, err = NewParse(url.Values{"having[sum(amount)][gt]": {"1"}}, Validations{"count(*):int!": nil, "sum(amount)": nil})
+	assert.EqualError(t, err, "count(*): required")
+}
@@ -0,0 +1,47 @@
+package rqp
+
+import "strings"
+
+// parseInclude parses "include" parameter of related resources: `include=author,comments`.
+// Names must be allowed by validation of "include": `"include": rqp.In("author", "comments")`.
+func (q *Query) parseInclude(value []string, validate ValidationFunc) error {
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
+	if validate == nil {
+		return ErrValidationNotFound
+	}
+
+	list := cleanSliceString(strings.Split(value[0], q.delimiterIN))
+
+	for _, v := range list {
+		if err := q.checkIdentifier(v); err != nil {
+			return err
+		}
+		if err := validate(v); err != nil {
+			return err
+		}
+	}
+
+	q.includes = list
+	return nil
+}
+
+// Includes returns names of related resources requested by "include" parameter,
+// eg. for eager loading of them by handler
+func (q *Query) Includes() []string {
+	return q.includes
+}
+
+// HaveInclude returns true if request asks for related resource
+func (q *Query) HaveInclude(name string) bool {
+	return stringInSlice(name, q.includes)
+}
+
+// SetIncludes sets names of requested related resources
+func (q *Query) SetIncludes(names ...string) *Query {
+	q.includes = names
+	return q
+}
@@ -0,0 +1,53 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInclude(t *testing.T) {
+	validations := Validations{
+		"include": In("author", "comments", "comments.author"),
+	}
+
+	cases := []struct {
+		url      string
+		includes []string
+		err      string
+	}{
+		{url: "?include=author,comments.author", includes: []string{"author", "comments.author"}},
+		{url: "?include[in]=comments", includes: []string{"comments"}},
+		{url: "?", includes: nil},
+		{url: "?include=author,tags", err: "include: tags: not in scope"},
+		{url: "?include=author&include=comments", err: "include: expected 1 value, got 2: bad format"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			URL, _ := url.Parse(c.url)
+			q, err := NewParse(URL.Query(), validations)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.includes, q.Includes())
+		})
+	}
+
+	// include without validation isn't allowed
+	_, err := NewParse(url.Values{"include": {"author"}}, Validations{})
+	assert.EqualError(t, err, "include: validation not found")
+
+	// include could be required
+	_, err = NewParse(url.Values{}, Validations{"include!": In("author")})
+	assert.EqualError(t, err, "include: required")
+
+	q := New().SetIncludes("author", "comments")
+	assert.True(t, q.HaveInclude("author"))
+	assert.False(t, q.HaveInclude("tags"))
+	assert.Equal(t, []string{"author", "comments"}, q.Clone().Includes())
+	assert.Equal(t, "include=author%2Ccomments", q.ToQueryString())
+	assert.Nil(t, q.Reset().Includes())
+}
@@ -0,0 +1,72 @@
+package rqp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetJSONColumns sets JSON columns whose keys could be filtered and sorted by dot notation.
+// Path is extracted as text depending on dialect:
+//
+//	q.SetJSONColumns("meta")
+//	// ?meta.color=red  -> meta->>'color' = ?                    (DialectPostgres, DialectDefault)
+//	//                  -> JSON_EXTRACT(meta, '$.color') = ?      (DialectMySQL)
+//	//                  -> json_extract(meta, '$.color') = ?      (DialectSQLite)
+//	//                  -> JSON_VALUE(meta, '$.color') = ?        (DialectMSSQL)
+//	// ?meta.size.width -> meta->'size'->>'width'
+//
+// Filters must be defined in validations with the full name, eg. "meta.color".
+func (q *Query) SetJSONColumns(columns ...string) *Query {
+	q.invalidate()
+	q.jsonColumns = make(map[string]bool, len(columns))
+	for _, c := range columns {
+		q.jsonColumns[c] = true
+	}
+	return q
+}
+
+// jsonPath returns expression which extracts path from JSON column if name starts with JSON column
+func (q *Query) jsonPath(name string) (string, bool) {
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 || !q.jsonColumns[parts[0]] {
+		return "", false
+	}
+	column, path := q.column(parts[0]), parts[1:]
+
+	switch q.dialect {
+	case DialectMySQL, DialectSQLite, DialectMSSQL:
+		var b strings.Builder
+		b.WriteString("$")
+		for _, p := range path {
+			b.WriteString(".")
+			if isIdentifier(p) {
+				b.WriteString(p)
+			} else {
+				b.WriteString(`"` + strings.ReplaceAll(p, `"`, `\"`) + `"`)
+			}
+		}
+		fn := "JSON_EXTRACT"
+		switch q.dialect {
+		case DialectSQLite:
+			fn = "json_extract"
+		case DialectMSSQL:
+			fn = "JSON_VALUE"
+		}
+		return fmt.Sprintf("%s(%s, %s)", fn, column, quoteString(b.String())), true
+	default:
+		exp := column
+		for i, p := range path {
+			op := "->"
+			if i == len(path)-1 {
+				op = "->>"
+			}
+			exp += op + quoteString(p)
+		}
+		return exp, true
+	}
+}
+
+// quoteString returns s as SQL string literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
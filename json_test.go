@@ -0,0 +1,45 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetJSONColumns(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		where   string
+		order   string
+	}{
+		{DialectDefault, "meta->>'color' = ?", " ORDER BY meta->'size'->>'width'"},
+		{DialectPostgres, "meta->>'color' = ?", " ORDER BY meta->'size'->>'width'"},
+		{DialectMySQL, "JSON_EXTRACT(meta, '$.color') = ?", " ORDER BY JSON_EXTRACT(meta, '$.size.width')"},
+		{DialectSQLite, "json_extract(meta, '$.color') = ?", " ORDER BY json_extract(meta, '$.size.width')"},
+		{DialectMSSQL, "JSON_VALUE(meta, '$.color') = ?", " ORDER BY JSON_VALUE(meta, '$.size.width')"},
+	}
+	for _, c := range cases {
+		t.Run(string(c.dialect), func(t *testing.T) {
+			q := NewQV(nil, Validations{"meta.color": nil, "sort": In("meta.size.width")})
+			q.SetDialect(c.dialect).SetJSONColumns("meta")
+			assert.NoError(t, q.SetUrlString("?meta.color=red&sort=meta.size.width"))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, []interface{}{"red"}, q.Args())
+			assert.Equal(t, c.order, q.ORDER())
+		})
+	}
+
+	// path segments are quoted
+	q := New().SetJSONColumns("meta").AddFilter("meta.it's", EQ, "x")
+	assert.Equal(t, "meta->>'it''s' = ?", q.Where())
+	q.SetDialect(DialectMySQL)
+	assert.Equal(t, `JSON_EXTRACT(meta, '$."it''s"') = ?`, q.Where())
+
+	// column is quoted and mapped
+	q = New().SetJSONColumns("meta").SetQuoteIdentifiers(true).AddFilter("meta.color", EQ, "red").AddFilter("users.id", EQ, 1)
+	assert.Equal(t, `"meta"->>'color' = ? AND "users"."id" = ?`, q.Where())
+	q = New().SetJSONColumns("meta").SetNameMapping(Replacer{"meta": "p.meta"}).AddFilter("meta.color", EQ, "red")
+	assert.Equal(t, "p.meta->>'color' = ?", q.Where())
+	assert.Equal(t, q.Where(), q.Clone().Where())
+}
@@ -0,0 +1,84 @@
+package rqp
+
+import "strings"
+
+// LikePolicy defines how wildcard of client is translated in values of LIKE, ILIKE, NLIKE and NILIKE filters
+type LikePolicy byte
+
+// Like policies:
+const (
+	// LikeWildcard translates wildcard at the beginning and at the end of value to `%`: `*joe*` -> `%joe%`
+	LikeWildcard LikePolicy = iota
+	// LikeExact doesn't translate wildcard, so the value is compared as is
+	LikeExact
+	// LikePrefix translates only wildcard at the end of value: `joe*` -> `joe%`.
+	// Leading wildcard prevents usage of indexes so Parse returns ErrLeadingWildcard for it.
+	LikePrefix
+)
+
+// defaultWildcard is wildcard of client in values of LIKE filters
+const defaultWildcard = "*"
+
+// SetWildcard sets wildcard of client in values of LIKE filters, `*` by default
+func (q *Query) SetWildcard(w string) *Query {
+	q.invalidate()
+	q.wildcard = w
+	return q
+}
+
+// SetLikePolicy sets translation of wildcard of client in values of LIKE filters, see LikePolicy
+func (q *Query) SetLikePolicy(p LikePolicy) *Query {
+	q.invalidate()
+	q.likePolicy = p
+	return q
+}
+
+// wildcardOf returns wildcard of client
+func (q *Query) wildcardOf() string {
+	if q.wildcard == "" {
+		return defaultWildcard
+	}
+	return q.wildcard
+}
+
+// likeValue returns argument of LIKE filter: wildcards of client are translated to `%`
+// depending on policy and `%`, `_` of the value are escaped
+func (q *Query) likeValue(value string) string {
+	if q.likePolicy == LikeExact {
+		return escapeLike(value)
+	}
+
+	w := q.wildcardOf()
+	prefix, suffix := "", ""
+	if q.likePolicy != LikePrefix && len(value) > len(w) && strings.HasPrefix(value, w) {
+		value, prefix = value[len(w):], "%"
+	}
+	if strings.HasSuffix(value, w) && (prefix != "" || len(value) > len(w)) {
+		value, suffix = value[:len(value)-len(w)], "%"
+	}
+	return prefix + escapeLike(value) + suffix
+}
+
+// checkLike returns ErrLeadingWildcard if value of LIKE filter starts with wildcard of client and it isn't allowed
+func (q *Query) checkLike(f *Filter) error {
+	switch f.Method {
+	case LIKE, ILIKE, NLIKE, NILIKE:
+	default:
+		return nil
+	}
+	s, ok := f.Value.(string)
+	if !ok || q.likePolicy != LikePrefix {
+		return nil
+	}
+	if w := q.wildcardOf(); len(s) > len(w) && strings.HasPrefix(s, w) {
+		return ErrLeadingWildcard
+	}
+	return nil
+}
+
+// escapeLike escapes wildcards `%`, `_` and escape character `\` of LIKE in s
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
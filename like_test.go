@@ -0,0 +1,50 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikePolicy(t *testing.T) {
+	cases := []struct {
+		policy   LikePolicy
+		wildcard string
+		value    string
+		expected string
+	}{
+		{LikeWildcard, "", "*joe*", "%joe%"},
+		{LikeWildcard, "", "joe*", "joe%"},
+		{LikeWildcard, "", "*", "*"},
+		{LikeWildcard, "", "**", "%%"},
+		{LikeWildcard, "", "j*e", "j*e"},
+		{LikeWildcard, "", "*50%", `%50\%`},
+		{LikeWildcard, "~", "~joe~", "%joe%"},
+		{LikeWildcard, "~", "*joe*", "*joe*"},
+		{LikeWildcard, "%", "%joe%", "%joe%"},
+		{LikeWildcard, "%", "%50_%", `%50\_%`},
+		{LikeExact, "", "*joe*", "*joe*"},
+		{LikeExact, "", "50%", `50\%`},
+		{LikePrefix, "", "joe*", "joe%"},
+		{LikePrefix, "", "*", "*"},
+	}
+	for _, c := range cases {
+		q := New().SetLikePolicy(c.policy).SetWildcard(c.wildcard)
+		assert.Equal(t, c.expected, q.likeValue(c.value), c.value)
+	}
+
+	q := New().AddValidation("name", nil).SetLikePolicy(LikePrefix)
+	assert.NoError(t, q.SetUrlString("?name[like]=jo*"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{"jo%"}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?name[ilike]=*jo"))
+	assert.Equal(t, ErrLeadingWildcard, errors.Cause(q.Parse()))
+	assert.EqualError(t, q.Clone().Parse(), "name[ilike]: leading wildcard is not allowed")
+
+	// other methods aren't affected
+	assert.NoError(t, q.SetUrlString("?name=*jo"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{"*jo"}, q.Args())
+}
@@ -3,8 +3,10 @@ package rqp
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -13,16 +15,77 @@ import (
 type Query struct {
 	query       map[string][]string
 	validations Validations
+	required    map[string]bool
 
-	Fields  []string
-	Offset  int
-	Limit   int
-	Sorts   []Sort
-	Filters []*Filter
+	Fields   []string
+	Offset   int
+	Limit    int
+	Sorts    []Sort
+	Group    []string
+	Distinct bool
+	Filters  []*Filter
+	Havings  []*Filter
+
+	includes []string
+
+	unknown []string
+	forced  []*Filter
 
 	delimiterIN   string
 	delimiterOR   string
 	ignoreUnknown bool
+	lastValue     bool
+	trimValues    bool
+	maxFilters    int
+	maxSortKeys   int
+	maxFields     int
+	maxValueLen   int
+	maxINSize     int
+	searchKey     string
+	searchColumns []string
+	fields        []string
+	join          string
+	dialect       Dialect
+	quoteNames    bool
+	strictNames   bool
+	placeholder   Placeholder
+	methods       map[Method]string
+	useAnyIN      bool
+	arrayValuer   func(interface{}) interface{}
+	useCursor     bool
+	pagination    PaginationStyle
+	defaultLimit  int
+	maxLimit      int
+	clampLimit    bool
+	cursorBefore  bool
+	emptyValue    EmptyValueBehavior
+	wildcard      string
+	likePolicy    LikePolicy
+	sortAliases   map[string]string
+	nameMapping   Replacer
+	jsonColumns   map[string]bool
+	defaultSort   []Sort
+	ftsTemplate   string
+	timeLayouts   []string
+	dateLayouts   []string
+	clock         func() time.Time
+	softDelete    string
+	withDeleted   bool
+	formValues    bool
+	syntax        Syntax
+	relations     map[string]Relation
+	qualifier     string
+	deniedFields  []string
+
+	postValidation func(q *Query) error
+
+	columnComparisons map[string]columnComparison
+
+	// index of validations by names of filters
+	index *compiledValidations
+
+	// WHERE statement and arguments built by Parse
+	cache *built
 
 	Error error
 }
@@ -31,7 +94,7 @@ type Query struct {
 type Method string
 
 // Compare methods:
-var (
+const (
 	EQ     Method = "EQ"
 	NE     Method = "NE"
 	GT     Method = "GT"
@@ -46,12 +109,34 @@ var (
 	NOT    Method = "NOT"
 	IN     Method = "IN"
 	NIN    Method = "NIN"
-	raw    Method = "raw" // internal usage
+	// STARTS, ENDS and CONTAINS_STR are LIKE with wildcards added by the library:
+	// `value%`, `%value` and `%value%` respectively. Client's "*" isn't a wildcard for them
+	// and `%`, `_` of the value are escaped. STARTS and ENDS are also available as `sw` and `ew`.
+	STARTS       Method = "STARTS"
+	ENDS         Method = "ENDS"
+	CONTAINS_STR Method = "CONTAINS_STR"
+	BETWEEN      Method = "BETWEEN"  // two values separated by delimiter of IN, the first one isn't greater
+	RANGE        Method = "RANGE"    // parsed from interval notation, eg. `price=[10,100)`, see Range
+	FTS          Method = "FTS"      // full-text search, see SetFullTextSearch
+	REGEX        Method = "REGEX"    // must be allowed by validation key with the method, eg. "name:regex"
+	CONTAINS     Method = "CONTAINS" // array column contains all values, eg. `tags @> ?::text[]` (Postgres)
+	raw          Method = "raw"      // internal usage
 )
 
 // NULL constant
 const NULL = "NULL"
 
+// methodAliases are alternative names of methods in query part of URL
+var methodAliases = map[Method]Method{
+	"ISNOT": NOT,
+	"SW":    STARTS,
+	"EW":    ENDS,
+	"MATCH": FTS,
+	"@>":    CONTAINS,
+}
+
+// translateMethods are default SQL operators of methods, they are read-only and shared
+// by all instances, use SetMethodSQL to change or add operators of a Query
 var (
 	translateMethods map[Method]string = map[Method]string{
 		EQ:     "=",
@@ -68,23 +153,217 @@ var (
 		NOT:    "IS NOT",
 		IN:     "IN",
 		NIN:    "NOT IN",
+
+		STARTS:       "LIKE",
+		ENDS:         "LIKE",
+		CONTAINS_STR: "LIKE",
+
+		BETWEEN:  "BETWEEN",
+		FTS:      "@@", // template of dialect is used, see SetFullTextSearch
+		REGEX:    "~",
+		CONTAINS: "@>",
+	}
+
+	// explicitMethods are expensive methods which are allowed only if validation key
+	// of filter contains the method, eg. "name:regex"
+	explicitMethods = map[Method]bool{
+		REGEX: true,
 	}
 )
 
+// columnComparison is a condition between two columns
+type columnComparison struct {
+	left   string
+	method Method
+	right  string
+}
+
+// SetMethodSQL overrides SQL operator of method for this instance only.
+// Unknown method is added as a new one with single value parsed like EQ: `name SIMILAR TO ?`.
+// Example:
+//   q.SetMethodSQL(rqp.LIKE, "ILIKE")
+//   q.SetMethodSQL(rqp.Method("SIMILAR"), "SIMILAR TO") // name[similar]=...
+func (q *Query) SetMethodSQL(m Method, sql string) *Query {
+	q.invalidate()
+	if q.methods == nil {
+		q.methods = make(map[Method]string)
+	}
+	q.methods[m] = sql
+	return q
+}
+
+// translate returns SQL operator of method
+func (q *Query) translate(m Method) string {
+	if sql, ok := q.methods[m]; ok {
+		return sql
+	}
+	if sql, ok := dialectMethods[q.dialect][m]; ok {
+		return sql
+	}
+	return translateMethods[m]
+}
+
+// customMethod returns true if method m is added by SetMethodSQL
+func (q *Query) customMethod(m Method) bool {
+	if _, ok := translateMethods[m]; ok {
+		return false
+	}
+	_, ok := q.methods[m]
+	return ok
+}
+
 // Sort is ordering struct
 type Sort struct {
 	By   string
 	Desc bool
 }
 
+// Unknown returns keys of filters which were ignored by Parse because
+// they are not defined in validations (see IgnoreUnknownFilters)
+func (q *Query) Unknown() []string {
+	return q.unknown
+}
+
 // IgnoreUnknownFilters set behavior for Parser to raise ErrFilterNotAllowed to undefined filters or not
 func (q *Query) IgnoreUnknownFilters(i bool) *Query {
 	q.ignoreUnknown = i
 	return q
 }
 
+// EmptyValueBehavior defines how Parse handles empty values of filters
+type EmptyValueBehavior byte
+
+// Empty value behaviors:
+const (
+	// EmptyValueDefault returns ErrEmptyValue for empty value of filter (eg. ?name=)
+	// and keeps empty elements of lists (eg. ?name[in]=a,,b)
+	EmptyValueDefault EmptyValueBehavior = iota
+	// EmptyValueKeep keeps empty values: `name = ''`
+	EmptyValueKeep
+	// EmptyValueSkip skips filters with empty value and empty elements of lists
+	EmptyValueSkip
+	// EmptyValueError returns ErrEmptyValue for empty value and for empty elements of lists
+	EmptyValueError
+)
+
+// SetEmptyValueBehavior sets behavior of Parse for empty values of filters
+func (q *Query) SetEmptyValueBehavior(b EmptyValueBehavior) *Query {
+	q.emptyValue = b
+	return q
+}
+
+// UseLastValue sets behavior for Parser to use the last value of reserved parameters
+// (fields, sort, limit, offset, etc.) if they are provided several times
+// (eg. ?limit=10&limit=20) instead of raising ErrBadFormat.
+func (q *Query) UseLastValue(u bool) *Query {
+	q.lastValue = u
+	return q
+}
+
+// SetTrimValues sets behavior for Parser to trim leading and trailing whitespaces
+// of every value in the list of values (eg. IN lists) before validation.
+// The whole value of filter is always trimmed.
+func (q *Query) SetTrimValues(t bool) *Query {
+	q.trimValues = t
+	return q
+}
+
+// SetMaxFilters sets maximum number of filters allowed in the query.
+// Parse returns ErrTooManyFilters when it's exceeded. Zero means unlimited.
+func (q *Query) SetMaxFilters(n int) *Query {
+	q.maxFilters = n
+	return q
+}
+
+// SetMaxSortKeys sets maximum number of keys allowed in the "sort" parameter.
+// Parse returns ErrTooManySortKeys when it's exceeded. Zero means unlimited.
+func (q *Query) SetMaxSortKeys(n int) *Query {
+	q.maxSortKeys = n
+	return q
+}
+
+// SetMaxFields sets maximum number of fields allowed in the "fields" parameter.
+// Parse returns ErrTooManyFields when it's exceeded. Zero means unlimited.
+func (q *Query) SetMaxFields(n int) *Query {
+	q.maxFields = n
+	return q
+}
+
+// SetMaxINSize sets maximum number of values of IN, NIN and CONTAINS filters.
+// Parse returns ErrTooManyValues when it's exceeded. Zero means unlimited.
+func (q *Query) SetMaxINSize(n int) *Query {
+	q.maxINSize = n
+	return q
+}
+
+// SetDefaultLimit sets limit which is used when "limit" (or "per_page") isn't provided
+func (q *Query) SetDefaultLimit(n int) *Query {
+	q.defaultLimit = n
+	return q
+}
+
+// SetMaxLimit sets maximum of "limit" (and "per_page") parameter.
+// Bigger limit is rejected with ErrNotInScope or clamped to maximum (see SetClampLimit).
+func (q *Query) SetMaxLimit(n int) *Query {
+	q.maxLimit = n
+	return q
+}
+
+// SetClampLimit sets behavior for limit which is bigger then maximum:
+// true - limit is set to maximum, false (default) - Parse returns ErrNotInScope
+func (q *Query) SetClampLimit(c bool) *Query {
+	q.clampLimit = c
+	return q
+}
+
+// SetMaxValueLength sets maximum length of value of filter.
+// For lists of values (eg. IN) it's checked for every element.
+// Parse returns ErrValueTooLong when it's exceeded. Zero means unlimited.
+func (q *Query) SetMaxValueLength(n int) *Query {
+	q.maxValueLen = n
+	return q
+}
+
+// SetSearchColumns sets columns for the search filter.
+// Value of the search filter (eg. ?q=joe) is looked up in all of them:
+//   (col1 LIKE ? OR col2 LIKE ?)
+// The search filter is not recognized until columns are set.
+func (q *Query) SetSearchColumns(cols ...string) *Query {
+	q.searchColumns = cols
+	return q
+}
+
+// SetSearchKey sets name of the search filter in query part of URL ("q" by default)
+func (q *Query) SetSearchKey(key string) *Query {
+	q.searchKey = key
+	return q
+}
+
+// SetTopLevelJoin sets logical operator which joins top level filters in WHERE statement.
+// Allowed values are "AND" (default) and "OR". Filters inside OR statements
+// are always joined by OR.
+func (q *Query) SetTopLevelJoin(join string) error {
+	switch j := strings.ToUpper(strings.TrimSpace(join)); j {
+	case "AND", "OR":
+		q.invalidate()
+		q.join = j
+		return nil
+	default:
+		return errors.Wrap(ErrBadFormat, join)
+	}
+}
+
+// topLevelJoin returns logical operator for top level filters
+func (q *Query) topLevelJoin() string {
+	if q.join == "" {
+		return "AND"
+	}
+	return q.join
+}
+
 // SetDelimiterIN sets delimiter for values of filters
 func (q *Query) SetDelimiterIN(d string) *Query {
+	q.invalidate()
 	q.delimiterIN = d
 	return q
 }
@@ -99,30 +378,30 @@ func (q *Query) SetDelimiterOR(d string) *Query {
 //
 // Return example:
 //
-// When "fields" empty or not provided: `*`.
+// When "fields" empty or not provided: `*` or columns of group.
 //
 // When "fields=id,email": `id, email`.
 //
 func (q *Query) FieldsString() string {
 	if len(q.Fields) == 0 {
-		return "*"
+		return q.star()
 	}
-	return strings.Join(q.Fields, ", ")
+	return q.joinColumns(q.Fields)
 }
 
 // Select returns elements list separated by comma (",") for querying in SELECT statement or a star ("*") if nothing provided
 //
 // Return examples:
 //
-// When "fields" empty or not provided: `*`
+// When "fields" empty or not provided: `*` or columns of group
 //
 // When "fields=id,email": `id, email`
 //
 func (q *Query) Select() string {
 	if len(q.Fields) == 0 {
-		return "*"
+		return q.star()
 	}
-	return strings.Join(q.Fields, ", ")
+	return q.joinColumns(q.Fields)
 }
 
 // SELECT returns word SELECT with fields from Filter "fields" separated by comma (",") from URL-Query
@@ -130,15 +409,21 @@ func (q *Query) Select() string {
 //
 // Return examples:
 //
-// When "fields" empty or not provided: `SELECT *`.
+// When "fields" empty or not provided: `SELECT *` or columns of group.
 //
 // When "fields=id,email": `SELECT id, email`.
 //
+// When "distinct=true&fields=email": `SELECT DISTINCT email`.
+//
 func (q *Query) SELECT() string {
+	sel := "SELECT "
+	if q.Distinct {
+		sel = "SELECT DISTINCT "
+	}
 	if len(q.Fields) == 0 {
-		return "SELECT *"
+		return sel + q.star()
 	}
-	return fmt.Sprintf("SELECT %s", q.FieldsString())
+	return sel + q.FieldsString()
 }
 
 // HaveField returns true if request asks for specified field
@@ -146,6 +431,23 @@ func (q *Query) HaveField(field string) bool {
 	return stringInSlice(field, q.Fields)
 }
 
+// SetAvailableFields sets all fields which could be selected. It's required for exclusion
+// of fields by "-" prefix: `fields=-password,-secret` selects available fields except these ones.
+// Available fields are allowed fields if there is no validation of "fields".
+func (q *Query) SetAvailableFields(fields ...string) *Query {
+	q.fields = fields
+	return q
+}
+
+// availableFields returns validation of fields which allows available fields only
+func (q *Query) availableFields() ValidationFunc {
+	values := make([]interface{}, len(q.fields))
+	for i := range q.fields {
+		values[i] = q.fields[i]
+	}
+	return In(values...)
+}
+
 // AddField adds field to SELECT statement
 func (q *Query) AddField(field string) *Query {
 	q.Fields = append(q.Fields, field)
@@ -182,20 +484,21 @@ func (q *Query) Order() string {
 		return ""
 	}
 
-	var s string
+	var b strings.Builder
+	b.Grow(16 * len(q.Sorts))
 
 	for i := 0; i < len(q.Sorts); i++ {
 		if i > 0 {
-			s += ", "
+			b.WriteString(", ")
 		}
-		if q.Sorts[i].Desc {
-			s += fmt.Sprintf("%s DESC", q.Sorts[i].By)
-		} else {
-			s += q.Sorts[i].By
+		b.WriteString(q.sortColumn(q.Sorts[i].By))
+		// sorting is reversed to take the nearest rows before cursor
+		if q.Sorts[i].Desc != q.cursorBefore {
+			b.WriteString(" DESC")
 		}
 	}
 
-	return s
+	return b.String()
 }
 
 // ORDER returns words ORDER BY with list of elements for sorting
@@ -206,7 +509,45 @@ func (q *Query) ORDER() string {
 	if len(q.Sorts) == 0 {
 		return ""
 	}
-	return fmt.Sprintf(" ORDER BY %s", q.Order())
+	return " ORDER BY " + q.Order()
+}
+
+// SetSortAliases sets aliases for sorting by SQL expressions.
+// Key of map is a name in "sort" parameter and value is an expression for ORDER BY statement.
+// Aliases aren't validated by validation of "sort" parameter.
+// Example:
+//
+//	q.SetSortAliases(map[string]string{"popularity": "likes + comments"})
+func (q *Query) SetSortAliases(aliases map[string]string) *Query {
+	q.sortAliases = aliases
+	return q
+}
+
+// SetDefaultSort sets sorting which is used when "sort" parameter isn't provided.
+// Keys could have +/- prefix like in "sort" parameter, they aren't validated:
+//
+//	q.SetDefaultSort("-created_at", "id")
+func (q *Query) SetDefaultSort(keys ...string) *Query {
+	q.defaultSort = nil
+	for _, key := range keys {
+		switch {
+		case strings.HasPrefix(key, "-"):
+			q.defaultSort = append(q.defaultSort, Sort{By: key[1:], Desc: true})
+		case strings.HasPrefix(key, "+"):
+			q.defaultSort = append(q.defaultSort, Sort{By: key[1:]})
+		default:
+			q.defaultSort = append(q.defaultSort, Sort{By: key})
+		}
+	}
+	return q
+}
+
+// sortColumn returns SQL expression of sort alias or column of sort key
+func (q *Query) sortColumn(by string) string {
+	if expr, ok := q.sortAliases[by]; ok {
+		return expr
+	}
+	return q.column(by)
 }
 
 // HaveSortBy returns true if request contains sorting by specified in by field name
@@ -263,19 +604,7 @@ func (q *Query) AddORFilters(fn func(query *Query)) *Query {
 		return q
 	}
 
-	firstIdx := 0
-	lastIdx := len(_q.Filters) - 1
-
-	for i := 0; i < len(_q.Filters); i++ {
-		switch i {
-		case firstIdx:
-			_q.Filters[i].OR = StartOR
-		case lastIdx:
-			_q.Filters[i].OR = EndOR
-		default:
-			_q.Filters[i].OR = InOR
-		}
-	}
+	setOR(_q.Filters)
 
 	q.Filters = append(q.Filters, _q.Filters...)
 	return q
@@ -344,31 +673,82 @@ func (q *Query) RemoveFilter(name string) error {
 	return nil
 }
 
+// DisableFilter marks all filters with name as disabled: they are still parsed, validated
+// and returned by GetFilter, but they aren't added to WHERE statement and Args.
+// Unlike RemoveFilter it keeps filters in Filters, eg. to echo applied filters back to client.
+func (q *Query) DisableFilter(name string) error {
+	q.invalidate()
+	found := false
+	for _, v := range q.Filters {
+		if v.Name == name {
+			v.Disabled = true
+			found = true
+		}
+	}
+	if !found {
+		return ErrFilterNotFound
+	}
+	return nil
+}
+
+// RegisterColumnComparison registers a boolean filter which compares two columns.
+// When the filter is true in the query part of URL (eg. ?valid=true) the condition
+// `left <op> right` is added to WHERE statement without arguments.
+// Only EQ, NE, GT, LT, GTE and LTE methods are allowed.
+// Example:
+//   q.RegisterColumnComparison("valid", "start_date", rqp.LTE, "end_date")
+func (q *Query) RegisterColumnComparison(name, left string, m Method, right string) error {
+	switch m {
+	case EQ, NE, GT, LT, GTE, LTE:
+	default:
+		return errors.Wrap(ErrMethodNotAllowed, string(m))
+	}
+	if !isIdentifier(left) {
+		return errors.Wrap(ErrInvalidIdentifier, left)
+	}
+	if !isIdentifier(right) {
+		return errors.Wrap(ErrInvalidIdentifier, right)
+	}
+	if q.columnComparisons == nil {
+		q.columnComparisons = make(map[string]columnComparison)
+	}
+	q.columnComparisons[name] = columnComparison{
+		left:   left,
+		method: m,
+		right:  right,
+	}
+	return nil
+}
+
 // AddValidation adds a validation to Query
 func (q *Query) AddValidation(NameAndTags string, v ValidationFunc) *Query {
 	if q.validations == nil {
 		q.validations = Validations{}
 	}
 	q.validations[NameAndTags] = v
+	q.index = nil
 	return q
 }
 
+// SetMethodValidation adds a validation which is used only for specified method of filter.
+// It's equal to AddValidation("age:gte", v). Validation of the whole filter
+// is used for methods which don't have own validation.
+func (q *Query) SetMethodValidation(name string, m Method, v ValidationFunc) *Query {
+	return q.AddValidation(name+":"+strings.ToLower(string(m)), v)
+}
+
 // RemoveValidation remove a validation from Query
 // You can provide full name of filter with tags or only name of filter:
 // RemoveValidation("id:int") and RemoveValidation("id") are equal
 func (q *Query) RemoveValidation(NameAndOrTags string) error {
 	for k := range q.validations {
-		if k == NameAndOrTags {
+		name := strings.Split(strings.TrimSuffix(k, "!"), ":")[0]
+		if k == NameAndOrTags || name == NameAndOrTags {
 			delete(q.validations, k)
+			delete(q.required, name)
+			q.index = nil
 			return nil
 		}
-		if strings.Contains(k, ":") {
-			parts := strings.Split(k, ":")
-			if parts[0] == NameAndOrTags {
-				delete(q.validations, k)
-				return nil
-			}
-		}
 	}
 	return ErrValidationNotFound
 }
@@ -390,10 +770,45 @@ func (q *Query) Clone() *Query {
 	qNew := &Query{
 		Offset:        q.Offset,
 		Limit:         q.Limit,
+		Distinct:      q.Distinct,
 		delimiterIN:   q.delimiterIN,
 		delimiterOR:   q.delimiterOR,
 		ignoreUnknown: q.ignoreUnknown,
+		lastValue:     q.lastValue,
+		trimValues:    q.trimValues,
+		maxFilters:    q.maxFilters,
+		maxSortKeys:   q.maxSortKeys,
+		maxFields:     q.maxFields,
+		maxValueLen:   q.maxValueLen,
+		maxINSize:     q.maxINSize,
+		searchKey:     q.searchKey,
+		join:          q.join,
+		dialect:       q.dialect,
+		quoteNames:    q.quoteNames,
+		strictNames:   q.strictNames,
+		placeholder:   q.placeholder,
+		useAnyIN:      q.useAnyIN,
+		arrayValuer:   q.arrayValuer,
+		useCursor:     q.useCursor,
+		pagination:    q.pagination,
+		defaultLimit:  q.defaultLimit,
+		maxLimit:      q.maxLimit,
+		clampLimit:    q.clampLimit,
+		cursorBefore:  q.cursorBefore,
+		emptyValue:    q.emptyValue,
+		wildcard:      q.wildcard,
+		likePolicy:    q.likePolicy,
+		ftsTemplate:   q.ftsTemplate,
+		clock:         q.clock,
+		softDelete:    q.softDelete,
+		withDeleted:   q.withDeleted,
+		formValues:    q.formValues,
+		syntax:        q.syntax,
+		qualifier:     q.qualifier,
+		index:         q.index, // read-only, it's replaced when validations are changed
 		Error:         q.Error,
+
+		postValidation: q.postValidation,
 	}
 
 	// copy query map
@@ -415,6 +830,102 @@ func (q *Query) Clone() *Query {
 		}
 	}
 
+	// copy required names
+	if q.required != nil {
+		qNew.required = make(map[string]bool)
+		for key := range q.required {
+			qNew.required[key] = true
+		}
+	}
+
+	// copy unknown keys
+	if q.unknown != nil {
+		qNew.unknown = make([]string, len(q.unknown))
+		copy(qNew.unknown, q.unknown)
+	}
+
+	// copy search columns
+	if q.searchColumns != nil {
+		qNew.searchColumns = make([]string, len(q.searchColumns))
+		copy(qNew.searchColumns, q.searchColumns)
+	}
+
+	// copy available fields
+	if q.fields != nil {
+		qNew.fields = make([]string, len(q.fields))
+		copy(qNew.fields, q.fields)
+	}
+
+	// copy column comparisons
+	if q.columnComparisons != nil {
+		qNew.columnComparisons = make(map[string]columnComparison)
+		for key := range q.columnComparisons {
+			qNew.columnComparisons[key] = q.columnComparisons[key]
+		}
+	}
+
+	// copy methods
+	if q.methods != nil {
+		qNew.methods = make(map[Method]string)
+		for key := range q.methods {
+			qNew.methods[key] = q.methods[key]
+		}
+	}
+
+	// copy name mapping
+	if q.nameMapping != nil {
+		qNew.nameMapping = make(Replacer)
+		for key := range q.nameMapping {
+			qNew.nameMapping[key] = q.nameMapping[key]
+		}
+	}
+
+	// copy JSON columns
+	if q.jsonColumns != nil {
+		qNew.jsonColumns = make(map[string]bool)
+		for key := range q.jsonColumns {
+			qNew.jsonColumns[key] = true
+		}
+	}
+
+	// copy layouts of time and date
+	if q.timeLayouts != nil {
+		qNew.timeLayouts = make([]string, len(q.timeLayouts))
+		copy(qNew.timeLayouts, q.timeLayouts)
+	}
+	if q.dateLayouts != nil {
+		qNew.dateLayouts = make([]string, len(q.dateLayouts))
+		copy(qNew.dateLayouts, q.dateLayouts)
+	}
+
+	// copy default sort
+	if q.defaultSort != nil {
+		qNew.defaultSort = make([]Sort, len(q.defaultSort))
+		copy(qNew.defaultSort, q.defaultSort)
+	}
+
+	// copy sort aliases
+	if q.sortAliases != nil {
+		qNew.sortAliases = make(map[string]string)
+		for key := range q.sortAliases {
+			qNew.sortAliases[key] = q.sortAliases[key]
+		}
+	}
+
+	// copy denied fields
+	if q.deniedFields != nil {
+		qNew.deniedFields = make([]string, len(q.deniedFields))
+		copy(qNew.deniedFields, q.deniedFields)
+	}
+
+	// copy relations
+	if q.relations != nil {
+		qNew.relations = make(map[string]Relation, len(q.relations))
+		for key := range q.relations {
+			qNew.relations[key] = q.relations[key]
+		}
+	}
+
 	// copy Fields
 	if q.Fields != nil {
 		qNew.Fields = make([]string, len(q.Fields), cap(q.Fields))
@@ -425,11 +936,32 @@ func (q *Query) Clone() *Query {
 		qNew.Sorts = make([]Sort, len(q.Sorts), cap(q.Sorts))
 		copy(qNew.Sorts, q.Sorts)
 	}
+	// copy Group
+	if q.Group != nil {
+		qNew.Group = make([]string, len(q.Group), cap(q.Group))
+		copy(qNew.Group, q.Group)
+	}
+	// copy forced filters
+	if q.forced != nil {
+		qNew.forced = make([]*Filter, len(q.forced))
+		copy(qNew.forced, q.forced)
+	}
+
 	// copy Filters
 	if q.Filters != nil {
 		qNew.Filters = make([]*Filter, len(q.Filters), cap(q.Filters))
 		copy(qNew.Filters, q.Filters)
 	}
+	// copy includes
+	if q.includes != nil {
+		qNew.includes = make([]string, len(q.includes), cap(q.includes))
+		copy(qNew.includes, q.includes)
+	}
+	// copy Havings
+	if q.Havings != nil {
+		qNew.Havings = make([]*Filter, len(q.Havings), cap(q.Havings))
+		copy(qNew.Havings, q.Havings)
+	}
 
 	return qNew
 }
@@ -446,6 +978,93 @@ func (q *Query) GetFilter(name string) (*Filter, error) {
 	return nil, ErrFilterNotFound
 }
 
+// QuerySummary describes clauses which were built by Query
+type QuerySummary struct {
+	Filters   int      // number of filters in WHERE statement
+	Sorts     []string // sort keys, descending keys have "-" prefix
+	Fields    []string // selected fields, empty means all fields
+	Limit     int
+	Offset    int
+	Paginated bool // true if limit or offset is set
+}
+
+// Summary returns summary of Query, eg. for logging or metrics
+func (q *Query) Summary() QuerySummary {
+	s := QuerySummary{
+		Limit:     q.Limit,
+		Offset:    q.Offset,
+		Paginated: q.Limit > 0 || q.Offset > 0,
+	}
+
+	for _, f := range q.Filters {
+		if !f.Disabled {
+			s.Filters++
+		}
+	}
+
+	if len(q.Fields) > 0 {
+		s.Fields = make([]string, len(q.Fields))
+		copy(s.Fields, q.Fields)
+	}
+
+	for _, v := range q.Sorts {
+		if v.Desc {
+			s.Sorts = append(s.Sorts, "-"+v.By)
+		} else {
+			s.Sorts = append(s.Sorts, v.By)
+		}
+	}
+
+	return s
+}
+
+// SetNameMapping sets columns for names of filters, sorts and fields from query part of URL.
+// Unlike ReplaceNames the mapping is applied while building of statements, so names
+// of parsed filters, sorts and fields are kept, eg. for ToQueryString:
+//
+//	q.SetNameMapping(rqp.Replacer{"createdAt": "created_at", "author": "users.name"})
+//	// ?author=tim&sort=-createdAt -> WHERE users.name = ? ORDER BY created_at DESC
+func (q *Query) SetNameMapping(r Replacer) *Query {
+	q.invalidate()
+	q.nameMapping = r
+	return q
+}
+
+// column returns column for name of filter, sort or field
+func (q *Query) column(name string) string {
+	if c, ok := q.nameMapping[name]; ok {
+		c = q.qualify(c)
+		if q.quoteNames && isIdentifier(c) {
+			return q.quote(c)
+		}
+		return c
+	}
+	if exp, alias, ok := splitComputed(name); ok {
+		return q.computedColumn(exp, alias)
+	}
+	if exp, ok := q.jsonPath(name); ok {
+		return exp
+	}
+	name = q.qualify(name)
+	if q.quoteNames {
+		return q.quote(name)
+	}
+	return name
+}
+
+// joinColumns returns columns for names of filters, sorts or fields separated by comma
+func (q *Query) joinColumns(names []string) string {
+	var b strings.Builder
+	b.Grow(12 * len(names))
+	for i := range names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(q.column(names[i]))
+	}
+	return b.String()
+}
+
 // Replacer struct for ReplaceNames method
 type Replacer map[string]string
 
@@ -459,6 +1078,7 @@ type Replacer map[string]string
 //	   "user_id": "users.user_id",
 //   })
 func (q *Query) ReplaceNames(r Replacer) {
+	q.invalidate()
 
 	for name, newname := range r {
 		for i, v := range q.Filters {
@@ -482,45 +1102,130 @@ func (q *Query) ReplaceNames(r Replacer) {
 
 // Where returns list of filters for WHERE statement
 // return example: `id > 0 AND email LIKE 'some@email.com'`
+// Statement is built once by Parse and rebuilt only if filters or options are changed.
 func (q *Query) Where() string {
-
-	if len(q.Filters) == 0 {
-		return ""
+	if c, ok := q.cached(); ok {
+		return c.where
 	}
 
-	var where string
-	// var OR bool = false
+	where, _ := q.whereFrom(1)
+	return where
+}
 
-	for i := 0; i < len(q.Filters); i++ {
-		filter := q.Filters[i]
+// whereFrom returns list of filters for WHERE statement with placeholders numbered from n.
+// It returns next number.
+func (q *Query) whereFrom(n int) (string, int) {
+	where := q.render(func(filter *Filter) (string, bool) {
+		a, err := filter.where(q)
+		if err != nil {
+			return "", false
+		}
+		if filter.Method != raw {
+			a, n = q.rebind(a, n)
+		}
+		return a, true
+	})
+	return where, n
+}
 
-		prefix := ""
-		suffix := ""
+// render joins conditions of enabled filters returned by fn into WHERE statement
+// with OR statements in parentheses. Filters are skipped if fn returns false.
+// Cursor of keyset pagination and forced filters are added at the end with AND.
+func (q *Query) render(fn func(filter *Filter) (string, bool)) string {
+	var b strings.Builder
+	b.Grow(24 * len(q.Filters))
+
+	filters, and := q.conditions()
+	groups := orGroups(filters)
+	q.renderGroups(&b, groups, q.topLevelJoin(), fn)
+	if len(and) == 0 {
+		return b.String()
+	}
 
-		if filter.OR == StartOR {
-			if i == 0 {
-				prefix = "("
-			} else {
-				prefix = " AND ("
+	var f strings.Builder
+	forced := make([][]*Filter, len(and))
+	for i := range and {
+		forced[i] = and[i : i+1]
+	}
+	q.renderGroups(&f, forced, "AND", fn)
+
+	switch {
+	case b.Len() == 0:
+		return f.String()
+	case f.Len() == 0:
+		return b.String()
+	case q.topLevelJoin() == "OR" && len(groups) > 1:
+		return "(" + b.String() + ") AND " + f.String()
+	default:
+		b.WriteString(" AND ")
+		b.WriteString(f.String())
+		return b.String()
+	}
+}
+
+// renderGroups writes conditions of groups of filters joined by join to b
+func (q *Query) renderGroups(b *strings.Builder, groups [][]*Filter, join string, fn func(filter *Filter) (string, bool)) {
+	// conditions of the current group, the slice is reused for all groups
+	var or []string
+	first := true
+
+	for _, group := range groups {
+		or = or[:0]
+		for _, filter := range group {
+			if filter.Disabled {
+				continue
+			}
+			if a, ok := fn(filter); ok {
+				or = append(or, a)
 			}
-		} else if filter.OR == InOR {
-			prefix = " OR "
-		} else if filter.OR == EndOR {
-			prefix = " OR "
-			suffix = ")"
-		} else if i > 0 && len(where) > 0 {
-			prefix = " AND "
+		}
+		if len(or) == 0 {
+			continue
 		}
 
-		if a, err := filter.Where(); err == nil {
-			where += fmt.Sprintf("%s%s%s", prefix, a, suffix)
-		} else {
+		if !first {
+			b.WriteByte(' ')
+			b.WriteString(join)
+			b.WriteByte(' ')
+		}
+		first = false
+
+		if len(or) == 1 {
+			b.WriteString(or[0])
 			continue
 		}
+		b.WriteByte('(')
+		for i, a := range or {
+			if i > 0 {
+				b.WriteString(" OR ")
+			}
+			b.WriteString(a)
+		}
+		b.WriteByte(')')
+	}
+}
+
+// groups returns filters split into OR statements, single filters are groups of one filter.
+// Groups are parts of q.Filters.
+func (q *Query) groups() [][]*Filter {
+	return orGroups(q.Filters)
+}
+
+// orGroups returns filters split into OR statements, groups are parts of filters
+func orGroups(filters []*Filter) [][]*Filter {
+	groups := make([][]*Filter, 0, len(filters))
 
+	for i := 0; i < len(filters); i++ {
+		start := i
+		if filters[i].OR == StartOR {
+			for i+1 < len(filters) && filters[i].OR != EndOR {
+				i++
+			}
+		}
+		groups = append(groups, filters[start:i+1:i+1])
 	}
 
-	return where
+	return groups
 }
 
 // WHERE returns list of filters for WHERE SQL statement with `WHERE` word
@@ -528,30 +1233,38 @@ func (q *Query) Where() string {
 // Return example: ` WHERE id > 0 AND email LIKE 'some@email.com'`
 //
 func (q *Query) WHERE() string {
-
-	if len(q.Filters) == 0 {
+	where := q.Where()
+	if len(where) == 0 {
 		return ""
 	}
 
-	return " WHERE " + q.Where()
+	return " WHERE " + where
 }
 
-// Args returns slice of arguments for WHERE statement
+// Args returns slice of arguments for WHERE statement.
+// Arguments built by Parse are shared between calls, they must not be modified.
 func (q *Query) Args() []interface{} {
+	if c, ok := q.cached(); ok {
+		return c.args[:len(c.args):len(c.args)]
+	}
 
-	args := make([]interface{}, 0)
+	args := make([]interface{}, 0, len(q.Filters))
 
-	if len(q.Filters) == 0 {
-		return args
+	filters, forced := q.conditions()
+	if len(forced) > 0 {
+		filters = append(append([]*Filter{}, filters...), forced...)
 	}
 
-	for i := 0; i < len(q.Filters); i++ {
-		filter := q.Filters[i]
+	for i := 0; i < len(filters); i++ {
+		filter := filters[i]
+		if filter.Disabled {
+			continue
+		}
 		if (filter.Method == IS || filter.Method == NOT) && filter.Value == NULL {
 			continue
 		}
 
-		if a, err := filter.Args(); err == nil {
+		if a, err := filter.args(q); err == nil {
 			args = append(args, a...)
 		} else {
 			continue
@@ -563,15 +1276,68 @@ func (q *Query) Args() []interface{} {
 
 // SQL returns whole SQL statement
 func (q *Query) SQL(table string) string {
-	return fmt.Sprintf(
-		"%s FROM %s%s%s%s%s",
-		q.SELECT(),
-		table,
-		q.WHERE(),
-		q.ORDER(),
-		q.LIMIT(),
-		q.OFFSET(),
-	)
+	if q.joined() {
+		return q.Qualified(table).SQL(table)
+	}
+
+	order := q.ORDER()
+	pagination := q.PAGINATION()
+
+	// MSSQL doesn't allow OFFSET without ORDER BY
+	if q.dialect == DialectMSSQL && len(order) == 0 && len(pagination) > 0 {
+		order = " ORDER BY (SELECT NULL)"
+	}
+
+	sel, where, group, having := q.SELECT(), q.WHERE(), q.GROUPBY(), q.HAVING()
+
+	join := q.JOIN(table)
+
+	var b strings.Builder
+	b.Grow(len(sel) + len(" FROM ") + len(table) + len(join) + len(where) + len(group) + len(having) + len(order) + len(pagination))
+	b.WriteString(sel)
+	b.WriteString(" FROM ")
+	b.WriteString(table)
+	b.WriteString(join)
+	b.WriteString(where)
+	b.WriteString(group)
+	b.WriteString(having)
+	b.WriteString(order)
+	b.WriteString(pagination)
+
+	return b.String()
+}
+
+// CountSQL returns SQL statement which counts all rows matched by filters
+// without sorting and pagination, eg. for total number of rows in paginated response.
+// Groups are counted if the Query is grouped (see GroupBy), distinct rows are counted if it's distinct.
+// Arguments are the same as SQLWithArgs returns.
+//
+// Return example: `SELECT COUNT(*) FROM table WHERE id > ?`
+func (q *Query) CountSQL(table string) string {
+	if q.joined() {
+		return q.Qualified(table).CountSQL(table)
+	}
+	if len(q.Group) > 0 || len(q.Havings) > 0 {
+		return fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s%s%s%s%s) AS grouped", table, q.JOIN(table), q.WHERE(), q.GROUPBY(), q.HAVING())
+	}
+	if q.Distinct {
+		return fmt.Sprintf("SELECT COUNT(*) FROM (%s FROM %s%s%s) AS distinct_rows", q.SELECT(), table, q.JOIN(table), q.WHERE())
+	}
+	return fmt.Sprintf("SELECT COUNT(*) FROM %s%s%s", table, q.JOIN(table), q.WHERE())
+}
+
+// SQLWithArgs returns whole SQL statement with SELECT, FROM, WHERE, ORDER BY and pagination
+// statements and arguments for it. Arguments of HAVING filters follow arguments of WHERE.
+//
+//	rows, err := db.Query(q.SQLWithArgs("users"))
+func (q *Query) SQLWithArgs(table string) (string, []interface{}) {
+	if q.joined() {
+		return q.Qualified(table).SQLWithArgs(table)
+	}
+	if len(q.Havings) > 0 {
+		return q.SQL(table), append(q.Args(), q.HavingArgs()...)
+	}
+	return q.SQL(table), q.Args()
 }
 
 // SetUrlQuery change url in the Query for parsing
@@ -593,9 +1359,30 @@ func (q *Query) SetUrlString(Url string) error {
 	return err
 }
 
-// SetValidations change validations rules for the instance
+// SetPostValidation sets validation func which is called at the end of Parse
+// when all filters are parsed and validated. It's useful for validations
+// which depend on several filters. Error of the func is returned by Parse.
+// Example:
+//
+//	q.SetPostValidation(func(q *rqp.Query) error {
+//		min, err1 := q.GetFilter("min_price")
+//		max, err2 := q.GetFilter("max_price")
+//		if err1 == nil && err2 == nil && min.Value.(int) > max.Value.(int) {
+//			return errors.New("max_price must be greater then min_price")
+//		}
+//		return nil
+//	})
+func (q *Query) SetPostValidation(fn func(q *Query) error) *Query {
+	q.postValidation = fn
+	return q
+}
+
+// SetValidations change validations rules for the instance.
+// Map v is used as is, so it must not be changed after the call: use AddValidation
+// and RemoveValidation or call SetValidations again.
 func (q *Query) SetValidations(v Validations) *Query {
 	q.validations = v
+	q.index = nil
 	return q
 }
 
@@ -604,6 +1391,7 @@ func New() *Query {
 	return &Query{
 		delimiterIN: ",",
 		delimiterOR: "|",
+		searchKey:   "q",
 	}
 }
 
@@ -621,15 +1409,41 @@ func NewParse(q url.Values, v Validations) (*Query, error) {
 
 // Parse parses the query of URL
 // as query you can use standart http.Request query by r.URL.Query()
+// Filters are added in order of sorted keys of the query, values of the same key
+// and fields keep their order, so the same query always produces the same
+// WHERE statement, arguments and errors regardless of order of keys in URL.
 func (q *Query) Parse() (err error) {
 
 	// clean previously parsed filters
 	q.cleanFilters()
+	q.cursorBefore = false
+	q.withDeleted = false
 
 	// construct a slice with required names of filters
 	requiredNames := q.requiredNames()
 
-	for key, values := range q.query {
+	query, err := q.syntaxQuery()
+	if err != nil {
+		return err
+	}
+
+	// iterate keys in sorted order to build the same WHERE statement for the same query
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// cursor is parsed after sort
+	cursors := make(map[string][]string)
+	// page is parsed after per_page
+	pages := make(map[string][]string)
+	limitOrOffset := false
+	hasLimit := false
+	hasSort := false
+
+	for _, key := range keys {
+		values := query[key]
 
 		low := strings.ToLower(key)
 
@@ -639,18 +1453,72 @@ func (q *Query) Parse() (err error) {
 			err = q.parseFields(values, q.validations[low])
 			delete(requiredNames, low)
 		case "offset", "offset[in]":
+			if !q.acceptsPagination(PaginationOffset) {
+				err = q.notAccepted(key)
+				break
+			}
 			low = strings.ReplaceAll(low, "[in]", "")
 			err = q.parseOffset(values, q.validations[low])
 			delete(requiredNames, low)
+			limitOrOffset = true
 		case "limit", "limit[in]":
+			if !q.acceptsPagination(PaginationOffset) {
+				err = q.notAccepted(key)
+				break
+			}
 			low = strings.ReplaceAll(low, "[in]", "")
 			err = q.parseLimit(values, q.validations[low])
 			delete(requiredNames, low)
+			limitOrOffset = true
+			hasLimit = true
 		case "sort", "sort[in]":
 			low = strings.ReplaceAll(low, "[in]", "")
 			err = q.parseSort(values, q.validations[low])
 			delete(requiredNames, low)
+			hasSort = true
+		case "group", "group[in]":
+			low = strings.ReplaceAll(low, "[in]", "")
+			err = q.parseGroup(values, q.validations[low])
+			delete(requiredNames, low)
+		case "distinct":
+			err = q.parseDistinct(values)
+			delete(requiredNames, low)
+		case "include", "include[in]":
+			low = strings.ReplaceAll(low, "[in]", "")
+			err = q.parseInclude(values, q.validations[low])
+			delete(requiredNames, low)
 		default:
+			if q.isPageKey(low) {
+				pages[low] = values
+				delete(requiredNames, low)
+				break
+			}
+			if q.isCursorKey(low) {
+				cursors[low] = values
+				delete(requiredNames, low)
+				break
+			}
+			if c, ok := q.columnComparisons[key]; ok {
+				err = q.parseColumnComparison(key, values, c)
+				delete(requiredNames, key)
+				break
+			}
+			if key == q.searchKey && len(q.searchColumns) > 0 {
+				err = q.parseSearch(values, q.validations[key])
+				delete(requiredNames, key)
+				break
+			}
+			if q.isWithDeletedKey(key) {
+				err = q.parseWithDeleted(values)
+				delete(requiredNames, key)
+				break
+			}
+			if strings.HasPrefix(key, havingKey+"[") {
+				if err := q.parseHaving(key, values); err != nil {
+					return err
+				}
+				break
+			}
 			if len(values) == 0 {
 				return errors.Wrap(ErrBadFormat, key)
 			}
@@ -667,29 +1535,75 @@ func (q *Query) Parse() (err error) {
 		}
 	}
 
-	// check required filters
+	if !hasSort && len(q.defaultSort) > 0 {
+		q.Sorts = make([]Sort, len(q.defaultSort))
+		copy(q.Sorts, q.defaultSort)
+	}
+
+	if _, ok := pages["per_page"]; !ok && !hasLimit && q.defaultLimit > 0 {
+		q.Limit = q.defaultLimit
+	}
+
+	if len(pages) > 0 {
+		if limitOrOffset {
+			return errors.Wrap(ErrBadFormat, "page can't be used with limit or offset")
+		}
+		if err := q.parsePage(pages); err != nil {
+			return err
+		}
+	}
+
+	if len(cursors) > 0 {
+		if err := q.parseCursor(cursors); err != nil {
+			return err
+		}
+	}
+
+	if q.maxFilters > 0 && len(q.Filters) > q.maxFilters {
+		return ErrTooManyFilters
+	}
+
+	if err := q.checkGroup(); err != nil {
+		return err
+	}
 
+	// check required filters in sorted order to return the same error for the same query
+
+	names := make([]string, 0, len(requiredNames))
 	for requiredName := range requiredNames {
-		if !q.HaveFilter(requiredName) {
+		names = append(names, requiredName)
+	}
+	sort.Strings(names)
+
+	for _, requiredName := range names {
+		if !q.HaveFilter(requiredName) && !q.HaveHaving(requiredName) {
 			return errors.Wrap(ErrRequired, requiredName)
 		}
 	}
 
+	if q.postValidation != nil {
+		if err := q.postValidation(q); err != nil {
+			return err
+		}
+	}
+
+	q.build()
+
 	return nil
 }
 
-// requiredNames returns list of required filters
+// requiredNames returns list of required filters.
+// Required marks are removed from keys of validations and names are kept in q.required.
 func (q *Query) requiredNames() map[string]bool {
-	required := make(map[string]bool)
-
 	for name, f := range q.validations {
-		if strings.Contains(name, ":required") {
+		if newname, ok := trimRequired(name); ok {
 			oldname := name
 			// oldname = arg1:required
 			// oldname = arg2:int:required
-			newname := strings.Replace(name, ":required", "", 1)
+			// oldname = arg3:int!
 			// newname = arg1
 			// newname = arg2:int
+			// newname = arg3:int
 
 			if strings.Contains(newname, ":") {
 				parts := strings.Split(newname, ":")
@@ -700,55 +1614,93 @@ func (q *Query) requiredNames() map[string]bool {
 			// name = arg1
 			// name = arg2
 
+			if q.required == nil {
+				q.required = make(map[string]bool)
+			}
+
 			low := strings.ToLower(name)
 			switch low {
 			case "fields", "fields[in]",
 				"offset", "offset[in]",
 				"limit", "limit[in]",
-				"sort", "sort[in]":
+				"sort", "sort[in]",
+				"group", "group[in]",
+				"distinct",
+				"include", "include[in]":
 				low = strings.ReplaceAll(low, "[in]", "")
-				required[low] = true
+				q.required[low] = true
 			default:
-				required[name] = true
+				q.required[name] = true
 			}
 
 			q.validations[newname] = f
 			delete(q.validations, oldname)
+			q.index = nil
 		}
 	}
+
+	required := make(map[string]bool, len(q.required))
+	for name := range q.required {
+		required[name] = true
+	}
 	return required
 }
 
+// trimRequired removes required mark from key of validation: ":required" tag or "!" suffix
+func trimRequired(key string) (string, bool) {
+	if strings.Contains(key, ":required") {
+		return strings.TrimSuffix(strings.Replace(key, ":required", "", 1), "!"), true
+	}
+	if strings.HasSuffix(key, "!") {
+		return strings.TrimSuffix(key, "!"), true
+	}
+	return key, false
+}
+
 // parseFilter parses one filter
 func (q *Query) parseFilter(key, value string) error {
 	value = strings.TrimSpace(value)
 
 	if len(value) == 0 {
-		return errors.Wrap(ErrEmptyValue, key)
+		switch q.emptyValue {
+		case EmptyValueSkip:
+			return nil
+		case EmptyValueDefault, EmptyValueError:
+			return errors.Wrap(ErrEmptyValue, key)
+		}
 	}
 
 	if strings.Contains(value, q.delimiterOR) { // OR multiple filter
+		// parts without key use key of the previous part: status=active|pending
 		parts := strings.Split(value, q.delimiterOR)
+		filters := make([]*Filter, 0, len(parts))
 		for i, v := range parts {
 			if i > 0 {
-				u := strings.Split(v, "=")
-				if len(u) < 2 {
-					return errors.Wrap(ErrBadFormat, key)
+				if u := strings.SplitN(v, "=", 2); len(u) == 2 {
+					key = u[0]
+					v = u[1]
 				}
-				key = u[0]
-				v = u[1]
 			}
 
 			v := strings.TrimSpace(v)
 			if len(v) == 0 {
-				return errors.Wrap(ErrEmptyValue, key)
+				switch q.emptyValue {
+				case EmptyValueSkip:
+					continue
+				case EmptyValueDefault, EmptyValueError:
+					return errors.Wrap(ErrEmptyValue, key)
+				}
 			}
 
-			filter, err := newFilter(key, v, q.delimiterIN, q.validations)
+			filter, err := q.newFilter(key, v)
 
 			if err != nil {
+				if err == errSkipFilter {
+					continue
+				}
 				if err == ErrValidationNotFound {
 					if q.ignoreUnknown {
+						q.addUnknown(key)
 						continue
 					} else {
 						return errors.Wrap(ErrFilterNotFound, key)
@@ -757,23 +1709,21 @@ func (q *Query) parseFilter(key, value string) error {
 				return errors.Wrap(err, key)
 			}
 
-			// set OR
-			if i == 0 {
-				filter.OR = StartOR
-			} else if i == len(parts)-1 {
-				filter.OR = EndOR
-			} else {
-				filter.OR = InOR
-			}
-
-			q.Filters = append(q.Filters, filter)
+			filters = append(filters, filter)
 		}
+
+		setOR(filters)
+		q.Filters = append(q.Filters, filters...)
 	} else { // Single filter
-		filter, err := newFilter(key, value, q.delimiterIN, q.validations)
+		filter, err := q.newFilter(key, value)
 		if err != nil {
+			if err == errSkipFilter {
+				return nil
+			}
 			if err == ErrValidationNotFound {
 				err = ErrFilterNotFound
 				if q.ignoreUnknown {
+					q.addUnknown(key)
 					return nil
 				}
 			}
@@ -786,22 +1736,72 @@ func (q *Query) parseFilter(key, value string) error {
 	return nil
 }
 
+// setOR sets OR states of filters to put them into one OR statement
+func setOR(filters []*Filter) {
+	if len(filters) < 2 {
+		return
+	}
+
+	for i := range filters {
+		switch i {
+		case 0:
+			filters[i].OR = StartOR
+		case len(filters) - 1:
+			filters[i].OR = EndOR
+		default:
+			filters[i].OR = InOR
+		}
+	}
+}
+
 // clean the filters slice
 func (q *Query) cleanFilters() {
+	q.invalidate()
 	if len(q.Filters) > 0 {
 		for i := range q.Filters {
 			q.Filters[i] = nil
 		}
 		q.Filters = nil
 	}
+	q.Havings = nil
+	q.unknown = nil
 }
 
-func (q *Query) parseSort(value []string, validate ValidationFunc) error {
+// addUnknown remembers the key of ignored filter
+func (q *Query) addUnknown(key string) {
+	if !stringInSlice(key, q.unknown) {
+		q.unknown = append(q.unknown, key)
+	}
+}
+
+// notAccepted handles key which isn't accepted by the Query like unknown filter
+func (q *Query) notAccepted(key string) error {
+	if q.ignoreUnknown {
+		q.addUnknown(key)
+		return nil
+	}
+	return ErrFilterNotFound
+}
+
+// singleValue checks that reserved parameter has only one value.
+// If q.lastValue is set the last value of several ones is used.
+func (q *Query) singleValue(value []string) ([]string, error) {
+	if q.lastValue && len(value) > 1 {
+		return value[len(value)-1:], nil
+	}
 	if len(value) != 1 {
-		return ErrBadFormat
+		return nil, errors.Wrapf(ErrBadFormat, "expected 1 value, got %d", len(value))
 	}
+	return value, nil
+}
 
-	if validate == nil {
+func (q *Query) parseSort(value []string, validate ValidationFunc) error {
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
+	if validate == nil && len(q.sortAliases) == 0 {
 		return ErrValidationNotFound
 	}
 
@@ -812,6 +1812,10 @@ func (q *Query) parseSort(value []string, validate ValidationFunc) error {
 
 	list = cleanSliceString(list)
 
+	if q.maxSortKeys > 0 && len(list) > q.maxSortKeys {
+		return ErrTooManySortKeys
+	}
+
 	sort := make([]Sort, 0)
 
 	for _, v := range list {
@@ -833,7 +1837,18 @@ func (q *Query) parseSort(value []string, validate ValidationFunc) error {
 			desc = false
 		}
 
-		if validate != nil {
+		if err := q.checkDenied(by); err != nil {
+			return err
+		}
+
+		// aliases are defined by developer so they aren't validated
+		if _, ok := q.sortAliases[by]; !ok {
+			if err := q.checkIdentifier(by); err != nil {
+				return err
+			}
+			if validate == nil {
+				return ErrValidationNotFound
+			}
 			if err := validate(by); err != nil {
 				return err
 			}
@@ -851,12 +1866,17 @@ func (q *Query) parseSort(value []string, validate ValidationFunc) error {
 }
 
 func (q *Query) parseFields(value []string, validate ValidationFunc) error {
-	if len(value) != 1 {
-		return ErrBadFormat
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
 	}
 
 	if validate == nil {
-		return ErrValidationNotFound
+		if len(q.fields) == 0 {
+			return ErrValidationNotFound
+		}
+		// available fields are allowed if there is no validation of fields
+		validate = q.availableFields()
 	}
 
 	list := value
@@ -866,30 +1886,140 @@ func (q *Query) parseFields(value []string, validate ValidationFunc) error {
 
 	list = cleanSliceString(list)
 
-	if validate != nil {
-		for _, v := range list {
+	if q.maxFields > 0 && len(list) > q.maxFields {
+		return ErrTooManyFields
+	}
+
+	excluded := 0
+	for i, v := range list {
+		if strings.HasPrefix(v, "-") {
+			excluded++
+			list[i] = v[1:]
+		}
+	}
+	if excluded > 0 && excluded < len(list) {
+		return errors.Wrap(ErrBadFormat, "inclusion and exclusion of fields can't be mixed")
+	}
+
+	for _, v := range list {
+		// computed fields are allowed by validation only: `"fields": rqp.In("id", "count(*) AS total")`
+		if _, _, ok := splitComputed(v); !ok || excluded > 0 {
+			if err := q.checkIdentifier(v); err != nil {
+				return err
+			}
+		}
+		if excluded == 0 {
+			if err := q.checkDenied(v); err != nil {
+				return err
+			}
+		}
+		if validate != nil {
 			if err := validate(v); err != nil {
 				return err
 			}
 		}
 	}
 
+	if excluded > 0 {
+		return q.excludeFields(list)
+	}
+
 	q.Fields = list
 	return nil
 }
 
-func (q *Query) parseOffset(value []string, validate ValidationFunc) error {
+// excludeFields sets Fields to available fields except excluded ones
+func (q *Query) excludeFields(excluded []string) error {
+	if len(q.fields) == 0 {
+		return errors.Wrap(ErrBadFormat, "exclusion of fields requires available fields")
+	}
 
-	if len(value) != 1 {
+	var fields []string
+	for _, f := range q.fields {
+		if !stringInSlice(f, excluded) && q.checkDenied(f) == nil {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return errors.Wrap(ErrBadFormat, "all fields are excluded")
+	}
+
+	q.Fields = fields
+	return nil
+}
+
+// parseSearch adds LIKE filters for every search column into one OR statement
+func (q *Query) parseSearch(value []string, validate ValidationFunc) error {
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
+	s := strings.TrimSpace(value[0])
+	if len(s) == 0 {
+		return nil
+	}
+
+	if err := q.checkValueLength(s); err != nil {
+		return err
+	}
+
+	if validate != nil {
+		if err := validate(s); err != nil {
+			return err
+		}
+	}
+
+	filters := make([]*Filter, len(q.searchColumns))
+	for i, col := range q.searchColumns {
+		filters[i] = &Filter{
+			Key:    q.searchKey,
+			Name:   col,
+			Method: CONTAINS_STR,
+			Value:  s,
+		}
+	}
+
+	setOR(filters)
+	q.Filters = append(q.Filters, filters...)
+
+	return nil
+}
+
+// parseColumnComparison adds condition between two columns if value is true
+func (q *Query) parseColumnComparison(key string, value []string, c columnComparison) error {
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
+	b, err := strconv.ParseBool(strings.TrimSpace(value[0]))
+	if err != nil {
 		return ErrBadFormat
 	}
 
+	if b {
+		q.Filters = append(q.Filters, &Filter{
+			Key:    key,
+			Name:   fmt.Sprintf("%s %s %s", c.left, q.translate(c.method), c.right),
+			Method: raw,
+		})
+	}
+
+	return nil
+}
+
+func (q *Query) parseOffset(value []string, validate ValidationFunc) error {
+
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
 	if len(value[0]) == 0 {
 		return ErrBadFormat
 	}
 
-	var err error
-
 	i, err := strconv.Atoi(value[0])
 	if err != nil {
 		return ErrBadFormat
@@ -912,16 +2042,15 @@ func (q *Query) parseOffset(value []string, validate ValidationFunc) error {
 
 func (q *Query) parseLimit(value []string, validate ValidationFunc) error {
 
-	if len(value) != 1 {
-		return ErrBadFormat
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
 	}
 
 	if len(value[0]) == 0 {
 		return ErrBadFormat
 	}
 
-	var err error
-
 	i, err := strconv.Atoi(value[0])
 	if err != nil {
 		return ErrBadFormat
@@ -931,6 +2060,13 @@ func (q *Query) parseLimit(value []string, validate ValidationFunc) error {
 		return errors.Wrapf(ErrNotInScope, "%d", i)
 	}
 
+	if q.maxLimit > 0 && i > q.maxLimit {
+		if !q.clampLimit {
+			return errors.Wrapf(ErrNotInScope, "%d", i)
+		}
+		i = q.maxLimit
+	}
+
 	if validate != nil {
 		if err := validate(i); err != nil {
 			return err
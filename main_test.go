@@ -1,6 +1,7 @@
 package rqp
 
 import (
+	"fmt"
 	"net/url"
 	"reflect"
 	"testing"
@@ -157,6 +158,38 @@ func TestLimit(t *testing.T) {
 	}
 }
 
+func TestDefaultAndMaxLimit(t *testing.T) {
+	q := New().SetDefaultLimit(25).SetMaxLimit(100)
+
+	assert.NoError(t, q.SetUrlString("?"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " LIMIT 25", q.LIMIT())
+
+	assert.NoError(t, q.SetUrlString("?limit=100"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, 100, q.Limit)
+
+	assert.NoError(t, q.SetUrlString("?limit=100000"))
+	err := q.Parse()
+	assert.EqualError(t, err, "limit: 100000: not in scope")
+	assert.Equal(t, ErrNotInScope, errors.Cause(err))
+
+	q.SetClampLimit(true)
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, 100, q.Limit)
+	QueryEqual(t, q, q.Clone())
+
+	// the same for page pagination
+	q.SetPaginationStyle(PaginationPage)
+	assert.NoError(t, q.SetUrlString("?page=3"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " LIMIT 25 OFFSET 50", q.PAGINATION())
+
+	assert.NoError(t, q.SetUrlString("?page=3&per_page=1000"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " LIMIT 100 OFFSET 200", q.PAGINATION())
+}
+
 func TestSort(t *testing.T) {
 
 	cases := []struct {
@@ -217,7 +250,7 @@ func TestWhere(t *testing.T) {
 		{url: "?id[nin]=1.2,1.2", expected: "", err: "id[nin]: bad format"},
 		{url: "?id[test]=1", expected: "", err: "id[test]: unknown method"},
 		{url: "?id[like]=1", expected: "", err: "id[like]: method are not allowed"},
-		{url: "?id=1,2", expected: "", err: "id: method are not allowed"},
+		{url: "?id=1,2", expected: "", err: "id: bad format"},
 		{url: "?id=4", expected: " WHERE id = ?"},
 
 		{url: "?id=100", err: "id: can't be greater then 10"},
@@ -237,7 +270,7 @@ func TestWhere(t *testing.T) {
 		{url: "?s[nin]=super,best", expected: " WHERE s NOT IN (?, ?)"},
 		{url: "?s=puper", expected: "", err: "s: puper: not in scope"},
 		{url: "?u=puper", expected: " WHERE u = ?"},
-		{url: "?u[eq]=1,2", expected: "", err: "u[eq]: method are not allowed"},
+		{url: "?u[eq]=1,2", expected: " WHERE u = ?"},
 		{url: "?u[gt]=1", expected: " WHERE u > ?"},
 		{url: "?id[in]=1,2", expected: " WHERE id IN (?, ?)"},
 		{url: "?id[eq]=1&id[eq]=4", expected: " WHERE id = ? AND id = ?"},
@@ -249,8 +282,10 @@ func TestWhere(t *testing.T) {
 		// bool:
 		{url: "?b=true", expected: " WHERE b = ?"},
 		{url: "?b=true1", err: "b: bad format"},
+		{url: "?b=yes", err: "b: bad format"},
+		{url: "?b[ne]=false", expected: " WHERE b != ?"},
 		{url: "?b[not]=true", err: "b[not]: method are not allowed"},
-		{url: "?b[eq]=true,false", err: "b[eq]: method are not allowed"},
+		{url: "?b[eq]=true,false", err: "b[eq]: bad format"},
 	}
 	for _, c := range cases {
 		t.Run(c.url, func(t *testing.T) {
@@ -379,6 +414,51 @@ func TestSQL(t *testing.T) {
 	assert.Equal(t, "SELECT id, status FROM test WHERE some = ? ORDER BY id OFFSET 10", q.SQL("test"))
 }
 
+func TestCountSQL(t *testing.T) {
+	q := NewQV(nil, Validations{"sort": In("id"), "id:int": nil})
+	assert.NoError(t, q.SetUrlString("?sort=-id&limit=10&offset=20&id[gt]=1"))
+	assert.NoError(t, q.Parse())
+
+	assert.Equal(t, "SELECT COUNT(*) FROM test WHERE id > ?", q.CountSQL("test"))
+	assert.Equal(t, []interface{}{1}, q.Args())
+	assert.Equal(t, "SELECT COUNT(*) FROM test", New().CountSQL("test"))
+}
+
+func TestSQLWithArgs(t *testing.T) {
+	q := NewQV(nil, Validations{"fields": In("id", "status"), "sort": In("id"), "id:int": nil, "status": nil})
+	assert.NoError(t, q.SetUrlString("?fields=id,status&sort=-id&limit=10&offset=20&id[in]=1,2&status=new"))
+	assert.NoError(t, q.Parse())
+
+	query, args := q.SQLWithArgs("test")
+	assert.Equal(t, "SELECT id, status FROM test WHERE id IN (?, ?) AND status = ? ORDER BY id DESC LIMIT 10 OFFSET 20", query)
+	assert.Equal(t, []interface{}{1, 2, "new"}, args)
+
+	query, args = New().SQLWithArgs("test")
+	assert.Equal(t, "SELECT * FROM test", query)
+	assert.Equal(t, []interface{}{}, args)
+}
+
+func TestSetNameMapping(t *testing.T) {
+	q := NewQV(nil, Validations{
+		"fields":         In("id", "createdAt", "author"),
+		"sort":           In("id", "createdAt"),
+		"author":         nil,
+		"createdAt[gte]": nil,
+		"id:int":         nil,
+	}).SetNameMapping(Replacer{"createdAt": "created_at", "author": "users.name"})
+
+	assert.NoError(t, q.SetUrlString("?fields=id,createdAt,author&sort=-createdAt,id&author=tim&id[in]=1,2"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "SELECT id, created_at, users.name FROM posts WHERE users.name = ? AND id IN (?, ?) ORDER BY created_at DESC, id", q.SQL("posts"))
+
+	// parsed names are kept
+	assert.True(t, q.HaveFilter("author"))
+	assert.True(t, q.HaveSortBy("createdAt"))
+	assert.Equal(t, []string{"id", "createdAt", "author"}, q.Fields)
+	assert.Equal(t, "author=tim&fields=id%2CcreatedAt%2Cauthor&id%5Bin%5D=1%2C2&sort=-createdAt%2Cid", q.ToQueryString())
+	QueryEqual(t, q, q.Clone())
+}
+
 func TestReplaceFiltersNames(t *testing.T) {
 	URL, err := url.Parse("?fields=one&sort=one&one=123&another=yes")
 	assert.NoError(t, err)
@@ -444,6 +524,71 @@ func TestRequiredFilter(t *testing.T) {
 	assert.True(t, present)
 }
 
+func TestParseOrder(t *testing.T) {
+	validations := Validations{"fields": In("id", "name"), "id:int": nil, "name": nil, "age:int": nil, "city": nil}
+	urls := []string{
+		"?fields=name,id&name=tim&id[in]=3,1,2&age[gt]=10&city=a|city=b",
+		"?city=a|city=b&age[gt]=10&id[in]=3,1,2&name=tim&fields=name,id",
+		"?id[in]=3,1,2&fields=name,id&city=a|city=b&name=tim&age[gt]=10",
+	}
+	for _, u := range urls {
+		for i := 0; i < 10; i++ {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(u))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, "SELECT name, id FROM t WHERE age > ? AND (city = ? OR city = ?) AND id IN (?, ?, ?) AND name = ?", q.SQL("t"))
+			assert.Equal(t, []interface{}{10, "a", "b", 3, 1, 2, "tim"}, q.Args())
+		}
+	}
+}
+
+func TestRequiredMark(t *testing.T) {
+	q := NewQV(nil, Validations{
+		"tenant_id:int!": nil,
+		"name!":          nil,
+		"limit!":         nil,
+		"id:int":         nil,
+	})
+
+	assert.NoError(t, q.SetUrlString("?tenant_id=1&name=tim&limit=10"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "name = ? AND tenant_id = ?", q.Where())
+
+	// filters are required for every parse
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?name=tim&limit=10", err: "tenant_id: required"},
+		{url: "?tenant_id=1&limit=10", err: "name: required"},
+		{url: "?tenant_id=1&name=tim", err: "limit: required"},
+	}
+	for _, c := range cases {
+		assert.NoError(t, q.SetUrlString(c.url))
+		assert.EqualError(t, q.Parse(), c.err)
+	}
+
+	// the first missing filter in sorted order is reported
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, q.SetUrlString("?"))
+		assert.EqualError(t, q.Parse(), "limit: required")
+	}
+
+	// type tag is kept
+	assert.NoError(t, q.SetUrlString("?tenant_id=a&name=tim&limit=10"))
+	assert.EqualError(t, q.Parse(), "tenant_id: bad format")
+	QueryEqual(t, q, q.Clone())
+
+	assert.NoError(t, q.RemoveValidation("limit"))
+	assert.NoError(t, q.SetUrlString("?tenant_id=1&name=tim"))
+	assert.NoError(t, q.Parse())
+
+	// mark could be removed before parse
+	q = NewQV(nil, Validations{"name!": nil})
+	assert.NoError(t, q.RemoveValidation("name"))
+	assert.NoError(t, q.Parse())
+}
+
 func TestAddField(t *testing.T) {
 	q := New()
 	q.SetUrlString("?test=ok")
@@ -478,6 +623,18 @@ func Test_ignoreUnknown(t *testing.T) {
 
 }
 
+func TestUnknown(t *testing.T) {
+	q := New().AddValidation("id:int", nil).IgnoreUnknownFilters(true)
+	assert.NoError(t, q.SetUrlString("?id=10&name=tim&age[gt]=1|age[lt]=2|id=1"))
+	assert.NoError(t, q.Parse())
+	assert.ElementsMatch(t, []string{"name", "age[gt]", "age[lt]"}, q.Unknown())
+	assert.Len(t, q.Filters, 2)
+
+	assert.NoError(t, q.SetUrlString("?id=10"))
+	assert.NoError(t, q.Parse())
+	assert.Empty(t, q.Unknown())
+}
+
 func TestRemoveValidation(t *testing.T) {
 	q := New()
 
@@ -579,6 +736,13 @@ func TestEmptySliceFilterWithAnotherFilter(t *testing.T) {
 	q := New().AddFilter("id", IN, []string{})
 	q.AddFilter("another_id", EQ, uuid.New().String())
 	t.Log(q.SQL("test"))
+
+	// empty IN is skipped
+	q = New().AddFilter("id", IN, []int{}).AddFilter("x", EQ, 1)
+	assert.Equal(t, "x = ?", q.Where())
+	assert.Equal(t, []interface{}{1}, q.Args())
+	q = New().AddFilter("x", EQ, 1).AddFilter("id", NIN, []int{})
+	assert.Equal(t, "x = ?", q.Where())
 }
 
 func TestQuery_AddORFilters(t *testing.T) {
@@ -653,3 +817,717 @@ func QueryEqual(t *testing.T, q, got *Query) {
 		t.Errorf("q.Filters = %v , want = %v", got.Filters, q.Filters)
 	}
 }
+
+func TestMaxFiltersAndSortKeys(t *testing.T) {
+	v := Validations{
+		"id:int": nil,
+		"name":   nil,
+		"sort":   In("id", "name"),
+	}
+
+	q := NewQV(nil, v).SetMaxFilters(2)
+	assert.NoError(t, q.SetUrlString("?id=1&name=tim"))
+	assert.NoError(t, q.Parse())
+
+	assert.NoError(t, q.SetUrlString("?id=1&name=tim|name=bob"))
+	assert.Equal(t, ErrTooManyFilters, errors.Cause(q.Parse()))
+
+	q = NewQV(nil, v).SetMaxSortKeys(1)
+	assert.NoError(t, q.SetUrlString("?sort=-id"))
+	assert.NoError(t, q.Parse())
+
+	assert.NoError(t, q.SetUrlString("?sort=-id,name"))
+	assert.EqualError(t, q.Parse(), "sort: too many sort keys")
+
+	// zero means unlimited
+	q = NewQV(nil, v)
+	assert.NoError(t, q.SetUrlString("?sort=-id,name&id=1&name=tim|name=bob"))
+	assert.NoError(t, q.Parse())
+}
+
+func TestMethodValidation(t *testing.T) {
+	q := New().
+		AddValidation("age:int", In(18, 21)).
+		SetMethodValidation("age", GTE, Min(1)).
+		AddValidation("name:lt", nil)
+
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?age=18"},
+		{url: "?age=20", err: "age: 20: not in scope"},
+		{url: "?age[gte]=20"},
+		{url: "?age[gte]=0", err: "age[gte]: 0: not in scope"},
+		{url: "?age[lte]=20", err: "age[lte]: 20: not in scope"},
+		{url: "?name[lt]=tim"},
+		{url: "?name=tim", err: "name: filter not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if len(c.err) > 0 {
+				assert.EqualError(t, err, c.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTrimValues(t *testing.T) {
+	v := Validations{
+		"id:int": nil,
+		"name":   nil,
+	}
+
+	q := NewQV(nil, v)
+	assert.NoError(t, q.SetUrlString("?id[in]=1,%202"))
+	assert.EqualError(t, q.Parse(), "id[in]: bad format")
+
+	q.SetTrimValues(true)
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{1, 2}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?name[in]=%20joe%20,%20bob&id=%201%20"))
+	assert.NoError(t, q.Parse())
+	f, err := q.GetFilter("name")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"joe", "bob"}, f.Value)
+	f, err = q.GetFilter("id")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, f.Value)
+}
+
+func TestCommaInValue(t *testing.T) {
+	v := Validations{"name": nil}
+
+	// values of methods which aren't lists are kept whole
+	cases := []struct {
+		url   string
+		where string
+		args  []interface{}
+	}{
+		{url: "?name=a,b", where: "name = ?", args: []interface{}{"a,b"}},
+		{url: "?name[like]=*a,b*", where: "name LIKE ?", args: []interface{}{"%a,b%"}},
+		{url: "?name[in]=a,b", where: "name IN (?, ?)", args: []interface{}{"a", "b"}},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, v)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	q := NewQV(url.Values{"$filter": {"name eq 'a,b'"}}, v).SetSyntax(SyntaxOData)
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "name = ?", q.Where())
+	assert.Equal(t, []interface{}{"a,b"}, q.Args())
+}
+
+func TestSearch(t *testing.T) {
+	q := New().
+		SetValidations(Validations{"id:int": nil}).
+		SetSearchColumns("firstname", "lastname", "email")
+
+	assert.NoError(t, q.SetUrlString("?q=joe&id=1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id = ? AND (firstname LIKE ? OR lastname LIKE ? OR email LIKE ?)", q.Where())
+	assert.Equal(t, []interface{}{1, "%joe%", "%joe%", "%joe%"}, q.Args())
+
+	// empty search
+	assert.NoError(t, q.SetUrlString("?q="))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "", q.Where())
+
+	// single column and custom key
+	q.SetSearchColumns("name").SetSearchKey("search")
+	assert.NoError(t, q.SetUrlString("?search=joe"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "name LIKE ?", q.Where())
+	assert.Equal(t, []interface{}{"%joe%"}, q.Args())
+
+	// without columns the key is an ordinary filter
+	q = New()
+	assert.NoError(t, q.SetUrlString("?q=joe"))
+	assert.Equal(t, ErrFilterNotFound, errors.Cause(q.Parse()))
+}
+
+func TestSetTopLevelJoin(t *testing.T) {
+	q := New().
+		AddFilter("a", EQ, 1).
+		AddORFilters(func(query *Query) {
+			query.AddFilter("b", EQ, 2)
+			query.AddFilter("c", EQ, 3)
+		}).
+		AddFilter("d", EQ, 4)
+	assert.Equal(t, "a = ? AND (b = ? OR c = ?) AND d = ?", q.Where())
+
+	assert.NoError(t, q.SetTopLevelJoin("or"))
+	assert.Equal(t, "a = ? OR (b = ? OR c = ?) OR d = ?", q.Where())
+	assert.Equal(t, q.Where(), q.Clone().Where())
+
+	assert.NoError(t, q.SetTopLevelJoin("AND"))
+	assert.Equal(t, "a = ? AND (b = ? OR c = ?) AND d = ?", q.Where())
+
+	err := q.SetTopLevelJoin("XOR")
+	assert.Equal(t, ErrBadFormat, errors.Cause(err))
+	assert.EqualError(t, err, "XOR: bad format")
+	assert.Equal(t, "a = ? AND (b = ? OR c = ?) AND d = ?", q.Where())
+}
+
+func TestMaxValueLength(t *testing.T) {
+	q := NewQV(nil, Validations{"name": nil, "id:int": nil}).
+		SetMaxValueLength(3).
+		SetSearchColumns("name")
+
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?name=tim"},
+		{url: "?name[like]=*tim*", err: "name[like]: value too long"},
+		{url: "?name[in]=tim,bob"},
+		{url: "?name[in]=tim,bobby", err: "name[in]: value too long"},
+		{url: "?id=1000", err: "id: value too long"},
+		{url: "?q=tim"},
+		{url: "?q=timothy", err: "q: value too long"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if len(c.err) > 0 {
+				assert.EqualError(t, err, c.err)
+				assert.Equal(t, ErrValueTooLong, errors.Cause(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaxFields(t *testing.T) {
+	q := NewQV(nil, Validations{"fields": In("id", "name", "email")}).SetMaxFields(2)
+
+	assert.NoError(t, q.SetUrlString("?fields=id,name"))
+	assert.NoError(t, q.Parse())
+
+	assert.NoError(t, q.SetUrlString("?fields=id,name,email"))
+	err := q.Parse()
+	assert.Equal(t, ErrTooManyFields, errors.Cause(err))
+	assert.EqualError(t, err, "fields: too many fields")
+}
+
+func TestMaxINSize(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "tags": nil}).SetMaxINSize(3)
+
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?id[in]=1,2,3"},
+		{url: "?id[in]=1,2,3,4", err: "id[in]: too many values"},
+		{url: "?id[nin]=1,2,3,4", err: "id[nin]: too many values"},
+		{url: "?tags[contains]=a,b,c,d", err: "tags[contains]: too many values"},
+		{url: "?tags[contains]=a,b"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Clone().Parse()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				assert.Equal(t, ErrTooManyValues, errors.Cause(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRegisterColumnComparison(t *testing.T) {
+	q := New().AddValidation("id:int", nil)
+	assert.NoError(t, q.RegisterColumnComparison("valid", "start_date", LTE, "end_date"))
+	assert.Equal(t, ErrMethodNotAllowed, errors.Cause(q.RegisterColumnComparison("bad", "a", LIKE, "b")))
+	assert.Equal(t, ErrInvalidIdentifier, errors.Cause(q.RegisterColumnComparison("bad", "a; DROP TABLE a", EQ, "b")))
+	assert.Equal(t, ErrInvalidIdentifier, errors.Cause(q.RegisterColumnComparison("bad", "a", EQ, "b'")))
+
+	assert.NoError(t, q.SetUrlString("?valid=true&id=1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id = ? AND start_date <= end_date", q.Where())
+	assert.Equal(t, []interface{}{1}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?valid=false"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "", q.Where())
+
+	assert.NoError(t, q.SetUrlString("?valid=yes"))
+	assert.EqualError(t, q.Parse(), "valid: bad format")
+	QueryEqual(t, q, q.Clone())
+}
+
+func TestDeterministicWhere(t *testing.T) {
+	URL, err := url.Parse("?id[gte]=1&id[lte]=10&name[in]=tim,bob&s=super&u[like]=*tim*|id[eq]=5")
+	assert.NoError(t, err)
+
+	var where string
+	var args []interface{}
+	for i := 0; i < 50; i++ {
+		q, err := NewParse(URL.Query(), Validations{
+			"id:int": nil,
+			"name":   nil,
+			"s":      nil,
+			"u":      nil,
+		})
+		assert.NoError(t, err)
+		if i == 0 {
+			where, args = q.Where(), q.Args()
+			continue
+		}
+		assert.Equal(t, where, q.Where())
+		assert.Equal(t, args, q.Args())
+	}
+	assert.Equal(t, "id >= ? AND id <= ? AND name IN (?, ?) AND s = ? AND (u LIKE ? OR id = ?)", where)
+	assert.Equal(t, []interface{}{1, 10, "tim", "bob", "super", "%tim%", 5}, args)
+}
+
+func TestSetMethodSQL(t *testing.T) {
+	q1 := New().AddFilter("name", LIKE, "*tim*").AddFilter("id", IN, []int{1, 2})
+	q2 := q1.Clone().SetMethodSQL(LIKE, "ILIKE").SetMethodSQL(IN, "= ANY")
+
+	assert.Equal(t, "name LIKE ? AND id IN (?, ?)", q1.Where())
+	assert.Equal(t, "name ILIKE ? AND id = ANY (?, ?)", q2.Where())
+	assert.Equal(t, q1.Args(), q2.Args())
+	assert.Equal(t, q2.Where(), q2.Clone().Where())
+
+	f, err := q2.GetFilter("name")
+	assert.NoError(t, err)
+	exp, err := f.Where()
+	assert.NoError(t, err)
+	assert.Equal(t, "name LIKE ?", exp)
+
+	// new method is available only in instance which adds it
+	similar := Method("SIMILAR")
+	q3 := NewQV(nil, Validations{"name": nil, "id:int": nil}).SetMethodSQL(similar, "SIMILAR TO")
+	assert.NoError(t, q3.SetUrlString("?name[similar]=%25b_d%25&id[similar]=1"))
+	assert.NoError(t, q3.Parse())
+	assert.Equal(t, "id SIMILAR TO ? AND name SIMILAR TO ?", q3.Where())
+	assert.Equal(t, []interface{}{1, "%b_d%"}, q3.Args())
+	assert.Equal(t, q3.Where(), q3.Clone().Where())
+
+	q4 := NewQV(nil, Validations{"name": nil})
+	assert.NoError(t, q4.SetUrlString("?name[similar]=a"))
+	assert.EqualError(t, q4.Parse(), "name[similar]: unknown method")
+}
+
+func TestStartsEndsContains(t *testing.T) {
+	cases := []struct {
+		url   string
+		where string
+		arg   string
+	}{
+		{url: "?name[starts]=joe", where: "name LIKE ?", arg: "joe%"},
+		{url: "?name[ends]=son", where: "name LIKE ?", arg: "%son"},
+		{url: "?name[contains_str]=oh", where: "name LIKE ?", arg: "%oh%"},
+		{url: "?name[starts]=*joe", where: "name LIKE ?", arg: "*joe%"},
+		{url: "?name[sw]=jo", where: "name LIKE ?", arg: "jo%"},
+		{url: "?name[ew]=son", where: "name LIKE ?", arg: "%son"},
+		{url: "?name[sw]=10%25_", where: "name LIKE ?", arg: `10\%\_%`},
+		{url: "?name[contains_str]=a\\b", where: "name LIKE ?", arg: `%a\\b%`},
+		{url: "?name[like]=*oh*", where: "name LIKE ?", arg: "%oh%"},
+		{url: "?name[like]=100%25*", where: "name LIKE ?", arg: `100\%%`},
+		{url: "?name[ilike]=*a_b", where: "name ILIKE ?", arg: `%a\_b`},
+		{url: "?name[nlike]=a*b", where: "name NOT LIKE ?", arg: "a*b"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := New().AddValidation("name", nil)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, []interface{}{c.arg}, q.Args())
+		})
+	}
+
+	q := New().AddValidation("name", nil).SetDialect(DialectSQLite)
+	assert.NoError(t, q.SetUrlString("?name[sw]=jo"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, `name LIKE ? ESCAPE '\'`, q.Where())
+
+	q = New().AddValidation("id:int", nil)
+	assert.NoError(t, q.SetUrlString("?id[starts]=1"))
+	assert.EqualError(t, q.Parse(), "id[starts]: method are not allowed")
+}
+
+func TestRegex(t *testing.T) {
+	q := New().AddValidation("name", nil)
+	assert.NoError(t, q.SetUrlString("?name[regex]=^jo"))
+	assert.EqualError(t, q.Parse(), "name[regex]: method are not allowed")
+
+	q = New().AddValidation("name", nil).AddValidation("name:regex", nil)
+	assert.NoError(t, q.SetUrlString("?name[regex]=^jo"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "name ~ ?", q.Where())
+	assert.Equal(t, []interface{}{"^jo"}, q.Args())
+
+	q.SetDialect(DialectMySQL)
+	assert.Equal(t, "name REGEXP ?", q.Where())
+
+	q = New().AddValidation("id:int:regex", nil)
+	assert.NoError(t, q.SetUrlString("?id[regex]=1"))
+	assert.EqualError(t, q.Parse(), "id[regex]: method are not allowed")
+}
+
+func TestContains(t *testing.T) {
+	q := New().AddValidation("tags", nil).AddValidation("ids:int", nil)
+	assert.NoError(t, q.SetUrlString("?tags[contains]=go,sql&ids[@>]=1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "ids @> ?::int[] AND tags @> ?::text[]", q.Where())
+	assert.Equal(t, []interface{}{[]int{1}, []string{"go", "sql"}}, q.Args())
+
+	q = New().AddValidation("active:bool", nil)
+	assert.NoError(t, q.SetUrlString("?active[contains]=true"))
+	assert.EqualError(t, q.Parse(), "active[contains]: method are not allowed")
+}
+
+func TestBoolValue(t *testing.T) {
+	q := New().AddValidation("active:bool", nil)
+	assert.NoError(t, q.SetUrlString("?active=true"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{true}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?active[ne]=0"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "active != ?", q.Where())
+	assert.Equal(t, []interface{}{false}, q.Args())
+}
+
+func TestEmptyValueBehavior(t *testing.T) {
+	cases := []struct {
+		behavior EmptyValueBehavior
+		url      string
+		where    string
+		args     []interface{}
+		err      string
+	}{
+		{behavior: EmptyValueDefault, url: "?name=", err: "name: empty value"},
+		{behavior: EmptyValueDefault, url: "?name[in]=a,,b", where: "name IN (?, ?, ?)", args: []interface{}{"a", "", "b"}},
+		{behavior: EmptyValueKeep, url: "?name=", where: "name = ?", args: []interface{}{""}},
+		{behavior: EmptyValueKeep, url: "?id=", err: "id: bad format"},
+		{behavior: EmptyValueKeep, url: "?name[in]=a,,b", where: "name IN (?, ?, ?)", args: []interface{}{"a", "", "b"}},
+		{behavior: EmptyValueSkip, url: "?name=&id=1", where: "id = ?", args: []interface{}{1}},
+		{behavior: EmptyValueSkip, url: "?name[in]=a,,b", where: "name IN (?, ?)", args: []interface{}{"a", "b"}},
+		{behavior: EmptyValueSkip, url: "?name[in]=,", where: "", args: []interface{}{}},
+		{behavior: EmptyValueSkip, url: "?id=1|name=|u=a", where: "(id = ? OR u = ?)", args: []interface{}{1, "a"}},
+		{behavior: EmptyValueSkip, url: "?id=1|name=", where: "id = ?", args: []interface{}{1}},
+		{behavior: EmptyValueError, url: "?name=", err: "name: empty value"},
+		{behavior: EmptyValueError, url: "?name[in]=a,,b", err: "name[in]: empty value"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, Validations{"name": nil, "id:int": nil, "u": nil}).SetEmptyValueBehavior(c.behavior)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if len(c.err) > 0 {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+}
+
+func TestDefaultSort(t *testing.T) {
+	q := NewQV(nil, Validations{"sort": In("name")}).SetDefaultSort("-created_at", "+id", "name")
+
+	assert.NoError(t, q.SetUrlString("?"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " ORDER BY created_at DESC, id, name", q.ORDER())
+
+	// client's sort wins
+	assert.NoError(t, q.SetUrlString("?sort=-name"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " ORDER BY name DESC", q.ORDER())
+	QueryEqual(t, q, q.Clone())
+
+	// changing of parsed sorts doesn't change the default
+	assert.NoError(t, q.SetUrlString("?"))
+	assert.NoError(t, q.Parse())
+	q.Sorts[0].Desc = false
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " ORDER BY created_at DESC, id, name", q.ORDER())
+
+	// cursor uses default sort
+	c, _ := EncodeCursor("2020-01-01", 1, "a")
+	q.SetCursorPagination(true)
+	assert.NoError(t, q.SetUrlString("?after="+c))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "(created_at < ? OR (created_at = ? AND id > ?) OR (created_at = ? AND id = ? AND name > ?))", q.Where())
+}
+
+func TestSortAliases(t *testing.T) {
+	q := New().SetSortAliases(map[string]string{"popularity": "likes + comments"})
+
+	// alias doesn't require validation
+	assert.NoError(t, q.SetUrlString("?sort=-popularity"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " ORDER BY likes + comments DESC", q.ORDER())
+	assert.True(t, q.HaveSortBy("popularity"))
+	assert.Equal(t, "sort=-popularity", q.ToQueryString())
+
+	// other keys are validated
+	assert.NoError(t, q.SetUrlString("?sort=id,-popularity"))
+	assert.Equal(t, ErrValidationNotFound, errors.Cause(q.Parse()))
+
+	q.AddValidation("sort", In("id"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " ORDER BY id, likes + comments DESC", q.ORDER())
+
+	assert.NoError(t, q.SetUrlString("?sort=name"))
+	assert.EqualError(t, q.Parse(), "sort: name: not in scope")
+	QueryEqual(t, q, q.Clone())
+}
+
+func TestExcludeFields(t *testing.T) {
+	q := New().SetValidations(Validations{"fields": In("id", "name", "email", "password")})
+
+	// available fields are required
+	assert.NoError(t, q.SetUrlString("?fields=-password"))
+	assert.EqualError(t, q.Parse(), "fields: exclusion of fields requires available fields: bad format")
+
+	q.SetAvailableFields("id", "name", "email", "password")
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []string{"id", "name", "email"}, q.Fields)
+	assert.Equal(t, "SELECT id, name, email", q.SELECT())
+
+	assert.NoError(t, q.SetUrlString("?fields=-password,-email"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []string{"id", "name"}, q.Fields)
+
+	assert.NoError(t, q.SetUrlString("?fields=id,-password"))
+	assert.EqualError(t, q.Parse(), "fields: inclusion and exclusion of fields can't be mixed: bad format")
+
+	assert.NoError(t, q.SetUrlString("?fields=-secret"))
+	assert.EqualError(t, q.Parse(), "fields: secret: not in scope")
+
+	assert.NoError(t, q.SetUrlString("?fields=-id,-name,-email,-password"))
+	assert.EqualError(t, q.Parse(), "fields: all fields are excluded: bad format")
+
+	assert.NoError(t, q.SetUrlString("?fields=id"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []string{"id"}, q.Fields)
+	QueryEqual(t, q, q.Clone())
+
+	// available fields are allowed without validation of fields
+	q = New().SetAvailableFields("id", "name", "password")
+	assert.NoError(t, q.SetUrlString("?fields=-password"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []string{"id", "name"}, q.Fields)
+
+	assert.NoError(t, q.SetUrlString("?fields=id,name"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []string{"id", "name"}, q.Fields)
+
+	assert.NoError(t, q.SetUrlString("?fields=email"))
+	assert.EqualError(t, q.Parse(), "fields: email: not in scope")
+
+	assert.NoError(t, q.SetUrlString("?fields=-secret"))
+	assert.EqualError(t, q.Parse(), "fields: secret: not in scope")
+}
+
+func TestSummary(t *testing.T) {
+	q := New().SetValidations(Validations{
+		"fields": In("id", "name"),
+		"sort":   In("id", "name"),
+		"id:int": nil,
+		"name":   nil,
+	})
+	assert.Equal(t, QuerySummary{}, q.Summary())
+
+	assert.NoError(t, q.SetUrlString("?fields=id,name&sort=-id,name&limit=10&id[in]=1,2&name=tim|name=bob"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, QuerySummary{
+		Filters:   3,
+		Sorts:     []string{"-id", "name"},
+		Fields:    []string{"id", "name"},
+		Limit:     10,
+		Paginated: true,
+	}, q.Summary())
+}
+
+func TestPostValidation(t *testing.T) {
+	errPrice := errors.New("max_price must be greater then min_price")
+
+	q := NewQV(nil, Validations{"min_price:int": nil, "max_price:int": nil}).
+		SetPostValidation(func(q *Query) error {
+			min, err1 := q.GetFilter("min_price")
+			max, err2 := q.GetFilter("max_price")
+			if err1 == nil && err2 == nil && min.Value.(int) > max.Value.(int) {
+				return errPrice
+			}
+			return nil
+		})
+
+	assert.NoError(t, q.SetUrlString("?min_price=10&max_price=20"))
+	assert.NoError(t, q.Parse())
+
+	assert.NoError(t, q.SetUrlString("?min_price=30"))
+	assert.NoError(t, q.Parse())
+
+	assert.NoError(t, q.SetUrlString("?min_price=30&max_price=20"))
+	assert.Equal(t, errPrice, q.Parse())
+	assert.Equal(t, errPrice, q.Clone().Parse())
+}
+
+func TestRepeatedReservedParameters(t *testing.T) {
+	v := Validations{
+		"fields": In("id", "name"),
+		"sort":   In("id", "name"),
+	}
+
+	cases := []struct {
+		url string
+		err string
+	}{
+		{url: "?limit=10&limit=20", err: "limit: expected 1 value, got 2: bad format"},
+		{url: "?offset=10&offset=20&offset=30", err: "offset: expected 1 value, got 3: bad format"},
+		{url: "?sort=id&sort=name", err: "sort: expected 1 value, got 2: bad format"},
+		{url: "?fields=id&fields=name", err: "fields: expected 1 value, got 2: bad format"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, v)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			assert.EqualError(t, err, c.err)
+			assert.Equal(t, ErrBadFormat, errors.Cause(err))
+		})
+	}
+
+	q := NewQV(nil, v).UseLastValue(true)
+	assert.NoError(t, q.SetUrlString("?limit=10&limit=20&offset=1&offset=2&sort=id&sort=-name&fields=id&fields=name"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, 20, q.Limit)
+	assert.Equal(t, 2, q.Offset)
+	assert.Equal(t, []Sort{{By: "name", Desc: true}}, q.Sorts)
+	assert.Equal(t, []string{"name"}, q.Fields)
+}
+
+func TestDisableFilter(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "name": nil, "u": nil})
+	assert.NoError(t, q.SetUrlString("?id=1&name=tim|u=bob"))
+	assert.NoError(t, q.Parse())
+
+	assert.NoError(t, q.DisableFilter("id"))
+	assert.Equal(t, "(name = ? OR u = ?)", q.Where())
+	assert.Equal(t, []interface{}{"tim", "bob"}, q.Args())
+
+	// filter is still parsed
+	f, err := q.GetFilter("id")
+	assert.NoError(t, err)
+	assert.True(t, f.Disabled)
+	assert.Equal(t, 1, f.Value)
+	assert.Equal(t, 2, q.Summary().Filters)
+	assert.Equal(t, "id=1&name=tim%7Cu%3Dbob", q.ToQueryString())
+
+	// disabled filter in OR statement
+	assert.NoError(t, q.DisableFilter("name"))
+	assert.Equal(t, "u = ?", q.Where())
+	assert.Equal(t, []interface{}{"bob"}, q.Args())
+
+	assert.NoError(t, q.DisableFilter("u"))
+	assert.Equal(t, "", q.Where())
+	assert.Equal(t, "", q.WHERE())
+	assert.Equal(t, []interface{}{}, q.Args())
+
+	assert.Equal(t, ErrFilterNotFound, q.DisableFilter("email"))
+}
+
+func TestORSameKey(t *testing.T) {
+	q := NewQV(nil, Validations{"status": nil, "id:int": nil})
+
+	assert.NoError(t, q.SetUrlString("?status[eq]=active|pending"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "(status = ? OR status = ?)", q.Where())
+	assert.Equal(t, []interface{}{"active", "pending"}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?id=1&status[like]=act*|*ing|id[gt]=10|5"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id = ? AND (status LIKE ? OR status LIKE ? OR id > ? OR id > ?)", q.Where())
+	assert.Equal(t, []interface{}{1, "act%", "%ing", 10, 5}, q.Args())
+
+	// value could contain "="
+	assert.NoError(t, q.SetUrlString("?status=a|status=b=c"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{"a", "b=c"}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?id=1|a"))
+	assert.Equal(t, ErrBadFormat, errors.Cause(q.Parse()))
+}
+
+func TestFiltersAfterParse(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "user_id:int": nil, "name": nil})
+	assert.NoError(t, q.SetUrlString("?id[in]=1,2&user_id=5&name=tim"))
+	assert.NoError(t, q.Parse())
+
+	// force filter by authenticated user
+	assert.True(t, q.HaveFilter("user_id"))
+	assert.NoError(t, q.RemoveFilter("user_id"))
+	assert.False(t, q.HaveFilter("user_id"))
+	q.AddFilter("user_id", EQ, 10)
+
+	f, err := q.GetFilter("user_id")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, f.Value)
+
+	assert.Equal(t, "id IN (?, ?) AND name = ? AND user_id = ?", q.Where())
+	assert.Equal(t, []interface{}{1, 2, "tim", 10}, q.Args())
+
+	_, err = q.GetFilter("email")
+	assert.Equal(t, ErrFilterNotFound, err)
+	assert.Equal(t, ErrFilterNotFound, q.RemoveFilter("email"))
+}
+
+// benchQuery returns query with 20+ filters, sorts and fields which aren't built by Parse
+func benchQuery() *Query {
+	q := New().SetNameMapping(Replacer{"author": "users.name"})
+	for i := 0; i < 20; i++ {
+		q.AddFilter(fmt.Sprintf("f%d", i), EQ, i)
+	}
+	q.AddFilter("id", IN, []int{1, 2, 3, 4, 5})
+	q.AddFilter("author", LIKE, "tim*")
+	q.AddORFilters(func(q *Query) {
+		q.AddFilter("status", EQ, "active")
+		q.AddFilter("status", EQ, "pending")
+	})
+	q.Fields = []string{"id", "author", "email", "created_at", "status"}
+	q.Sorts = []Sort{{By: "created_at", Desc: true}, {By: "author"}, {By: "id"}}
+	return q.SetLimit(10).SetOffset(20)
+}
+
+func BenchmarkSQL(b *testing.B) {
+	q := benchQuery()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.SQL("users")
+		q.Args()
+	}
+}
+
+func BenchmarkSQLDollar(b *testing.B) {
+	q := benchQuery().SetPlaceholder(PlaceholderDollar)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.SQL("users")
+		q.Args()
+	}
+}
@@ -0,0 +1,519 @@
+package rqp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeType is type of time.Time values which are compared by time
+var timeType = reflect.TypeOf(time.Time{})
+
+// getter returns value of a field by name of filter or sort
+type getter func(name string) (interface{}, bool)
+
+// regexps are compiled patterns of REGEX filters by their values, they are compiled once
+// for all records of one Match, MatchFunc or MatchSlice call. Invalid patterns are nil.
+type regexps map[string]*regexp.Regexp
+
+// Match reports whether struct v (or pointer to struct) satisfies filters of the Query.
+// Fields are found by name of filter in `db` or `json` tags and then by case-insensitive name of field.
+// Filters for unknown fields don't match. Raw filters (see AddFilterRaw) can't be evaluated in Go
+// so they are ignored as well as disabled filters and cursor of keyset pagination.
+//
+// LIKE and NLIKE are case-sensitive, ILIKE and NILIKE are not.
+// Nil pointers are NULL: they match only IS NULL filters.
+func (q *Query) Match(v interface{}) bool {
+	return q.match(structGetter(reflect.ValueOf(v)), q.regexps())
+}
+
+// MatchFunc returns function which reports whether record satisfies filters of the Query like Match,
+// values are found by names of filters as keys of record, eg. to filter decoded JSON documents:
+//
+//	match := q.MatchFunc()
+//	for _, doc := range docs {
+//		if match(doc) { ... }
+//	}
+//
+// Missing keys are unknown fields, nil values and nil pointers are NULL,
+// json.Number values are compared as numbers.
+func (q *Query) MatchFunc() func(record map[string]interface{}) bool {
+	re := q.regexps()
+	return func(record map[string]interface{}) bool {
+		return q.match(mapGetter(record), re)
+	}
+}
+
+// MatchSlice removes elements which don't match filters from slice pointed by ptr,
+// then sorts the rest by Sorts and applies Offset and Limit.
+// Sorting by aliases (see SetSortAliases) isn't possible in Go so they are skipped.
+//
+//	users := []User{...}
+//	err := q.MatchSlice(&users)
+func (q *Query) MatchSlice(ptr interface{}) error {
+	p := reflect.ValueOf(ptr)
+	if p.Kind() != reflect.Ptr || p.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected pointer to slice, got %T", ptr)
+	}
+	slice := p.Elem()
+
+	re := q.regexps()
+	n := 0
+	for i := 0; i < slice.Len(); i++ {
+		if q.match(structGetter(slice.Index(i)), re) {
+			slice.Index(n).Set(slice.Index(i))
+			n++
+		}
+	}
+	result := slice.Slice(0, n)
+
+	less := q.less()
+	sort.SliceStable(result.Interface(), func(i, j int) bool {
+		return less(structGetter(result.Index(i)), structGetter(result.Index(j)))
+	})
+
+	if q.Offset > 0 {
+		if q.Offset > result.Len() {
+			result = result.Slice(0, 0)
+		} else {
+			result = result.Slice(q.Offset, result.Len())
+		}
+	}
+	if q.Limit > 0 && q.Limit < result.Len() {
+		result = result.Slice(0, q.Limit)
+	}
+
+	slice.Set(result)
+	return nil
+}
+
+// regexps compiles patterns of REGEX filters of the Query
+func (q *Query) regexps() regexps {
+	var re regexps
+	for _, filters := range [][]*Filter{q.Filters, q.forcedFilters()} {
+		for _, f := range filters {
+			if f.Method != REGEX {
+				continue
+			}
+			pattern, _ := f.Value.(string)
+			if _, ok := re[pattern]; ok {
+				continue
+			}
+			if re == nil {
+				re = make(regexps)
+			}
+			re[pattern], _ = regexp.Compile(pattern)
+		}
+	}
+	return re
+}
+
+// match evaluates filters with OR statements and top level join of the Query
+func (q *Query) match(get getter, re regexps) bool {
+	or := q.topLevelJoin() == "OR"
+	matched := !or
+	evaluated := false
+
+	for _, group := range q.groups() {
+		ok, known := q.matchGroup(group, get, re)
+		if !known {
+			continue
+		}
+		evaluated = true
+		if or {
+			matched = matched || ok
+		} else {
+			matched = matched && ok
+		}
+	}
+
+	if !matched && evaluated {
+		return false
+	}
+
+	for _, f := range q.forcedFilters() {
+		if ok, known := q.matchGroup([]*Filter{f}, get, re); known && !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchGroup evaluates filters of one OR statement.
+// known is false when all filters of the group are raw, cursor or disabled.
+func (q *Query) matchGroup(group []*Filter, get getter, re regexps) (ok, known bool) {
+	for _, f := range group {
+		if f.Method == raw || f.Method == cursor || f.Disabled {
+			continue
+		}
+		known = true
+		if q.matchFilter(f, get, re) {
+			ok = true
+		}
+	}
+	return
+}
+
+// matchFilter evaluates single filter against value of field
+func (q *Query) matchFilter(f *Filter, get getter, re regexps) bool {
+	value, ok := get(f.Name)
+	if !ok {
+		return false
+	}
+
+	if f.Method == IS || f.Method == NOT {
+		if f.Value != NULL {
+			return false
+		}
+		return (value == nil) == (f.Method == IS)
+	}
+
+	// comparison with NULL is never true
+	if value == nil {
+		return false
+	}
+
+	switch f.Method {
+	case EQ, NE, GT, LT, GTE, LTE:
+		c, ok := compareValues(value, f.Value)
+		if !ok {
+			return false
+		}
+		switch f.Method {
+		case EQ:
+			return c == 0
+		case NE:
+			return c != 0
+		case GT:
+			return c > 0
+		case LT:
+			return c < 0
+		case GTE:
+			return c >= 0
+		default:
+			return c <= 0
+		}
+	case LIKE, ILIKE, NLIKE, NILIKE, STARTS, ENDS, CONTAINS_STR:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		args, err := f.args(q)
+		if err != nil || len(args) != 1 {
+			return false
+		}
+		pattern, _ := args[0].(string)
+		fold := f.Method == ILIKE || f.Method == NILIKE
+		if f.Method == NLIKE || f.Method == NILIKE {
+			return !likeMatch(s, pattern, fold)
+		}
+		return likeMatch(s, pattern, fold)
+	case FTS:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		// all words of the value must be in the field like plainto_tsquery does
+		text, _ := f.Value.(string)
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			if !strings.Contains(strings.ToLower(s), word) {
+				return false
+			}
+		}
+		return true
+	case REGEX:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		pattern, _ := f.Value.(string)
+		r, ok := re[pattern]
+		if !ok {
+			// filter is changed after compilation
+			r, _ = regexp.Compile(pattern)
+		}
+		return r != nil && r.MatchString(s)
+	case IN, NIN:
+		found := false
+		list := reflect.ValueOf(toSlice(f.Value))
+		for i := 0; i < list.Len(); i++ {
+			if c, ok := compareValues(value, list.Index(i).Interface()); ok && c == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (f.Method == IN)
+	case CONTAINS:
+		field := reflect.ValueOf(value)
+		if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+			return false
+		}
+		list := reflect.ValueOf(toSlice(f.Value))
+		for i := 0; i < list.Len(); i++ {
+			found := false
+			for j := 0; j < field.Len(); j++ {
+				if c, ok := compareValues(field.Index(j).Interface(), list.Index(i).Interface()); ok && c == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case BETWEEN:
+		list := reflect.ValueOf(toSlice(f.Value))
+		if list.Len() != 2 {
+			return false
+		}
+		return q.matchFilter(&Filter{Name: f.Name, Method: GTE, Value: list.Index(0).Interface()}, get, re) &&
+			q.matchFilter(&Filter{Name: f.Name, Method: LTE, Value: list.Index(1).Interface()}, get, re)
+	case RANGE:
+		r, ok := f.Value.(Range)
+		if !ok {
+			return false
+		}
+		from, to := r.methods()
+		return q.matchFilter(&Filter{Name: f.Name, Method: from, Value: r.From}, get, re) &&
+			q.matchFilter(&Filter{Name: f.Name, Method: to, Value: r.To}, get, re)
+	default:
+		return false
+	}
+}
+
+// less returns function which compares elements by Sorts of the Query
+func (q *Query) less() func(a, b getter) bool {
+	return func(a, b getter) bool {
+		for _, s := range q.Sorts {
+			if _, ok := q.sortAliases[s.By]; ok {
+				continue
+			}
+			va, _ := a(s.By)
+			vb, _ := b(s.By)
+			c := compareNULL(va, vb)
+			if c == 0 {
+				continue
+			}
+			if s.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	}
+}
+
+// compareNULL compares values like compareValues but nil values are the lowest
+func compareNULL(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	c, _ := compareValues(a, b)
+	return c
+}
+
+// compareValues compares two values of numeric, string or bool kinds.
+// It returns -1, 0 or 1 and false if values can't be compared.
+func compareValues(a, b interface{}) (int, bool) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	// decimal filters keep values as strings
+	if isNumber(va) && vb.Kind() == reflect.String {
+		if f, err := strconv.ParseFloat(vb.String(), 64); err == nil {
+			vb = reflect.ValueOf(f)
+		}
+	}
+
+	switch {
+	case isNumber(va) && isNumber(vb):
+		x, y := toFloat(va), toFloat(vb)
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case va.IsValid() && vb.IsValid() && va.Type() == timeType && vb.Type() == timeType:
+		x, y := a.(time.Time), b.(time.Time)
+		switch {
+		case x.Before(y):
+			return -1, true
+		case x.After(y):
+			return 1, true
+		default:
+			return 0, true
+		}
+	case va.Kind() == reflect.String && vb.Kind() == reflect.String:
+		return strings.Compare(va.String(), vb.String()), true
+	case va.Kind() == reflect.Bool && vb.Kind() == reflect.Bool:
+		x, y := va.Bool(), vb.Bool()
+		switch {
+		case x == y:
+			return 0, true
+		case !x:
+			return -1, true
+		default:
+			return 1, true
+		}
+	default:
+		return 0, false
+	}
+}
+
+// isNumber returns true for int, uint and float kinds
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// toFloat returns numeric value as float64
+func toFloat(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// likeMatch reports whether s matches SQL LIKE pattern with `%` and `_` wildcards,
+// `\` escapes the next character of pattern
+func likeMatch(s, pattern string, fold bool) bool {
+	if fold {
+		s, pattern = strings.ToLower(s), strings.ToLower(pattern)
+	}
+	str, pat := []rune(s), []rune(pattern)
+
+	// star is the position of the last `%` in pattern and mark is the position
+	// in str where we should continue if matching after star fails
+	si, pi, star, mark := 0, 0, -1, 0
+	for si < len(str) {
+		switch {
+		case pi+1 < len(pat) && pat[pi] == '\\':
+			if pat[pi+1] == str[si] {
+				si++
+				pi += 2
+			} else if star >= 0 {
+				mark++
+				si, pi = mark, star+1
+			} else {
+				return false
+			}
+		case pi < len(pat) && (pat[pi] == '_' || pat[pi] == str[si]):
+			si++
+			pi++
+		case pi < len(pat) && pat[pi] == '%':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			mark++
+			si, pi = mark, star+1
+		default:
+			return false
+		}
+	}
+	for pi < len(pat) && pat[pi] == '%' {
+		pi++
+	}
+	return pi == len(pat)
+}
+
+// structGetter returns getter for fields of struct.
+// nil pointers and interfaces are returned as nil values.
+func structGetter(v reflect.Value) getter {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return func(string) (interface{}, bool) { return nil, false }
+		}
+		v = v.Elem()
+	}
+
+	return func(name string) (interface{}, bool) {
+		if v.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, ok := structField(v, name)
+		if !ok {
+			return nil, false
+		}
+		for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+			if field.IsNil() {
+				return nil, true
+			}
+			field = field.Elem()
+		}
+		return field.Interface(), true
+	}
+}
+
+// mapGetter returns getter for values of map, pointers are dereferenced
+func mapGetter(m map[string]interface{}) getter {
+	return func(name string) (interface{}, bool) {
+		value, ok := m[name]
+		if !ok {
+			return nil, false
+		}
+		if n, ok := value.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				return f, true
+			}
+			return n.String(), true
+		}
+		v := reflect.ValueOf(value)
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, true
+			}
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			return nil, true
+		}
+		return v.Interface(), true
+	}
+}
+
+// structField finds exported field of struct by `db` or `json` tag or by case-insensitive name
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+
+	for _, tag := range []string{"db", "json"} {
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			if n := strings.Split(t.Field(i).Tag.Get(tag), ",")[0]; n != "" && n == name {
+				return v.Field(i), true
+			}
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" && strings.EqualFold(t.Field(i).Name, name) {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
@@ -0,0 +1,226 @@
+package rqp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type matchUser struct {
+	ID      int     `db:"id"`
+	Name    string  `json:"name"`
+	Email   *string `db:"email"`
+	Balance float64
+	Active  bool `db:"active"`
+	Tags    []string
+}
+
+func TestMatch(t *testing.T) {
+	email := "tim@example.com"
+	user := matchUser{ID: 5, Name: "Tim", Email: &email, Balance: 10.5, Active: true, Tags: []string{"go", "sql"}}
+
+	validations := Validations{
+		"id:int":      nil,
+		"name":        nil,
+		"name:regex":  nil,
+		"email":       nil,
+		"balance:int": nil,
+		"active:bool": nil,
+		"unknown":     nil,
+		"tags":        nil,
+	}
+
+	cases := []struct {
+		url      string
+		expected bool
+	}{
+		{url: "?", expected: true},
+		{url: "?id=5", expected: true},
+		{url: "?id[ne]=5", expected: false},
+		{url: "?id[gt]=4&id[lt]=6", expected: true},
+		{url: "?id[gte]=6", expected: false},
+		{url: "?id[lte]=5", expected: true},
+		{url: "?id[in]=1,5", expected: true},
+		{url: "?id[nin]=1,5", expected: false},
+		{url: "?name=Tim", expected: true},
+		{url: "?name[like]=T*", expected: true},
+		{url: "?name[like]=t*", expected: false},
+		{url: "?name[like]=T_m", expected: false},
+		{url: "?name[ilike]=t*", expected: true},
+		{url: "?name[nlike]=*im", expected: false},
+		{url: "?name[nilike]=*x*", expected: true},
+		{url: "?name[starts]=Ti", expected: true},
+		{url: "?name[ends]=Ti", expected: false},
+		{url: "?name[contains_str]=i", expected: true},
+		{url: "?name[sw]=T_", expected: false},
+		{url: "?email[fts]=tim+example", expected: true},
+		{url: "?email[fts]=tim+bob", expected: false},
+		{url: "?name[regex]=^T.m$", expected: true},
+		{url: "?name[regex]=^t", expected: false},
+		{url: "?tags[contains]=sql,go", expected: true},
+		{url: "?tags[contains]=go,rust", expected: false},
+		{url: "?name[ew]=im", expected: true},
+		{url: "?email[like]=*@example.com", expected: true},
+		{url: "?email[is]=null", expected: false},
+		{url: "?email[not]=null", expected: true},
+		{url: "?balance[gt]=10", expected: true},
+		{url: "?active=false", expected: false},
+		{url: "?unknown=1", expected: false},
+		{url: "?id=1|name=Tim", expected: true},
+		{url: "?id=1|name=Bob", expected: false},
+		{url: "?id=5&name=Bob", expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.expected, q.Match(user))
+			assert.Equal(t, c.expected, q.Match(&user))
+		})
+	}
+
+	// nil pointer is NULL
+	q := New().AddFilter("email", IS, NULL)
+	assert.True(t, q.Match(matchUser{}))
+	q = New().AddFilter("email", EQ, "tim@example.com")
+	assert.False(t, q.Match(matchUser{}))
+	assert.False(t, q.Match((*matchUser)(nil)))
+
+	// top level join
+	q = New().AddFilter("id", EQ, 1).AddFilter("name", EQ, "Tim")
+	assert.False(t, q.Match(user))
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	assert.True(t, q.Match(user))
+
+	// raw filters are ignored
+	q = New().AddFilterRaw("id > 100")
+	assert.True(t, q.Match(user))
+}
+
+func TestMatchSlice(t *testing.T) {
+	users := []matchUser{
+		{ID: 1, Name: "Tim", Balance: 3},
+		{ID: 2, Name: "Bob", Balance: 1},
+		{ID: 3, Name: "Ann", Balance: 2},
+		{ID: 4, Name: "Tom", Balance: 2},
+	}
+
+	q := NewQV(nil, Validations{"id:int": nil, "sort": In("balance", "name")})
+	assert.NoError(t, q.SetUrlString("?id[gt]=1&sort=-balance,name&limit=2"))
+	assert.NoError(t, q.Parse())
+
+	list := append([]matchUser{}, users...)
+	assert.NoError(t, q.MatchSlice(&list))
+	assert.Equal(t, []matchUser{users[2], users[3]}, list)
+
+	q.SetOffset(2)
+	list = append([]matchUser{}, users...)
+	assert.NoError(t, q.MatchSlice(&list))
+	assert.Equal(t, []matchUser{users[1]}, list)
+
+	q.SetOffset(10)
+	list = append([]matchUser{}, users...)
+	assert.NoError(t, q.MatchSlice(&list))
+	assert.Empty(t, list)
+
+	// slice of pointers
+	ptrs := []*matchUser{&users[0], &users[1]}
+	q = New().AddFilter("name", EQ, "Bob")
+	assert.NoError(t, q.MatchSlice(&ptrs))
+	assert.Equal(t, []*matchUser{&users[1]}, ptrs)
+
+	assert.Error(t, q.MatchSlice(users))
+
+	// patterns of regex are compiled once for all elements, invalid ones match nothing
+	q = New().AddFilter("name", REGEX, "^T").AddForcedFilter("name", REGEX, "m$")
+	assert.Len(t, q.regexps(), 2)
+	list = append([]matchUser{}, users...)
+	assert.NoError(t, q.MatchSlice(&list))
+	assert.Equal(t, []matchUser{users[0], users[3]}, list)
+
+	q = New().AddFilter("name", REGEX, "(")
+	re := q.regexps()
+	assert.Contains(t, re, "(")
+	assert.Nil(t, re["("])
+	list = append([]matchUser{}, users...)
+	assert.NoError(t, q.MatchSlice(&list))
+	assert.Empty(t, list)
+}
+
+func TestMatchFunc(t *testing.T) {
+	var docs []map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(`[
+		{"id": 1, "name": "Tim", "tags": ["a", "b"], "deleted_at": null},
+		{"id": 2, "name": "Bob", "deleted_at": "2020-01-01"},
+		{"id": 3, "name": "tina"}
+	]`))
+	dec.UseNumber()
+	assert.NoError(t, dec.Decode(&docs))
+	name := "Tom"
+
+	cases := []struct {
+		url     string
+		matched []bool
+	}{
+		{"?id[gte]=2", []bool{false, true, true}},
+		{"?id[in]=1,3", []bool{true, false, true}},
+		{"?name[ilike]=t*", []bool{true, false, true}},
+		{"?deleted_at[is]=null", []bool{true, false, false}},
+		{"?deleted_at[not]=null", []bool{false, true, false}},
+		{"?id=1|name=Bob", []bool{true, true, false}},
+		{"?tags[contains]=b", []bool{true, false, false}},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, Validations{
+				"id:int":     nil,
+				"name":       nil,
+				"deleted_at": nil,
+				"tags":       nil,
+			})
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			match := q.MatchFunc()
+			for i, doc := range docs {
+				assert.Equal(t, c.matched[i], match(doc), i)
+			}
+		})
+	}
+
+	// values of Go types and pointers
+	match := New().AddFilter("name", EQ, "Tom").AddFilter("id", LT, 10).MatchFunc()
+	assert.True(t, match(map[string]interface{}{"name": &name, "id": int64(5)}))
+	assert.False(t, match(map[string]interface{}{"name": (*string)(nil), "id": 5}))
+	assert.False(t, match(map[string]interface{}{"id": 5}))
+}
+
+func Test_likeMatch(t *testing.T) {
+	cases := []struct {
+		s, pattern string
+		fold       bool
+		expected   bool
+	}{
+		{"tim", "tim", false, true},
+		{"tim", "Tim", false, false},
+		{"tim", "Tim", true, true},
+		{"tim", "t%", false, true},
+		{"tim", "%m", false, true},
+		{"tim", "%i%", false, true},
+		{"tim", "t_m", false, true},
+		{"tim", "t__m", false, false},
+		{"aaab", "%ab", false, true},
+		{"", "%", false, true},
+		{"", "_", false, false},
+		{"50%", `50\%`, false, true},
+		{"500", `50\%`, false, false},
+		{"a_b", `%\_%`, false, true},
+		{"ab", `%\_%`, false, false},
+		{`a\b`, `a\\b`, false, true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, likeMatch(c.s, c.pattern, c.fold), c.s+" "+c.pattern)
+	}
+}
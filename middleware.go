@@ -0,0 +1,48 @@
+package rqp
+
+import (
+	"context"
+	"net/http"
+)
+
+// contextKey is a type of keys of values stored in context by this package
+type contextKey struct{}
+
+// queryKey is a key of parsed Query in context of request
+var queryKey = contextKey{}
+
+// Middleware returns net/http middleware which parses query of every request by Validations v
+// and options set by configure functions (see NewFactory). Requests with bad query get
+// 400 Bad Request with text of the error, handlers of others get the Query by FromContext.
+// Example:
+//
+//	mux.Handle("/users", rqp.Middleware(rqp.Validations{"id:int": nil})(usersHandler))
+//
+//	func usersHandler(w http.ResponseWriter, r *http.Request) {
+//		q, _ := rqp.FromContext(r.Context())
+//		rows, err := db.Query(q.SQL("users"), q.Args()...)
+//	}
+func Middleware(v Validations, configure ...func(q *Query)) func(http.Handler) http.Handler {
+	f := NewFactory(v, configure...)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := f.New()
+			if err := q.ParseRequest(r); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), q)))
+		})
+	}
+}
+
+// NewContext returns a copy of ctx with Query q
+func NewContext(ctx context.Context, q *Query) context.Context {
+	return context.WithValue(ctx, queryKey, q)
+}
+
+// FromContext returns Query stored in ctx by Middleware or NewContext
+func FromContext(ctx context.Context) (*Query, bool) {
+	q, ok := ctx.Value(queryKey).(*Query)
+	return q, ok
+}
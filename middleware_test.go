@@ -0,0 +1,45 @@
+package rqp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	mw := Middleware(Validations{"id:int": nil}, func(q *Query) {
+		q.SetPlaceholder(PlaceholderDollar)
+	})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q, ok := FromContext(r.Context())
+		assert.True(t, ok)
+		_, _ = w.Write([]byte(q.Where()))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?id=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id = $1", w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?id=a", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "id: bad format\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?name=tim", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	q := New()
+	got, ok := FromContext(NewContext(context.Background(), q))
+	assert.True(t, ok)
+	assert.Same(t, q, got)
+}
@@ -0,0 +1,83 @@
+package rqp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var notNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// WhereNamed returns list of filters for WHERE statement like Where but with named
+// bind variables, eg. for sqlx.NamedQuery. Values of variables are returned by NamedArgs.
+// Names are taken from names of filters, repeated names get number suffix:
+//
+//	id = :id AND name IN (:name, :name_2)
+func (q *Query) WhereNamed() string {
+	where, _ := q.named()
+	return where
+}
+
+// NamedArgs returns values of bind variables of WhereNamed by their names
+func (q *Query) NamedArgs() map[string]interface{} {
+	_, args := q.named()
+	return args
+}
+
+// named returns WHERE statement with named bind variables and their values
+func (q *Query) named() (string, map[string]interface{}) {
+	args := make(map[string]interface{})
+
+	where := q.render(func(filter *Filter) (string, bool) {
+		a, err := filter.where(q)
+		if err != nil {
+			return "", false
+		}
+		if filter.Method == raw || isNullComparison(filter) {
+			return a, true
+		}
+
+		values, err := filter.args(q)
+		if err != nil {
+			return "", false
+		}
+
+		var b strings.Builder
+		for _, v := range values {
+			i := strings.IndexByte(a, '?')
+			if i == -1 {
+				break
+			}
+			name := uniqueName(bindName(filter.Name), args)
+			args[name] = v
+
+			b.WriteString(a[:i])
+			b.WriteString(":" + name)
+			a = a[i+1:]
+		}
+		b.WriteString(a)
+
+		return b.String(), true
+	})
+
+	return where, args
+}
+
+// bindName returns name of filter which could be used as name of bind variable:
+// users.id -> users_id
+func bindName(name string) string {
+	return notNameRegexp.ReplaceAllString(name, "_")
+}
+
+// uniqueName returns name with number suffix if the name is already used
+func uniqueName(name string, used map[string]interface{}) string {
+	if _, ok := used[name]; !ok {
+		return name
+	}
+	for i := 2; ; i++ {
+		n := name + "_" + strconv.Itoa(i)
+		if _, ok := used[n]; !ok {
+			return n
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhereNamed(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "name": nil, "u": nil, "price:int": nil})
+	assert.NoError(t, q.SetUrlString("?id[in]=1,2&name[is]=null&price[between]=10,20&u[like]=a|*b"))
+	assert.NoError(t, q.Parse())
+	q.AddFilter("users.id", GT, 5)
+	q.AddFilterRaw("data ? 'key'")
+
+	assert.Equal(t, "id IN (:id, :id_2) AND name IS NULL AND price BETWEEN :price AND :price_2 AND "+
+		"(u LIKE :u OR u LIKE :u_2) AND users.id > :users_id AND data ? 'key'", q.WhereNamed())
+	assert.Equal(t, map[string]interface{}{
+		"id":       1,
+		"id_2":     2,
+		"price":    10,
+		"price_2":  20,
+		"u":        "a",
+		"u_2":      "%b",
+		"users_id": 5,
+	}, q.NamedArgs())
+
+	// disabled filters are skipped
+	assert.NoError(t, q.DisableFilter("id"))
+	assert.Equal(t, "name IS NULL AND price BETWEEN :price AND :price_2 AND "+
+		"(u LIKE :u OR u LIKE :u_2) AND users.id > :users_id AND data ? 'key'", q.WhereNamed())
+
+	assert.Equal(t, "", New().WhereNamed())
+	assert.Equal(t, map[string]interface{}{}, New().NamedArgs())
+}
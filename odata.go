@@ -0,0 +1,320 @@
+package rqp
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// odataMethods are methods of comparison operators and functions of OData
+var odataMethods = map[string]Method{
+	"eq":         EQ,
+	"ne":         NE,
+	"gt":         GT,
+	"ge":         GTE,
+	"lt":         LT,
+	"le":         LTE,
+	"in":         IN,
+	"contains":   CONTAINS_STR,
+	"startswith": STARTS,
+	"endswith":   ENDS,
+}
+
+// odataQuery translates query of OData syntax:
+//
+//	$filter=age gt 18 and name eq 'Bob' -> age[gt]=18&name[eq]=Bob
+//	$orderby=created_at desc,id         -> sort=-created_at,id
+//	$select=id,name                     -> fields=id,name
+//	$top=10&$skip=20                    -> limit=10&offset=20
+//	$search=tim                         -> q=tim (see SetSearchColumns)
+func (q *Query) odataQuery() (url.Values, error) {
+	values := make(url.Values, len(q.query))
+
+	for key, list := range q.query {
+		switch key {
+		case "$filter":
+			for _, s := range list {
+				if err := q.odataFilter(values, s); err != nil {
+					return nil, err
+				}
+			}
+		case "$orderby":
+			for _, s := range list {
+				sort, err := q.odataOrderBy(s)
+				if err != nil {
+					return nil, err
+				}
+				values.Add("sort", sort)
+			}
+		case "$select":
+			values["fields"] = append(values["fields"], list...)
+		case "$top":
+			values["limit"] = append(values["limit"], list...)
+		case "$skip":
+			values["offset"] = append(values["offset"], list...)
+		case "$search":
+			values[q.searchKey] = append(values[q.searchKey], list...)
+		default:
+			values[key] = append(values[key], list...)
+		}
+	}
+
+	return values, nil
+}
+
+// odataOrderBy translates $orderby to sort parameter: `created_at desc,id` -> `-created_at,id`
+func (q *Query) odataOrderBy(s string) (string, error) {
+	parts := strings.Split(s, ",")
+	for i, p := range parts {
+		f := strings.Fields(p)
+		switch {
+		case len(f) == 1 || len(f) == 2 && strings.EqualFold(f[1], "asc"):
+			parts[i] = f[0]
+		case len(f) == 2 && strings.EqualFold(f[1], "desc"):
+			parts[i] = "-" + f[0]
+		default:
+			return "", errors.Wrapf(ErrBadFormat, "$orderby: %s", strings.TrimSpace(p))
+		}
+	}
+	return strings.Join(parts, q.delimiterIN), nil
+}
+
+// odataFilter translates $filter expression to filters of values.
+// Conditions are joined by AND, OR statements of conditions must be in parentheses
+// if they are joined with others: `(status eq 'a' or status eq 'b') and age gt 18`.
+func (q *Query) odataFilter(values url.Values, s string) error {
+	p := &odataParser{tokens: odataTokens(s)}
+	groups, err := p.parse()
+	if err == nil {
+		err = q.addConditions(values, groups)
+	}
+	if err != nil {
+		return errors.Wrapf(ErrBadFormat, "$filter: %v", err)
+	}
+	return nil
+}
+
+// odataTokens splits $filter expression into words, string literals, parentheses and commas
+func odataTokens(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'':
+			// string literal keeps quotes, '' is an escaped quote
+			j := i + 1
+			for j < len(s) {
+				if s[j] == '\'' {
+					if j+1 < len(s) && s[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if j < len(s) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t(),'", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+// odataParser parses tokens of $filter expression
+type odataParser struct {
+	tokens []string
+	pos    int
+}
+
+// next returns the next token without moving to it
+func (p *odataParser) next() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// take returns the next token and moves to it
+func (p *odataParser) take() string {
+	t := p.next()
+	p.pos++
+	return t
+}
+
+// expect moves to the next token if it's t
+func (p *odataParser) expect(t string) error {
+	if got := p.take(); !strings.EqualFold(got, t) {
+		return errors.Errorf("expected %q, got %q", t, got)
+	}
+	return nil
+}
+
+// parse returns conditions of expression grouped by OR statements, groups are joined by AND
+func (p *odataParser) parse() ([][]condition, error) {
+	groups, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.next(); t != "" {
+		return nil, errors.Errorf("unexpected %q", t)
+	}
+	return groups, nil
+}
+
+// or parses `and-expression or and-expression ...`
+func (p *odataParser) or() ([][]condition, error) {
+	groups, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(p.next(), "or") {
+		return groups, nil
+	}
+
+	var or []condition
+	for {
+		// only single conditions or OR statements could be joined by OR
+		if len(groups) > 1 {
+			return nil, errors.New("and inside of or must be in parentheses of its own filter")
+		}
+		or = append(or, groups[0]...)
+		if !strings.EqualFold(p.next(), "or") {
+			return [][]condition{or}, nil
+		}
+		p.take()
+		if groups, err = p.and(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// and parses `term and term ...`
+func (p *odataParser) and() ([][]condition, error) {
+	var groups [][]condition
+	for {
+		g, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g...)
+		if !strings.EqualFold(p.next(), "and") {
+			return groups, nil
+		}
+		p.take()
+	}
+}
+
+// term parses expression in parentheses, function or comparison
+func (p *odataParser) term() ([][]condition, error) {
+	t := p.take()
+	switch {
+	case t == "(":
+		groups, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return groups, p.expect(")")
+	case isODataFunction(t) && p.next() == "(":
+		p.take()
+		name := p.take()
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		value, _, err := odataLiteral(p.take())
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return [][]condition{{{name: name, method: odataMethods[strings.ToLower(t)], values: []string{value}}}}, nil
+	case t == "" || t == ")" || t == ",":
+		return nil, errors.Errorf("unexpected %q", t)
+	}
+
+	name, op := t, strings.ToLower(p.take())
+	m, ok := odataMethods[op]
+	if !ok || isODataFunction(op) {
+		return nil, errors.Errorf("unknown operator %q", op)
+	}
+
+	var values []string
+	if m == IN {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			value, _, err := odataLiteral(p.take())
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if p.next() != "," {
+				break
+			}
+			p.take()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	} else {
+		value, null, err := odataLiteral(p.take())
+		if err != nil {
+			return nil, err
+		}
+		// comparison to null is IS NULL or IS NOT NULL, string 'null' is compared as usual
+		if null && (m == EQ || m == NE) {
+			if m == EQ {
+				m = IS
+			} else {
+				m = NOT
+			}
+		}
+		values = []string{value}
+	}
+
+	return [][]condition{{{name: name, method: m, values: values}}}, nil
+}
+
+// isODataFunction returns true for string functions of $filter
+func isODataFunction(t string) bool {
+	switch strings.ToLower(t) {
+	case "contains", "startswith", "endswith":
+		return true
+	default:
+		return false
+	}
+}
+
+// odataLiteral returns value of literal: text of string without quotes, NULL for null
+// and the same text for numbers, booleans, dates, etc. It returns true for null.
+func odataLiteral(t string) (string, bool, error) {
+	switch {
+	case t == "" || t == "(" || t == ")" || t == ",":
+		return "", false, errors.Errorf("expected value, got %q", t)
+	case strings.HasPrefix(t, "'"):
+		if len(t) < 2 || !strings.HasSuffix(t, "'") {
+			return "", false, errors.Errorf("unterminated string %s", t)
+		}
+		return strings.ReplaceAll(t[1:len(t)-1], "''", "'"), false, nil
+	case strings.EqualFold(t, "null"):
+		return NULL, true, nil
+	default:
+		return t, false, nil
+	}
+}
@@ -0,0 +1,106 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxOData(t *testing.T) {
+	validations := Validations{
+		"fields":          In("id", "name"),
+		"sort":            In("id", "created_at"),
+		"name":            nil,
+		"age:int":         nil,
+		"status":          nil,
+		"deleted_at":      nil,
+		"created_at:time": nil,
+	}
+
+	cases := []struct {
+		filter string
+		where  string
+		args   []interface{}
+	}{
+		{
+			filter: "age gt 18 and name eq 'Bob'",
+			where:  "age > ? AND name = ?",
+			args:   []interface{}{18, "Bob"},
+		},
+		{
+			filter: "(status eq 'a' or status eq 'b') and age le 65",
+			where:  "age <= ? AND (status = ? OR status = ?)",
+			args:   []interface{}{65, "a", "b"},
+		},
+		{
+			filter: "status eq 'a' or name ne 'O''Brien' or (age lt 10 or age ge 60)",
+			where:  "(status = ? OR name != ? OR age < ? OR age >= ?)",
+			args:   []interface{}{"a", "O'Brien", 10, 60},
+		},
+		{
+			filter: "status in ('a', 'b') and deleted_at eq null and name ne null",
+			where:  "deleted_at IS NULL AND name IS NOT NULL AND status IN (?, ?)",
+			args:   []interface{}{"a", "b"},
+		},
+		{
+			filter: "contains(name,'im') and startswith(status, 'ac') and endswith(status,'ve')",
+			where:  "name LIKE ? AND status LIKE ? AND status LIKE ?",
+			args:   []interface{}{"%im%", "%ve", "ac%"},
+		},
+		{
+			// quoted null is a string
+			filter: "name eq 'NULL' and status ne 'null'",
+			where:  "name = ? AND status != ?",
+			args:   []interface{}{"NULL", "null"},
+		},
+		{
+			filter: "name eq 'a,b'",
+			where:  "name = ?",
+			args:   []interface{}{"a,b"},
+		},
+		{
+			filter: "AGE GE 1 And created_at lt 2020-01-02",
+			where:  "AGE >= ? AND created_at < ?",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.filter, func(t *testing.T) {
+			v := Validations{"AGE:int": nil}
+			for k, f := range validations {
+				v[k] = f
+			}
+			q := NewQV(nil, v).SetSyntax(SyntaxOData)
+			q.SetUrlQuery(map[string][]string{"$filter": {c.filter}})
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.where, q.Where())
+			if c.args != nil {
+				assert.Equal(t, c.args, q.Args())
+			}
+		})
+	}
+
+	q := NewQV(nil, validations).SetSyntax(SyntaxOData)
+	assert.NoError(t, q.SetUrlString("?$select=id,name&$orderby=created_at desc,id asc&$top=10&$skip=20&$filter=age gt 1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "SELECT id, name FROM t WHERE age > ? ORDER BY created_at DESC, id LIMIT 10 OFFSET 20", q.SQL("t"))
+
+	errs := []struct {
+		query map[string][]string
+		err   string
+	}{
+		{map[string][]string{"$filter": {"age gt"}}, `$filter: expected value, got "": bad format`},
+		{map[string][]string{"$filter": {"age gtx 1"}}, `$filter: unknown operator "gtx": bad format`},
+		{map[string][]string{"$filter": {"(age gt 1"}}, `$filter: expected ")", got "": bad format`},
+		{map[string][]string{"$filter": {"age gt 1 and name eq 'a' or status eq 'b'"}}, `$filter: and inside of or must be in parentheses of its own filter: bad format`},
+		{map[string][]string{"$filter": {"name eq 'a"}}, `$filter: unterminated string 'a: bad format`},
+		{map[string][]string{"$filter": {"age gt 1 age"}}, `$filter: unexpected "age": bad format`},
+		{map[string][]string{"$filter": {"age gt 'a'"}}, `age[gt]: bad format`},
+		{map[string][]string{"$orderby": {"id up"}}, `$orderby: id up: bad format`},
+		{map[string][]string{"$filter": {"name eq 'x|id[eq]=5'"}}, `$filter: value "x|id[eq]=5" contains "|": bad format`},
+		{map[string][]string{"$filter": {"status in ('a,b', 'c')"}}, `$filter: value "a,b" contains ",": bad format`},
+	}
+	for _, c := range errs {
+		q.SetUrlQuery(c.query)
+		assert.EqualError(t, q.Parse(), c.err)
+	}
+}
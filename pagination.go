@@ -0,0 +1,88 @@
+package rqp
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// PaginationStyle is a set of pagination parameters accepted by Query
+type PaginationStyle byte
+
+// Pagination styles, they could be combined: PaginationOffset | PaginationPage
+const (
+	PaginationOffset PaginationStyle = 1 << iota // limit and offset parameters (default)
+	PaginationPage                               // page and per_page parameters
+)
+
+// maxInt is the biggest int, offset of page mustn't overflow it
+const maxInt = int(^uint(0) >> 1)
+
+// SetPaginationStyle sets pagination parameters accepted by Query.
+// With PaginationPage `page=3&per_page=25` is the same as `limit=25&offset=50`.
+// Parameters of not accepted style are handled like unknown filters.
+// Both styles can't be used in one request.
+func (q *Query) SetPaginationStyle(s PaginationStyle) *Query {
+	q.pagination = s
+	return q
+}
+
+// acceptsPagination returns true if pagination style s is accepted
+func (q *Query) acceptsPagination(s PaginationStyle) bool {
+	if q.pagination == 0 {
+		// JSON:API has both styles of pagination
+		return s == PaginationOffset || (q.syntax == SyntaxJSONAPI && s == PaginationPage)
+	}
+	return q.pagination&s != 0
+}
+
+// isPageKey returns true if key is a parameter of page pagination
+func (q *Query) isPageKey(key string) bool {
+	return q.acceptsPagination(PaginationPage) && (key == "page" || key == "per_page")
+}
+
+// parsePage sets Limit and Offset from "page" and "per_page" parameters.
+// "per_page" could be omitted if Limit is set by SetLimit.
+func (q *Query) parsePage(pages map[string][]string) error {
+	if value, ok := pages["per_page"]; ok {
+		if err := q.parseLimit(value, q.validations["per_page"]); err != nil {
+			return errors.Wrap(err, "per_page")
+		}
+	}
+
+	value, ok := pages["page"]
+	if !ok {
+		return nil
+	}
+
+	if q.Limit <= 0 {
+		return errors.Wrap(ErrRequired, "per_page")
+	}
+
+	value, err := q.singleValue(value)
+	if err != nil {
+		return errors.Wrap(err, "page")
+	}
+
+	page, err := strconv.Atoi(value[0])
+	if err != nil {
+		return errors.Wrap(ErrBadFormat, "page")
+	}
+
+	if page < 1 {
+		return errors.Wrapf(ErrNotInScope, "page: %d", page)
+	}
+
+	if validate := q.validations["page"]; validate != nil {
+		if err := validate(page); err != nil {
+			return errors.Wrap(err, "page")
+		}
+	}
+
+	if page-1 > maxInt/q.Limit {
+		return errors.Wrapf(ErrNotInScope, "page: %d", page)
+	}
+
+	q.Offset = (page - 1) * q.Limit
+	return nil
+}
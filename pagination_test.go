@@ -0,0 +1,67 @@
+package rqp
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginationStyle(t *testing.T) {
+	cases := []struct {
+		style  PaginationStyle
+		url    string
+		limit  int
+		offset int
+		err    string
+	}{
+		{style: PaginationPage, url: "?page=3&per_page=25", limit: 25, offset: 50},
+		{style: PaginationPage, url: "?page=1&per_page=25", limit: 25, offset: 0},
+		{style: PaginationPage, url: "?per_page=25", limit: 25, offset: 0},
+		{style: PaginationPage, url: "?page=2", err: "per_page: required"},
+		{style: PaginationPage, url: "?page=0&per_page=10", err: "page: 0: not in scope"},
+		{style: PaginationPage, url: "?page=a&per_page=10", err: "page: bad format"},
+		{style: PaginationPage, url: "?page=" + strconv.Itoa(maxInt/10+2) + "&per_page=10", err: "page: " + strconv.Itoa(maxInt/10+2) + ": not in scope"},
+		{style: PaginationPage, url: "?page=" + strconv.Itoa(maxInt/10+1) + "&per_page=10", limit: 10, offset: maxInt / 10 * 10},
+		{style: PaginationPage, url: "?page=1&per_page=0", err: "per_page: 0: not in scope"},
+		{style: PaginationPage, url: "?page=1&per_page=1000", err: "per_page: 1000: not in scope"},
+		{style: PaginationPage, url: "?limit=10", err: "limit: filter not found"},
+		{style: PaginationOffset | PaginationPage, url: "?limit=10&offset=20", limit: 10, offset: 20},
+		{style: PaginationOffset | PaginationPage, url: "?page=2&per_page=10", limit: 10, offset: 10},
+		{style: PaginationOffset | PaginationPage, url: "?page=2&limit=10", err: "page can't be used with limit or offset: bad format"},
+		{style: 0, url: "?page=2", err: "page: filter not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, Validations{"per_page": Max(100)}).SetPaginationStyle(c.style)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if len(c.err) > 0 {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.limit, q.Limit)
+			assert.Equal(t, c.offset, q.Offset)
+		})
+	}
+
+	// per_page could be set by server
+	q := New().SetPaginationStyle(PaginationPage).SetLimit(20)
+	assert.NoError(t, q.SetUrlString("?page=3"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, " LIMIT 20 OFFSET 40", q.PAGINATION())
+	assert.Equal(t, "page=3&per_page=20", q.ToQueryString())
+	QueryEqual(t, q, q.Clone())
+
+	// not accepted parameters could be ignored
+	q = New().SetPaginationStyle(PaginationPage).IgnoreUnknownFilters(true)
+	assert.NoError(t, q.SetUrlString("?offset=10"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []string{"offset"}, q.Unknown())
+
+	q = New().SetPaginationStyle(PaginationPage)
+	assert.NoError(t, q.SetUrlString("?page=1&page=2&per_page=1"))
+	assert.Equal(t, ErrBadFormat, errors.Cause(q.Parse()))
+}
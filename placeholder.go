@@ -0,0 +1,93 @@
+package rqp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Placeholder is a style of bind variables in generated statements
+type Placeholder byte
+
+// Placeholders:
+const (
+	PlaceholderQuestion Placeholder = iota // ?
+	PlaceholderDollar                      // $1
+	PlaceholderColon                       // :p1
+	PlaceholderAtP                         // @p1
+)
+
+// format returns bind variable with number n
+func (p Placeholder) format(n int) string {
+	switch p {
+	case PlaceholderDollar:
+		return "$" + strconv.Itoa(n)
+	case PlaceholderColon:
+		return ":p" + strconv.Itoa(n)
+	case PlaceholderAtP:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// SetPlaceholder sets style of bind variables in WHERE statement.
+// Numbered styles are numbered across all filters in order of Args including expanded IN lists:
+//
+//	q.SetPlaceholder(rqp.PlaceholderDollar)
+//	q.Where() // id = $1 AND name IN ($2, $3)
+func (q *Query) SetPlaceholder(p Placeholder) *Query {
+	q.invalidate()
+	q.placeholder = p
+	return q
+}
+
+// Placeholder returns style of bind variables in WHERE statement, see SetPlaceholder
+func (q *Query) Placeholder() Placeholder {
+	return q.placeholder
+}
+
+// WhereQuestion returns WHERE statement with `?` placeholders whatever placeholder of q is
+// and arguments of it, eg. for query builders which replace `?` by placeholders of their drivers.
+// Unlike SetPlaceholder it doesn't change the Query.
+func (q *Query) WhereQuestion() (string, []interface{}) {
+	if q.placeholder == PlaceholderQuestion {
+		return q.Where(), q.Args()
+	}
+
+	where := q.render(func(filter *Filter) (string, bool) {
+		a, err := filter.where(q)
+		return a, err == nil
+	})
+	return where, q.Args()
+}
+
+// HavingQuestion returns HAVING statement with `?` placeholders whatever placeholder of q is
+// and arguments of it like WhereQuestion.
+func (q *Query) HavingQuestion() (string, []interface{}) {
+	var b strings.Builder
+	q.renderGroups(&b, orGroups(q.Havings), "AND", func(filter *Filter) (string, bool) {
+		a, err := filter.where(q)
+		return a, err == nil
+	})
+	return b.String(), q.HavingArgs()
+}
+
+// rebind replaces `?` bind variables of exp by placeholder of q numbered from n.
+// It returns next number.
+func (q *Query) rebind(exp string, n int) (string, int) {
+	if q.placeholder == PlaceholderQuestion {
+		return exp, n + strings.Count(exp, "?")
+	}
+
+	var b strings.Builder
+	b.Grow(len(exp) + 2)
+	for i := strings.IndexByte(exp, '?'); i != -1; i = strings.IndexByte(exp, '?') {
+		b.WriteString(exp[:i])
+		b.WriteString(q.placeholder.format(n))
+		n++
+		exp = exp[i+1:]
+	}
+	b.WriteString(exp)
+
+	return b.String(), n
+}
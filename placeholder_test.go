@@ -0,0 +1,60 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceholder_format(t *testing.T) {
+	assert.Equal(t, "?", PlaceholderQuestion.format(3))
+	assert.Equal(t, "$3", PlaceholderDollar.format(3))
+	assert.Equal(t, ":p3", PlaceholderColon.format(3))
+	assert.Equal(t, "@p3", PlaceholderAtP.format(3))
+}
+
+func TestSetPlaceholder(t *testing.T) {
+	validations := Validations{
+		"id:int":    nil,
+		"name":      nil,
+		"u":         nil,
+		"price:int": nil,
+	}
+
+	cases := []struct {
+		style    Placeholder
+		expected string
+	}{
+		{PlaceholderQuestion, "id IN (?, ?) AND name IS NULL AND price BETWEEN ? AND ? AND (u LIKE ? OR u LIKE ?) AND data ? 'key'"},
+		{PlaceholderDollar, "id IN ($1, $2) AND name IS NULL AND price BETWEEN $3 AND $4 AND (u LIKE $5 OR u LIKE $6) AND data ? 'key'"},
+		{PlaceholderColon, "id IN (:p1, :p2) AND name IS NULL AND price BETWEEN :p3 AND :p4 AND (u LIKE :p5 OR u LIKE :p6) AND data ? 'key'"},
+		{PlaceholderAtP, "id IN (@p1, @p2) AND name IS NULL AND price BETWEEN @p3 AND @p4 AND (u LIKE @p5 OR u LIKE @p6) AND data ? 'key'"},
+	}
+	for _, c := range cases {
+		q := NewQV(nil, validations).SetPlaceholder(c.style)
+		assert.NoError(t, q.SetUrlString("?id[in]=1,2&name[is]=null&price[between]=10,20&u[like]=a|*b"))
+		assert.NoError(t, q.Parse())
+		// raw filters aren't changed
+		q.AddFilterRaw("data ? 'key'")
+
+		assert.Equal(t, c.style, q.Placeholder())
+		assert.Equal(t, c.expected, q.Where())
+		assert.Equal(t, []interface{}{1, 2, 10, 20, "a", "%b"}, q.Args())
+		QueryEqual(t, q, q.Clone())
+
+		q.Havings = append(q.Havings, &Filter{Name: "count(*)", Method: GTE, Value: 2})
+		having, hargs := q.HavingQuestion()
+		assert.Equal(t, "count(*) >= ?", having)
+		assert.Equal(t, []interface{}{2}, hargs)
+		q.Havings = nil
+
+		where, args := q.WhereQuestion()
+		assert.Equal(t, cases[0].expected, where)
+		assert.Equal(t, []interface{}{1, 2, 10, 20, "a", "%b"}, args)
+		assert.Equal(t, c.expected, q.Where())
+	}
+
+	q := New().SetPlaceholder(PlaceholderDollar).SetDialect(DialectPostgres).SetAnyIN(true)
+	q.AddFilter("id", IN, []int{1, 2}).AddFilter("name", EQ, "tim")
+	assert.Equal(t, "SELECT * FROM users WHERE id = ANY($1) AND name = $2", q.SQL("users"))
+}
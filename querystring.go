@@ -0,0 +1,157 @@
+package rqp
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToQueryString returns parsed state of Query as normalized query part of URL.
+// Keys and values are sorted so the same filters, fields, sorts, limit and offset
+// always produce the same string. It could be used as a cache key or for building
+// pagination links. Parse of the result gives the same Query only with the default syntax
+// (see SetSyntax) and default layouts of time and date (see SetTimeLayouts, SetDateLayouts),
+// because filters are formatted as `name[method]=value`, times as RFC 3339 and dates as `2006-01-02`.
+// Raw filters (see AddFilterRaw) are not included.
+func (q *Query) ToQueryString() string {
+	values := url.Values{}
+
+	if len(q.Fields) > 0 {
+		values.Set("fields", strings.Join(q.Fields, q.delimiterIN))
+	}
+
+	if len(q.Sorts) > 0 {
+		list := make([]string, len(q.Sorts))
+		for i, s := range q.Sorts {
+			if s.Desc {
+				list[i] = "-" + s.By
+			} else {
+				list[i] = s.By
+			}
+		}
+		values.Set("sort", strings.Join(list, q.delimiterIN))
+	}
+
+	if len(q.Group) > 0 {
+		values.Set("group", strings.Join(q.Group, q.delimiterIN))
+	}
+
+	if len(q.includes) > 0 {
+		values.Set("include", strings.Join(q.includes, q.delimiterIN))
+	}
+
+	if q.Distinct {
+		values.Set("distinct", "true")
+	}
+
+	if !q.acceptsPagination(PaginationOffset) {
+		if q.Limit > 0 {
+			values.Set("per_page", strconv.Itoa(q.Limit))
+			if q.Offset > 0 {
+				values.Set("page", strconv.Itoa(q.Offset/q.Limit+1))
+			}
+		}
+	} else {
+		if q.Limit > 0 {
+			values.Set("limit", strconv.Itoa(q.Limit))
+		}
+
+		if q.Offset > 0 {
+			values.Set("offset", strconv.Itoa(q.Offset))
+		}
+	}
+
+	for _, group := range q.groups() {
+		first := group[0]
+
+		// search filter is the same for all columns
+		if len(q.searchColumns) > 0 && first.Key == q.searchKey && first.Method == CONTAINS_STR {
+			if s, ok := first.Value.(string); ok {
+				values.Add(q.searchKey, s)
+			}
+			continue
+		}
+
+		var parts []string
+		for _, f := range group {
+			if k, ok := f.Value.(keyset); ok && f.Method == cursor {
+				values.Add(f.Key, k.cursor)
+				continue
+			}
+			if f.Method == raw {
+				if _, ok := q.columnComparisons[f.Key]; ok {
+					values.Add(f.Key, "true")
+				}
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%s=%s", filterKey(f), formatValue(f.Value, q.delimiterIN)))
+		}
+		if len(parts) == 0 {
+			continue
+		}
+
+		key := strings.SplitN(parts[0], "=", 2)
+		values.Add(key[0], strings.Join(append([]string{key[1]}, parts[1:]...), q.delimiterOR))
+	}
+
+	for _, group := range orGroups(q.Havings) {
+		parts := make([]string, len(group))
+		for i, f := range group {
+			parts[i] = fmt.Sprintf("%s=%s", havingQueryKey(f), formatValue(f.Value, q.delimiterIN))
+		}
+		key := strings.SplitN(parts[0], "=", 2)
+		values.Add(key[0], strings.Join(append([]string{key[1]}, parts[1:]...), q.delimiterOR))
+	}
+
+	for key := range values {
+		sort.Strings(values[key])
+	}
+
+	return values.Encode()
+}
+
+// filterKey returns key of filter for query part of URL
+//   id[gt] for GT method, id for EQ and RANGE methods
+func filterKey(f *Filter) string {
+	if f.Method == EQ || f.Method == RANGE {
+		return f.Name
+	}
+	return fmt.Sprintf("%s[%s]", f.Name, strings.ToLower(string(f.Method)))
+}
+
+// formatValue returns value of filter as string for query part of URL
+func formatValue(value interface{}, delimiter string) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, delimiter)
+	case []int:
+		list := make([]string, len(v))
+		for i := range v {
+			list[i] = strconv.Itoa(v[i])
+		}
+		return strings.Join(list, delimiter)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []float64:
+		list := make([]string, len(v))
+		for i := range v {
+			list[i] = strconv.FormatFloat(v[i], 'f', -1, 64)
+		}
+		return strings.Join(list, delimiter)
+	case time.Time:
+		return formatTime(v)
+	case []time.Time:
+		list := make([]string, len(v))
+		for i := range v {
+			list[i] = formatTime(v[i])
+		}
+		return strings.Join(list, delimiter)
+	case Range:
+		return v.format(delimiter)
+	default:
+		return fmt.Sprint(v)
+	}
+}
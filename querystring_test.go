@@ -0,0 +1,61 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToQueryString(t *testing.T) {
+	validations := Validations{
+		"fields": In("id", "name"),
+		"sort":   In("id", "name"),
+		"id:int": nil,
+		"name":   nil,
+		"u":      nil,
+	}
+
+	cases := []struct {
+		url      string
+		expected string
+	}{
+		{url: "?", expected: ""},
+		{url: "?id=1", expected: "id=1"},
+		{url: "?id[eq]=1", expected: "id=1"},
+		{url: "?fields=name,id&sort=-id,name&limit=10&offset=20", expected: "fields=name%2Cid&limit=10&offset=20&sort=-id%2Cname"},
+		{url: "?offset=0", expected: ""},
+		{url: "?id[in]=1,2&name[like]=*tim*", expected: "id%5Bin%5D=1%2C2&name%5Blike%5D=%2Atim%2A"},
+		{url: "?u[not]=null", expected: "u%5Bnot%5D=NULL"},
+		{url: "?id=2&id=1", expected: "id=1&id=2"},
+		{url: "?id[gt]=1|name=tim|u=bob", expected: "id%5Bgt%5D=1%7Cname%3Dtim%7Cu%3Dbob"},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			s := q.ToQueryString()
+			assert.Equal(t, c.expected, s)
+
+			// parse of the result gives the same query
+			q2 := NewQV(nil, validations)
+			assert.NoError(t, q2.SetUrlString("?"+s))
+			assert.NoError(t, q2.Parse())
+			assert.Equal(t, s, q2.ToQueryString())
+			assert.Equal(t, q.Fields, q2.Fields)
+			assert.Equal(t, q.Sorts, q2.Sorts)
+			assert.Equal(t, q.Limit, q2.Limit)
+			assert.Equal(t, q.Offset, q2.Offset)
+			assert.ElementsMatch(t, q.Args(), q2.Args())
+		})
+	}
+}
+
+func TestToQueryStringSpecialFilters(t *testing.T) {
+	q := New().AddValidation("id:int", nil).SetSearchColumns("firstname", "lastname")
+	assert.NoError(t, q.RegisterColumnComparison("valid", "start_date", LTE, "end_date"))
+	assert.NoError(t, q.SetUrlString("?q=joe&valid=true&id=1"))
+	assert.NoError(t, q.Parse())
+	q.AddFilterRaw("deleted_at IS NULL")
+	assert.Equal(t, "id=1&q=joe&valid=true", q.ToQueryString())
+}
@@ -0,0 +1,108 @@
+package rqp
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Range is a value of RANGE filter which is parsed from interval notation:
+//
+//	price=[10,100] -> price >= 10 AND price <= 100
+//	price=(10,100) -> price > 10 AND price < 100
+//	price=[10,100) -> price >= 10 AND price < 100
+type Range struct {
+	From        interface{}
+	To          interface{}
+	ExcludeFrom bool
+	ExcludeTo   bool
+}
+
+// format returns range in interval notation with delimiter between bounds
+func (r Range) format(delimiter string) string {
+	open, close := "[", "]"
+	if r.ExcludeFrom {
+		open = "("
+	}
+	if r.ExcludeTo {
+		close = ")"
+	}
+	return open + formatValue(r.From, delimiter) + delimiter + formatValue(r.To, delimiter) + close
+}
+
+// isRangeValue returns true if value looks like interval notation
+func isRangeValue(value string) bool {
+	return strings.HasPrefix(value, "[") || strings.HasPrefix(value, "(")
+}
+
+// setRange parses value in interval notation with bounds separated by delimiter of IN
+func (f *Filter) setRange(q *Query, valueType, value string) error {
+	if len(value) < 2 || !strings.HasSuffix(value, "]") && !strings.HasSuffix(value, ")") {
+		return ErrBadFormat
+	}
+
+	bounds := strings.Split(value[1:len(value)-1], q.delimiterIN)
+	if len(bounds) != 2 {
+		return ErrBadFormat
+	}
+
+	r := Range{
+		ExcludeFrom: value[0] == '(',
+		ExcludeTo:   value[len(value)-1] == ')',
+	}
+
+	switch valueType {
+	case "int":
+		from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return ErrBadFormat
+		}
+		to, err := strconv.Atoi(strings.TrimSpace(bounds[1]))
+		if err != nil {
+			return ErrBadFormat
+		}
+		if from > to {
+			return ErrNotInScope
+		}
+		r.From, r.To = from, to
+	case "float":
+		g := &Filter{Method: BETWEEN}
+		if err := g.setFloat([]string{strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])}); err != nil {
+			return err
+		}
+		list := g.Value.([]float64)
+		r.From, r.To = list[0], list[1]
+	case "decimal":
+		g := &Filter{Method: BETWEEN}
+		if err := g.setDecimal([]string{strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])}); err != nil {
+			return err
+		}
+		list := g.Value.([]string)
+		r.From, r.To = list[0], list[1]
+	case "time", "date":
+		g := &Filter{Method: BETWEEN}
+		if err := g.setTime(q, valueType, []string{strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])}); err != nil {
+			return err
+		}
+		list := g.Value.([]time.Time)
+		r.From, r.To = list[0], list[1]
+	default:
+		return ErrMethodNotAllowed
+	}
+
+	f.Method = RANGE
+	f.Value = r
+	return nil
+}
+
+// methods returns methods for bounds of range
+func (r Range) methods() (from, to Method) {
+	from, to = GTE, LTE
+	if r.ExcludeFrom {
+		from = GT
+	}
+	if r.ExcludeTo {
+		to = LT
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange(t *testing.T) {
+	validations := Validations{
+		"price:int": Max(1000),
+		"name":      nil,
+	}
+
+	cases := []struct {
+		url   string
+		where string
+		args  []interface{}
+		err   error
+	}{
+		{url: "?price=[10,100]", where: "(price >= ? AND price <= ?)", args: []interface{}{10, 100}},
+		{url: "?price=(10,100)", where: "(price > ? AND price < ?)", args: []interface{}{10, 100}},
+		{url: "?price=[10,100)", where: "(price >= ? AND price < ?)", args: []interface{}{10, 100}},
+		{url: "?price=(10, 100]", where: "(price > ? AND price <= ?)", args: []interface{}{10, 100}},
+		{url: "?price=[10,100]&name=tim", where: "name = ? AND (price >= ? AND price <= ?)", args: []interface{}{"tim", 10, 100}},
+		{url: "?name=[10]", where: "name = ?", args: []interface{}{"[10]"}},
+		{url: "?price=[10,100", err: ErrBadFormat},
+		{url: "?price=[10]", err: ErrBadFormat},
+		{url: "?price=[10,20,30]", err: ErrBadFormat},
+		{url: "?price=[a,100]", err: ErrBadFormat},
+		{url: "?price=[10,2000]", err: ErrNotInScope},
+		{url: "?price=[100,10]", err: ErrNotInScope},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if c.err != nil {
+				assert.Equal(t, c.err, errors.Cause(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?price=(10,100]"))
+	assert.NoError(t, q.Parse())
+	f, err := q.GetFilter("price")
+	assert.NoError(t, err)
+	assert.Equal(t, RANGE, f.Method)
+	assert.Equal(t, Range{From: 10, To: 100, ExcludeFrom: true}, f.Value)
+
+	// range could be printed back and matched in Go
+	assert.Equal(t, "price=%2810%2C100%5D", q.ToQueryString())
+	assert.True(t, q.Match(struct{ Price int }{100}))
+	assert.False(t, q.Match(struct{ Price int }{10}))
+
+	// method can't be used directly
+	assert.NoError(t, q.SetUrlString("?price[range]=[10,100]"))
+	assert.Equal(t, ErrUnknownMethod, errors.Cause(q.Parse()))
+}
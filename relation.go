@@ -0,0 +1,153 @@
+package rqp
+
+import (
+	"sort"
+	"strings"
+)
+
+// Relation is a table joined to the main one for filters, sorts and fields of dot notation:
+// `author.name[like]=*smith*` where "author" is a name of relation.
+type Relation struct {
+	Table      string // joined table, eg. "users"
+	ForeignKey string // column of the main table, eg. "author_id"
+	References string // column of the joined table, "id" by default
+	Join       string // type of JOIN, eg. "INNER", "LEFT" by default
+}
+
+// SetRelations sets relations by their names. Joined tables are aliased by names of relations,
+// so conditions of filters are qualified by them: `author.name LIKE ?`.
+// Filters must be declared in validations as usual: `"author.name": nil`.
+// Example:
+//
+//	q.SetRelations(map[string]rqp.Relation{
+//		"author": {Table: "users", ForeignKey: "author_id"},
+//	})
+func (q *Query) SetRelations(relations map[string]Relation) *Query {
+	q.invalidate()
+	q.relations = relations
+	return q
+}
+
+// relationNames returns sorted names of relations used by filters, sorts, fields and group
+func (q *Query) relationNames() []string {
+	if len(q.relations) == 0 {
+		return nil
+	}
+
+	used := make(map[string]bool)
+	add := func(name string) {
+		if i := strings.IndexByte(name, '.'); i > 0 {
+			if _, ok := q.relations[name[:i]]; ok {
+				used[name[:i]] = true
+			}
+		}
+	}
+
+	for _, f := range q.Filters {
+		if !f.Disabled {
+			add(f.Name)
+		}
+	}
+	for _, f := range q.forcedFilters() {
+		add(f.Name)
+	}
+	for _, f := range q.Havings {
+		if !f.Disabled {
+			add(f.Name)
+		}
+	}
+	for _, s := range q.Sorts {
+		add(s.By)
+	}
+	for _, field := range q.Fields {
+		add(field)
+	}
+	for _, field := range q.Group {
+		add(field)
+	}
+
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Join returns JOIN clauses of relations used by the Query, in order of names of relations
+//
+// Return example: `LEFT JOIN users AS author ON author.id = posts.author_id`
+func (q *Query) Join(table string) string {
+	names := q.relationNames()
+
+	var b strings.Builder
+	for i, name := range names {
+		r, _ := q.Relation(name)
+
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(r.Join)
+		b.WriteString(" JOIN ")
+		b.WriteString(q.identifier(r.Table))
+		b.WriteString(" AS ")
+		b.WriteString(q.identifier(name))
+		b.WriteString(" ON ")
+		b.WriteString(q.identifier(name + "." + r.References))
+		b.WriteString(" = ")
+		b.WriteString(q.identifier(tableQualifier(table) + "." + r.ForeignKey))
+	}
+
+	return b.String()
+}
+
+// JOIN returns JOIN clauses of relations used by the Query with leading space
+//
+// Return example: ` LEFT JOIN users AS author ON author.id = posts.author_id`
+func (q *Query) JOIN(table string) string {
+	join := q.Join(table)
+	if len(join) == 0 {
+		return ""
+	}
+
+	return " " + join
+}
+
+// identifier returns name of table or column quoted if identifiers are quoted
+func (q *Query) identifier(name string) string {
+	if q.quoteNames {
+		return q.quote(name)
+	}
+	return name
+}
+
+// Qualified returns copy of the Query which qualifies names of columns of the main table
+// without table by name or alias of the table: `id = ?` -> `posts.id = ?`, `*` -> `posts.*`.
+// SQL, CountSQL and facets use it if relations are joined, so names of columns aren't ambiguous.
+func (q *Query) Qualified(table string) *Query {
+	qNew := q.Clone()
+	qNew.qualifier = tableQualifier(table)
+	return qNew
+}
+
+// joined returns true if relations are joined and names aren't qualified yet
+func (q *Query) joined() bool {
+	return q.qualifier == "" && len(q.relationNames()) > 0
+}
+
+// qualify returns name qualified by q.qualifier if it's a name of column without table
+func (q *Query) qualify(name string) string {
+	if q.qualifier == "" || !isIdentifier(name) || strings.Contains(name, ".") {
+		return name
+	}
+	return q.qualifier + "." + name
+}
+
+// tableQualifier returns alias or name of table: `posts AS p` -> `p`, `posts` -> `posts`
+func tableQualifier(table string) string {
+	words := strings.Fields(table)
+	if len(words) == 0 {
+		return table
+	}
+	return words[len(words)-1]
+}
@@ -0,0 +1,75 @@
+package rqp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelations(t *testing.T) {
+	validations := Validations{
+		"title":         nil,
+		"author.name":   nil,
+		"category.slug": nil,
+		"users.id:int":  nil,
+		"sort":          In("author.name", "id"),
+	}
+	relations := map[string]Relation{
+		"author":   {Table: "users", ForeignKey: "author_id"},
+		"category": {Table: "categories", ForeignKey: "category_id", References: "cid", Join: "inner"},
+	}
+
+	cases := []struct {
+		url   string
+		sql   string
+		count string
+	}{
+		{
+			url:   "?author.name[like]=*smith*&title=go",
+			sql:   "SELECT posts.* FROM posts LEFT JOIN users AS author ON author.id = posts.author_id WHERE author.name LIKE ? AND posts.title = ?",
+			count: "SELECT COUNT(*) FROM posts LEFT JOIN users AS author ON author.id = posts.author_id WHERE author.name LIKE ? AND posts.title = ?",
+		},
+		{
+			url: "?category.slug=go&sort=-author.name",
+			sql: "SELECT posts.* FROM posts LEFT JOIN users AS author ON author.id = posts.author_id" +
+				" INNER JOIN categories AS category ON category.cid = posts.category_id WHERE category.slug = ? ORDER BY author.name DESC",
+			count: "SELECT COUNT(*) FROM posts INNER JOIN categories AS category ON category.cid = posts.category_id WHERE category.slug = ?",
+		},
+		{
+			// dotted names without relation aren't joined
+			url:   "?users.id=1&title=go",
+			sql:   "SELECT * FROM posts WHERE title = ? AND users.id = ?",
+			count: "SELECT COUNT(*) FROM posts WHERE title = ? AND users.id = ?",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			URL, _ := url.Parse(c.url)
+			q := NewQV(URL.Query(), validations).SetRelations(relations)
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.sql, q.SQL("posts"))
+			// without sorts only relations of filters are joined
+			q.Sorts = nil
+			assert.Equal(t, c.count, q.CountSQL("posts"))
+		})
+	}
+
+	// relations of forced filters are joined, alias of the main table qualifies its columns
+	q := NewQV(url.Values{"title": {"go"}, "fields": {"id"}}, Validations{"title": nil, "fields": In("id")}).
+		SetRelations(relations).
+		AddForcedFilter("author.tenant_id", EQ, 7)
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "SELECT p.id FROM posts AS p LEFT JOIN users AS author ON author.id = p.author_id WHERE p.title = ? AND author.tenant_id = ?", q.SQL("posts AS p"))
+	sql, args := q.SQLWithArgs("posts")
+	assert.Equal(t, "SELECT posts.id FROM posts LEFT JOIN users AS author ON author.id = posts.author_id WHERE posts.title = ? AND author.tenant_id = ?", sql)
+	assert.Equal(t, []interface{}{"go", 7}, args)
+	assert.Equal(t, "title = ? AND author.tenant_id = ?", q.Where())
+	assert.Equal(t, "posts.title = ? AND author.tenant_id = ?", q.Qualified("posts").Where())
+
+	q = NewQV(url.Values{"author.name": {"tim"}}, validations).SetRelations(relations).SetQuoteIdentifiers(true)
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, ` LEFT JOIN "users" AS "author" ON "author"."id" = "posts"."author_id"`, q.JOIN("posts"))
+	assert.Equal(t, q.JOIN("posts"), q.Clone().JOIN("posts"))
+	assert.Equal(t, "", New().JOIN("posts"))
+}
@@ -0,0 +1,57 @@
+package rqp
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// NewParseRequest creates new Query instance and parses query of request r, see ParseRequest
+func NewParseRequest(r *http.Request, v Validations) (*Query, error) {
+	query := New().SetValidations(v)
+	return query, query.ParseRequest(r)
+}
+
+// ParseURL parses query part of URL u
+func (q *Query) ParseURL(u *url.URL) error {
+	if u == nil {
+		return q.SetUrlQuery(nil).Parse()
+	}
+	return q.SetUrlQuery(u.Query()).Parse()
+}
+
+// SetFormValues sets reading of the form of body of POST, PUT and PATCH requests
+// (application/x-www-form-urlencoded) by ParseRequest. Values of the form are added
+// to values of query part of URL with the same keys.
+func (q *Query) SetFormValues(read bool) *Query {
+	q.formValues = read
+	return q
+}
+
+// ParseRequest parses query part of URL of request r and the form of body if it's enabled by SetFormValues
+func (q *Query) ParseRequest(r *http.Request) error {
+	if !q.formValues || !hasForm(r) {
+		return q.ParseURL(r.URL)
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	// r.Form contains values of the body first and values of query part of URL after them
+	values := make(url.Values, len(r.Form))
+	for key, list := range r.Form {
+		values[key] = append([]string(nil), list...)
+	}
+	return q.SetUrlQuery(values).Parse()
+}
+
+// hasForm returns true if body of request r could contain url-encoded form
+func hasForm(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		return err == nil && ct == "application/x-www-form-urlencoded"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package rqp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseURL(t *testing.T) {
+	u, err := url.Parse("/users?id=1&sort=-id")
+	assert.NoError(t, err)
+
+	q := NewQV(nil, Validations{"id:int": nil, "sort": In("id")})
+	assert.NoError(t, q.ParseURL(u))
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? ORDER BY id DESC", q.SQL("users"))
+
+	q = NewQV(nil, Validations{"id:int": nil})
+	assert.NoError(t, q.ParseURL(nil))
+	assert.Equal(t, "SELECT * FROM users", q.SQL("users"))
+}
+
+func TestNewParseRequest(t *testing.T) {
+	validations := Validations{"id:int": nil, "name": nil}
+
+	r := httptest.NewRequest(http.MethodGet, "/users?id=1&name=tim", nil)
+	q, err := NewParseRequest(r, validations)
+	assert.NoError(t, err)
+	assert.Equal(t, "id = ? AND name = ?", q.Where())
+	assert.Equal(t, []interface{}{1, "tim"}, q.Args())
+
+	r = httptest.NewRequest(http.MethodGet, "/users?id=a", nil)
+	_, err = NewParseRequest(r, validations)
+	assert.EqualError(t, err, "id: bad format")
+}
+
+func TestSetFormValues(t *testing.T) {
+	validations := Validations{"id:int": nil, "name": nil}
+	newRequest := func(method, contentType string) *http.Request {
+		r := httptest.NewRequest(method, "/users?id=1", strings.NewReader("name=tim"))
+		r.Header.Set("Content-Type", contentType)
+		return r
+	}
+
+	// form isn't read by default
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.ParseRequest(newRequest(http.MethodPost, "application/x-www-form-urlencoded")))
+	assert.Equal(t, "id = ?", q.Where())
+
+	q.SetFormValues(true)
+	assert.NoError(t, q.ParseRequest(newRequest(http.MethodPost, "application/x-www-form-urlencoded; charset=utf-8")))
+	assert.Equal(t, "id = ? AND name = ?", q.Where())
+	assert.Equal(t, []interface{}{1, "tim"}, q.Args())
+
+	// other content types and methods use query part of URL only
+	assert.NoError(t, q.ParseRequest(newRequest(http.MethodPost, "application/json")))
+	assert.Equal(t, "id = ?", q.Where())
+	assert.NoError(t, q.ParseRequest(newRequest(http.MethodGet, "application/x-www-form-urlencoded")))
+	assert.Equal(t, "id = ?", q.Where())
+}
@@ -0,0 +1,24 @@
+module github.com/timsolov/rest-query-parser/rqpboil
+
+go 1.20
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.8.3
+	github.com/timsolov/rest-query-parser v0.0.0
+	github.com/volatiletech/sqlboiler/v4 v4.15.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/friendsofgo/errors v0.9.2 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/spf13/cast v1.5.0 // indirect
+	github.com/volatiletech/inflect v0.0.1 // indirect
+	github.com/volatiletech/strmangle v0.0.5 // indirect
+	golang.org/x/xerrors v0.0.0-20220609144429-65e65417b02f // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/timsolov/rest-query-parser => ../
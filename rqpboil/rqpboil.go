@@ -0,0 +1,101 @@
+// Package rqpboil converts parsed rest-query-parser Query to query mods of sqlboiler,
+// so client queries could be passed straight into finders of models.
+package rqpboil
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	rqp "github.com/timsolov/rest-query-parser"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// QueryMods returns qm.Select or qm.Distinct, joins of relations, qm.Where, qm.GroupBy, qm.Having,
+// qm.OrderBy, qm.Limit and qm.Offset of the Query.
+// Filters are rendered with `?` placeholders whatever placeholder of q is,
+// sqlboiler replaces them by placeholders of its driver. Example:
+//
+//	mods, err := rqpboil.QueryMods(q)
+//	users, err := models.Users(mods...).All(ctx, db)
+//
+// Query mods don't know the table of the model, so relations are joined only to the qualified Query:
+// `rqpboil.QueryMods(q.Qualified(models.TableNames.Posts))`, otherwise it's rqp.ErrNotQualified.
+func QueryMods(q *rqp.Query) ([]qm.QueryMod, error) {
+	mods, err := where(q)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case q.Distinct:
+		mods = append(mods, qm.Distinct(q.Select()))
+	case len(q.Fields) > 0 || len(q.Group) > 0 || q.Qualifier() != "":
+		mods = append(mods, qm.Select(q.Select()))
+	}
+	if group := q.GroupBy(); group != "" {
+		mods = append(mods, qm.GroupBy(group))
+	}
+	if having, args := q.HavingQuestion(); having != "" {
+		mods = append(mods, qm.Having(having, args...))
+	}
+	if order := q.Order(); order != "" {
+		mods = append(mods, qm.OrderBy(order))
+	}
+	if q.Limit > 0 {
+		mods = append(mods, qm.Limit(q.Limit))
+	}
+	if q.Offset > 0 {
+		mods = append(mods, qm.Offset(q.Offset))
+	}
+	return mods, nil
+}
+
+// Where returns only query mods of relations and filters of the Query, eg. to count rows of all pages:
+//
+//	mods, err := rqpboil.Where(q)
+//	total, err := models.Users(mods...).Count(ctx, db)
+func Where(q *rqp.Query) ([]qm.QueryMod, error) {
+	return where(q)
+}
+
+// where returns query mods of relations and filters of q, they are empty without filters
+func where(q *rqp.Query) ([]qm.QueryMod, error) {
+	mods, err := joins(q)
+	if err != nil {
+		return nil, err
+	}
+	if w, args := q.WhereQuestion(); w != "" {
+		mods = append(mods, qm.Where(w, args...))
+	}
+	return mods, nil
+}
+
+// joins returns query mods of relations used by q
+func joins(q *rqp.Query) ([]qm.QueryMod, error) {
+	names := q.Relations()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if q.Qualifier() == "" {
+		return nil, rqp.ErrNotQualified
+	}
+
+	mods := make([]qm.QueryMod, 0, len(names)+4)
+	for _, name := range names {
+		r, _ := q.Relation(name)
+		clause := r.Table + " AS " + name + " ON " + name + "." + r.References + " = " + q.Qualifier() + "." + r.ForeignKey
+		switch strings.TrimSuffix(r.Join, " OUTER") {
+		case "INNER":
+			mods = append(mods, qm.InnerJoin(clause))
+		case "LEFT":
+			mods = append(mods, qm.LeftOuterJoin(clause))
+		case "RIGHT":
+			mods = append(mods, qm.RightOuterJoin(clause))
+		case "FULL":
+			mods = append(mods, qm.FullOuterJoin(clause))
+		default:
+			return nil, errors.Wrapf(rqp.ErrMethodNotAllowed, "%s JOIN of %s", r.Join, name)
+		}
+	}
+	return mods, nil
+}
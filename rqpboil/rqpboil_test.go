@@ -0,0 +1,95 @@
+package rqpboil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+	"github.com/volatiletech/sqlboiler/v4/drivers"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// build returns statement and args of query mods from table users of Postgres
+func build(mods []qm.QueryMod) (string, []interface{}) {
+	return buildFrom(`"users"`, mods)
+}
+
+// buildFrom returns statement and args of query mods from the table of Postgres
+func buildFrom(table string, mods []qm.QueryMod) (string, []interface{}) {
+	q := &queries.Query{}
+	queries.SetDialect(q, &drivers.Dialect{LQ: '"', RQ: '"', UseIndexPlaceholders: true})
+	queries.SetFrom(q, table)
+	qm.Apply(q, mods...)
+	return queries.BuildQuery(q)
+}
+
+func TestQueryMods(t *testing.T) {
+	q := rqp.NewQV(nil, rqp.Validations{
+		"fields": rqp.In("id", "name"),
+		"sort":   rqp.In("id", "name"),
+		"id:int": nil,
+		"name":   nil,
+	}).SetPlaceholder(rqp.PlaceholderDollar)
+	assert.NoError(t, q.SetUrlString("?id[in]=1,2&name=tim|name=bob&fields=id,name&sort=-name,id&limit=10&offset=20"))
+	assert.NoError(t, q.Parse())
+
+	mods, err := QueryMods(q)
+	assert.NoError(t, err)
+	sql, args := build(mods)
+	assert.Equal(t, `SELECT id, name FROM "users" WHERE (id IN ($1, $2) AND (name = $3 OR name = $4)) ORDER BY name DESC, id LIMIT 10 OFFSET 20;`, sql)
+	assert.Equal(t, []interface{}{1, 2, "tim", "bob"}, args)
+
+	mods, err = Where(q)
+	assert.NoError(t, err)
+	sql, args = build(mods)
+	assert.Equal(t, `SELECT * FROM "users" WHERE (id IN ($1, $2) AND (name = $3 OR name = $4));`, sql)
+	assert.Len(t, args, 4)
+
+	mods, err = QueryMods(rqp.New())
+	assert.NoError(t, err)
+	sql, _ = build(mods)
+	assert.Equal(t, `SELECT * FROM "users";`, sql)
+}
+
+func TestQueryModsGroupAndRelations(t *testing.T) {
+	// group and having
+	q := rqp.NewQV(nil, rqp.Validations{"group": rqp.In("name"), "count(*):int": nil}).SetPlaceholder(rqp.PlaceholderDollar)
+	assert.NoError(t, q.SetUrlString("?group=name&having[count(*)][gte]=2"))
+	assert.NoError(t, q.Parse())
+
+	mods, err := QueryMods(q)
+	assert.NoError(t, err)
+	sql, args := build(mods)
+	assert.Equal(t, `SELECT "name" FROM "users" GROUP BY name HAVING count(*) >= $1;`, sql)
+	assert.Equal(t, []interface{}{2}, args)
+
+	// distinct
+	q = rqp.NewQV(nil, rqp.Validations{"fields": rqp.In("name"), "distinct": nil})
+	assert.NoError(t, q.SetUrlString("?fields=name&distinct=true"))
+	assert.NoError(t, q.Parse())
+
+	mods, err = QueryMods(q)
+	assert.NoError(t, err)
+	sql, _ = build(mods)
+	assert.Equal(t, `SELECT DISTINCT name FROM "users";`, sql)
+
+	// relations are joined to the qualified query
+	q = rqp.NewQV(nil, rqp.Validations{"author.name": nil, "id:int": nil}).SetRelations(map[string]rqp.Relation{
+		"author": {Table: "users", ForeignKey: "author_id", Join: "inner"},
+	})
+	assert.NoError(t, q.SetUrlString("?author.name=tim&id[gt]=1"))
+	assert.NoError(t, q.Parse())
+
+	mods, err = QueryMods(q.Qualified("posts"))
+	assert.NoError(t, err)
+	sql, args = buildFrom(`"posts"`, mods)
+	assert.Equal(t, `SELECT posts.* FROM "posts" INNER JOIN users AS author ON author.id = posts.author_id WHERE (author.name = $1 AND posts.id > $2);`, sql)
+	assert.Equal(t, []interface{}{"tim", 1}, args)
+
+	// table isn't known
+	_, err = QueryMods(q)
+	assert.Equal(t, rqp.ErrNotQualified, err)
+	_, err = Where(q)
+	assert.Equal(t, rqp.ErrNotQualified, err)
+}
@@ -0,0 +1,85 @@
+// Package rqpbun applies parsed rest-query-parser Query to Bun queries:
+// Distinct, Column, Join, Where, Group, Having, Order, Limit and Offset are added to bun.SelectQuery.
+package rqpbun
+
+import (
+	rqp "github.com/timsolov/rest-query-parser"
+	"github.com/uptrace/bun"
+)
+
+// Apply adds fields, distinct, relations, filters, group, having, sorts, limit and offset of the Query to sel.
+// Filters are rendered with `?` placeholders whatever placeholder of q is,
+// so Bun formats args by its dialect like args of own Where. Relations (see rqp.SetRelations)
+// are joined to the table of the model, columns of the model are qualified by its alias. Example:
+//
+//	var users []User
+//	err := rqpbun.Apply(db.NewSelect().Model(&users), q).Scan(ctx)
+func Apply(sel *bun.SelectQuery, q *rqp.Query) *bun.SelectQuery {
+	sel, q = qualified(sel, q)
+	if q.Distinct {
+		sel = sel.Distinct()
+	}
+	if len(q.Fields) > 0 || len(q.Group) > 0 || q.Qualifier() != "" {
+		sel = sel.ColumnExpr(q.Select())
+	}
+	sel = where(sel, q)
+	if group := q.GroupBy(); group != "" {
+		sel = sel.GroupExpr(group)
+	}
+	if having, args := q.HavingQuestion(); having != "" {
+		sel = sel.Having(having, args...)
+	}
+	if order := q.Order(); order != "" {
+		sel = sel.OrderExpr(order)
+	}
+	if q.Limit > 0 {
+		sel = sel.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		sel = sel.Offset(q.Offset)
+	}
+	return sel
+}
+
+// Where adds only relations and filters of the Query to sel, eg. to count rows of all pages:
+//
+//	total, err := rqpbun.Where(db.NewSelect().Model((*User)(nil)), q).Count(ctx)
+func Where(sel *bun.SelectQuery, q *rqp.Query) *bun.SelectQuery {
+	return where(qualified(sel, q))
+}
+
+// Scope returns function for bun.SelectQuery.Apply which applies the Query, see Apply:
+//
+//	err := db.NewSelect().Model(&users).Apply(rqpbun.Scope(q)).Scan(ctx)
+func Scope(q *rqp.Query) func(sel *bun.SelectQuery) *bun.SelectQuery {
+	return func(sel *bun.SelectQuery) *bun.SelectQuery {
+		return Apply(sel, q)
+	}
+}
+
+// qualified returns q qualified by alias of model of sel (or by its table) if relations are joined,
+// see rqp.Qualified. Error of sel is rqp.ErrNotQualified if the table isn't known.
+func qualified(sel *bun.SelectQuery, q *rqp.Query) (*bun.SelectQuery, *rqp.Query) {
+	if q.Qualifier() != "" || len(q.Relations()) == 0 {
+		return sel, q
+	}
+
+	if m, ok := sel.GetModel().(bun.TableModel); ok {
+		return sel, q.Qualified(m.Table().Alias)
+	}
+	if table := sel.GetTableName(); table != "" {
+		return sel, q.Qualified(table)
+	}
+	return sel.Err(rqp.ErrNotQualified), q
+}
+
+// where adds relations and filters of q to sel
+func where(sel *bun.SelectQuery, q *rqp.Query) *bun.SelectQuery {
+	if join := q.Join(q.Qualifier()); join != "" {
+		sel = sel.Join(join)
+	}
+	if w, args := q.WhereQuestion(); w != "" {
+		sel = sel.Where(w, args...)
+	}
+	return sel
+}
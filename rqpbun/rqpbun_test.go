@@ -0,0 +1,129 @@
+package rqpbun
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+)
+
+type user struct {
+	ID     int `bun:",pk"`
+	Name   string
+	Status string
+}
+
+// open returns in-memory database with users
+func open(t *testing.T) *bun.DB {
+	sqldb, err := sql.Open("sqlite", ":memory:")
+	assert.NoError(t, err)
+	sqldb.SetMaxOpenConns(1)
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+
+	ctx := context.Background()
+	_, err = db.NewCreateTable().Model((*user)(nil)).Exec(ctx)
+	assert.NoError(t, err)
+	_, err = db.NewInsert().Model(&[]user{
+		{ID: 1, Name: "tim", Status: "a"},
+		{ID: 2, Name: "tom", Status: "b"},
+		{ID: 3, Name: "bob", Status: "a"},
+		{ID: 4, Name: "tina", Status: "c"},
+	}).Exec(ctx)
+	assert.NoError(t, err)
+	return db
+}
+
+func TestApply(t *testing.T) {
+	db := open(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	q := rqp.NewQV(nil, rqp.Validations{
+		"fields": rqp.In("id", "name"),
+		"sort":   rqp.In("id", "name"),
+		"id:int": nil,
+		"name":   nil,
+		"status": nil,
+	}).SetPlaceholder(rqp.PlaceholderDollar)
+	assert.NoError(t, q.SetUrlString("?name[like]=t*&status[in]=a,c|id=2&fields=id,name&sort=-id&limit=2&offset=1"))
+	assert.NoError(t, q.Parse())
+
+	sel := Apply(db.NewSelect().Model((*user)(nil)), q)
+	assert.Equal(t, `SELECT id, name FROM "users" AS "user" WHERE (name LIKE 't%' AND (status IN ('a', 'c') OR id = 2)) ORDER BY id DESC LIMIT 2 OFFSET 1`, sel.String())
+
+	var users []user
+	assert.NoError(t, Apply(db.NewSelect().Model(&users), q).Scan(ctx))
+	assert.Equal(t, []user{{ID: 2, Name: "tom"}, {ID: 1, Name: "tim"}}, users)
+
+	total, err := Where(db.NewSelect().Model((*user)(nil)), q).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	users = nil
+	assert.NoError(t, db.NewSelect().Model(&users).Apply(Scope(rqp.New())).Order("id").Scan(ctx))
+	assert.Len(t, users, 4)
+}
+
+type post struct {
+	ID       int `bun:",pk"`
+	AuthorID int
+	Title    string
+}
+
+func TestApplyGroupAndRelations(t *testing.T) {
+	db := open(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.NewCreateTable().Model((*post)(nil)).Exec(ctx)
+	assert.NoError(t, err)
+	_, err = db.NewInsert().Model(&[]post{
+		{ID: 1, AuthorID: 1, Title: "a"},
+		{ID: 2, AuthorID: 1, Title: "b"},
+		{ID: 3, AuthorID: 2, Title: "c"},
+	}).Exec(ctx)
+	assert.NoError(t, err)
+
+	// group and having
+	q := rqp.NewQV(nil, rqp.Validations{"group": rqp.In("author_id"), "count(*):int": nil})
+	assert.NoError(t, q.SetUrlString("?group=author_id&having[count(*)][gte]=2"))
+	assert.NoError(t, q.Parse())
+
+	sel := Apply(db.NewSelect().Model((*post)(nil)), q)
+	assert.Equal(t, `SELECT author_id FROM "posts" AS "post" GROUP BY author_id HAVING (count(*) >= 2)`, sel.String())
+	var ids []int
+	assert.NoError(t, sel.Scan(ctx, &ids))
+	assert.Equal(t, []int{1}, ids)
+
+	// distinct
+	q = rqp.NewQV(nil, rqp.Validations{"fields": rqp.In("author_id"), "distinct": nil})
+	assert.NoError(t, q.SetUrlString("?fields=author_id&distinct=true"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, `SELECT DISTINCT author_id FROM "posts" AS "post"`, Apply(db.NewSelect().Model((*post)(nil)), q).String())
+
+	// relations are joined to the table of the model
+	q = rqp.NewQV(nil, rqp.Validations{"author.name": nil, "id:int": nil}).SetRelations(map[string]rqp.Relation{
+		"author": {Table: "users", ForeignKey: "author_id", Join: "inner"},
+	})
+	assert.NoError(t, q.SetUrlString("?author.name=tim&id[gt]=1"))
+	assert.NoError(t, q.Parse())
+
+	sel = Apply(db.NewSelect().Model((*post)(nil)), q)
+	assert.Equal(t, `SELECT post.* FROM "posts" AS "post" INNER JOIN users AS author ON author.id = post.author_id WHERE (author.name = 'tim' AND post.id > 1)`, sel.String())
+
+	var posts []post
+	assert.NoError(t, Apply(db.NewSelect().Model(&posts), q).Scan(ctx))
+	assert.Equal(t, []post{{ID: 2, AuthorID: 1, Title: "b"}}, posts)
+
+	total, err := Where(db.NewSelect().Model((*post)(nil)), q).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	// table isn't known
+	assert.Equal(t, rqp.ErrNotQualified, Apply(db.NewSelect().ColumnExpr("1"), q).Scan(ctx))
+}
@@ -0,0 +1,82 @@
+// Package rqpecho integrates rest-query-parser with Echo: middleware parses query of requests
+// by Validations of the route and attaches the Query to echo.Context.
+package rqpecho
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// ContextKey is a key of the Query in echo.Context
+const ContextKey = "rqp.query"
+
+// ErrorResponse is message of 400 Bad Request error for parse errors
+type ErrorResponse struct {
+	Error string `json:"error"`         // full text of the error, eg. "id: bad format"
+	Key   string `json:"key,omitempty"` // key of query part of URL, eg. "id"
+	Cause string `json:"cause"`         // cause of the error, eg. "bad format"
+}
+
+// Middleware returns Echo middleware which parses query of every request by Validations v
+// and options set by configure functions (see rqp.NewFactory). Requests with bad query
+// get error of NewHTTPError, handlers of others get the Query by Query.
+// Example:
+//
+//	e.GET("/users", func(c echo.Context) error {
+//		q := rqpecho.MustQuery(c)
+//		rows, err := db.Query(q.SQL("users"), q.Args()...)
+//	}, rqpecho.Middleware(rqp.Validations{"id:int": nil}))
+func Middleware(v rqp.Validations, configure ...func(q *rqp.Query)) echo.MiddlewareFunc {
+	f := rqp.NewFactory(v, configure...)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			q := f.New()
+			if err := q.ParseRequest(c.Request()); err != nil {
+				return NewHTTPError(err)
+			}
+			c.Set(ContextKey, q)
+			return next(c)
+		}
+	}
+}
+
+// Bind parses query of request of c by Validations v and attaches the Query to c.
+// Error is returned as is, use NewHTTPError to return it from handler.
+func Bind(c echo.Context, v rqp.Validations) (*rqp.Query, error) {
+	q, err := rqp.NewParseRequest(c.Request(), v)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(ContextKey, q)
+	return q, nil
+}
+
+// Query returns the Query attached to c by Middleware or Bind
+func Query(c echo.Context) (*rqp.Query, bool) {
+	q, ok := c.Get(ContextKey).(*rqp.Query)
+	return q, ok
+}
+
+// MustQuery returns the Query attached to c by Middleware or Bind, it panics if there is no Query
+func MustQuery(c echo.Context) *rqp.Query {
+	q, ok := Query(c)
+	if !ok {
+		panic("rqpecho: query isn't attached to context, use Middleware or Bind")
+	}
+	return q
+}
+
+// NewHTTPError returns 400 Bad Request error with ErrorResponse of parse error err.
+// Default error handler of Echo renders it as JSON.
+func NewHTTPError(err error) *echo.HTTPError {
+	key, cause := rqp.ErrorDetails(err)
+	e := echo.NewHTTPError(http.StatusBadRequest, ErrorResponse{
+		Error: err.Error(),
+		Key:   key,
+		Cause: cause.Error(),
+	})
+	e.Internal = err
+	return e
+}
@@ -0,0 +1,58 @@
+package rqpecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.GET("/users", func(c echo.Context) error {
+		return c.String(http.StatusOK, MustQuery(c).Where())
+	}, Middleware(rqp.Validations{"id:int": nil}, func(q *rqp.Query) {
+		q.SetPlaceholder(rqp.PlaceholderDollar)
+	}))
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?id=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id = $1", w.Body.String())
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?id=a", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"id: bad format","key":"id","cause":"bad format"}`, w.Body.String())
+}
+
+func TestBind(t *testing.T) {
+	e := echo.New()
+	e.GET("/users", func(c echo.Context) error {
+		if _, err := Bind(c, rqp.Validations{"id:int": nil}); err != nil {
+			return NewHTTPError(err)
+		}
+		q, ok := Query(c)
+		assert.True(t, ok)
+		return c.String(http.StatusOK, q.Where())
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?id=1", nil))
+	assert.Equal(t, "id = ?", w.Body.String())
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?name=tim", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"name: filter not found","key":"name","cause":"filter not found"}`, w.Body.String())
+}
+
+func TestQueryNotAttached(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	_, ok := Query(c)
+	assert.False(t, ok)
+	assert.Panics(t, func() { MustQuery(c) })
+}
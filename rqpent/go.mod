@@ -0,0 +1,28 @@
+module github.com/timsolov/rest-query-parser/rqpent
+
+go 1.20
+
+require (
+	entgo.io/ent v0.12.5
+	github.com/glebarez/go-sqlite v1.21.2
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.8.3
+	github.com/timsolov/rest-query-parser v0.0.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/mattn/go-isatty v0.0.17 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	modernc.org/libc v1.22.5 // indirect
+	modernc.org/mathutil v1.5.0 // indirect
+	modernc.org/memory v1.5.0 // indirect
+	modernc.org/sqlite v1.23.1 // indirect
+)
+
+replace github.com/timsolov/rest-query-parser => ../
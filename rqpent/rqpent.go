@@ -0,0 +1,304 @@
+// Package rqpent converts parsed rest-query-parser Query to predicates and ordering options
+// of ent, so ent-based services could accept the same query part of URL:
+//
+//	where, err := rqpent.Predicate(q)
+//	if err != nil { ... }
+//	users, err := client.User.Query().
+//		Where(predicate.User(where)).
+//		Order(rqpent.Order(q)...).
+//		Limit(q.Limit).Offset(q.Offset).
+//		All(ctx)
+package rqpent
+
+import (
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/pkg/errors"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// Predicate returns predicate of filters of the Query. Predicates are built by ent,
+// so identifiers and placeholders are of dialect of the selector. OR statements are OR,
+// top level filters are joined like in rqp.SetTopLevelJoin, forced filters are joined by AND.
+// Raw filters and cursor are ErrMethodNotAllowed (see rqp.Groups), regex, fts, contains
+// and custom methods are dialect-specific so they are ErrMethodNotAllowed too.
+func Predicate(q *rqp.Query) (func(*sql.Selector), error) {
+	filters, err := q.Groups()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*sql.Predicate
+	for _, group := range filters {
+		or := make([]*sql.Predicate, len(group))
+		for i, f := range group {
+			p, err := predicate(q, f)
+			if err != nil {
+				return nil, err
+			}
+			or[i] = p
+		}
+		if len(or) == 1 {
+			groups = append(groups, or[0])
+		} else {
+			groups = append(groups, sql.Or(or...))
+		}
+	}
+
+	var and []*sql.Predicate
+	switch {
+	case q.TopLevelJoin() == "OR" && len(groups) > 1:
+		and = append(and, sql.Or(groups...))
+	default:
+		and = append(and, groups...)
+	}
+	for _, f := range q.ForcedFilters() {
+		p, err := predicate(q, f)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, p)
+	}
+
+	return func(s *sql.Selector) {
+		switch len(and) {
+		case 0:
+		case 1:
+			s.Where(and[0])
+		default:
+			s.Where(sql.And(and...))
+		}
+	}, nil
+}
+
+// Order returns ordering options of Sorts of the Query
+func Order(q *rqp.Query) []func(*sql.Selector) {
+	options := make([]func(*sql.Selector), len(q.Sorts))
+	for i, s := range q.Sorts {
+		column, desc := q.Column(s.By), s.Desc
+		options[i] = func(s *sql.Selector) {
+			if desc {
+				s.OrderBy(sql.Desc(quote(&s.Builder, column)))
+			} else {
+				s.OrderBy(sql.Asc(quote(&s.Builder, column)))
+			}
+		}
+	}
+	return options
+}
+
+// Apply adds fields, distinct, relations, filters, group, having, sorts, limit and offset of the Query
+// to selector s, eg. in modifier of query builder: `client.User.Query().Modify(func(s *sql.Selector) { ... })`.
+// Columns aren't qualified by table, names of rqp.SetNameMapping could be qualified: "name": "users.name".
+// Relations are joined to the table of s (or to the qualifier of q.Qualified) and then columns are qualified.
+func Apply(s *sql.Selector, q *rqp.Query) error {
+	q, err := qualified(s, q)
+	if err != nil {
+		return err
+	}
+	where, err := Predicate(q)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(q.Fields) > 0:
+		s.Select(columns(&s.Builder, q, q.Fields)...)
+	case len(q.Group) > 0:
+		s.Select(columns(&s.Builder, q, q.Group)...)
+	case q.Qualifier() != "" && len(s.SelectedColumns()) == 0:
+		s.Select(quote(&s.Builder, q.Qualifier()) + ".*")
+	}
+	if q.Distinct {
+		s.Distinct()
+	}
+	if err := join(s, q); err != nil {
+		return err
+	}
+	where(s)
+	if len(q.Group) > 0 {
+		s.GroupBy(columns(&s.Builder, q, q.Group)...)
+	}
+	if having, args := q.HavingQuestion(); having != "" {
+		s.Having(raw(having, args))
+	}
+	for _, order := range Order(q) {
+		order(s)
+	}
+	if q.Limit > 0 {
+		s.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		s.Offset(q.Offset)
+	}
+	return nil
+}
+
+// qualified returns q qualified by the table of s if relations are used and q isn't qualified yet
+func qualified(s *sql.Selector, q *rqp.Query) (*rqp.Query, error) {
+	if q.Qualifier() != "" || len(q.Relations()) == 0 {
+		return q, nil
+	}
+	if s.Table() == nil || s.TableName() == "" {
+		return nil, rqp.ErrNotQualified
+	}
+	return q.Qualified(s.TableName()), nil
+}
+
+// join adds JOIN clauses of relations used by q to selector s
+func join(s *sql.Selector, q *rqp.Query) error {
+	for _, name := range q.Relations() {
+		r, _ := q.Relation(name)
+		t := sql.Table(r.Table).As(name)
+		switch strings.TrimSuffix(r.Join, " OUTER") {
+		case "INNER":
+			s.Join(t)
+		case "LEFT":
+			s.LeftJoin(t)
+		case "RIGHT":
+			s.RightJoin(t)
+		case "FULL":
+			s.FullJoin(t)
+		default:
+			return errors.Wrapf(rqp.ErrMethodNotAllowed, "%s JOIN of %s", r.Join, name)
+		}
+		references, foreignKey := name+"."+r.References, q.Qualifier()+"."+r.ForeignKey
+		s.OnP(sql.P(func(b *sql.Builder) {
+			ident(b, references).WriteString(" = ")
+			ident(b, foreignKey)
+		}))
+	}
+	return nil
+}
+
+// predicate returns predicate of single filter
+func predicate(q *rqp.Query, f *rqp.Filter) (*sql.Predicate, error) {
+	column := q.Column(f.Name)
+
+	args, err := q.FilterArgs(f)
+	if err != nil {
+		return nil, errors.Wrap(err, f.Name)
+	}
+
+	switch f.Method {
+	case rqp.EQ, rqp.NE, rqp.GT, rqp.LT, rqp.GTE, rqp.LTE:
+		return compare(column, operators[f.Method], args[0]), nil
+	case rqp.IS:
+		return sql.P(func(b *sql.Builder) { ident(b, column).WriteString(" IS NULL") }), nil
+	case rqp.NOT:
+		return sql.P(func(b *sql.Builder) { ident(b, column).WriteString(" IS NOT NULL") }), nil
+	case rqp.IN, rqp.NIN:
+		op := " IN ("
+		if f.Method == rqp.NIN {
+			op = " NOT IN ("
+		}
+		return sql.P(func(b *sql.Builder) { ident(b, column).WriteString(op).Args(args...).WriteByte(')') }), nil
+	case rqp.LIKE, rqp.STARTS, rqp.ENDS, rqp.CONTAINS_STR:
+		return like(column, args[0]), nil
+	case rqp.NLIKE:
+		return sql.Not(like(column, args[0])), nil
+	case rqp.ILIKE:
+		return ilike(column, args[0]), nil
+	case rqp.NILIKE:
+		return sql.Not(ilike(column, args[0])), nil
+	case rqp.BETWEEN:
+		return sql.And(compare(column, ">=", args[0]), compare(column, "<=", args[1])), nil
+	case rqp.RANGE:
+		r, _ := f.Value.(rqp.Range)
+		from, to := ">=", "<="
+		if r.ExcludeFrom {
+			from = ">"
+		}
+		if r.ExcludeTo {
+			to = "<"
+		}
+		return sql.And(compare(column, from, args[0]), compare(column, to, args[1])), nil
+	default:
+		return nil, errors.Wrap(rqp.ErrMethodNotAllowed, f.Name)
+	}
+}
+
+// operators are SQL operators of comparison methods
+var operators = map[rqp.Method]string{
+	rqp.EQ:  "=",
+	rqp.NE:  "<>",
+	rqp.GT:  ">",
+	rqp.LT:  "<",
+	rqp.GTE: ">=",
+	rqp.LTE: "<=",
+}
+
+// compare returns predicate `column op arg`
+func compare(column, op string, arg interface{}) *sql.Predicate {
+	return sql.P(func(b *sql.Builder) {
+		ident(b, column).WriteString(" " + op + " ").Arg(arg)
+	})
+}
+
+// like returns LIKE predicate
+func like(column string, pattern interface{}) *sql.Predicate {
+	return sql.P(func(b *sql.Builder) {
+		ident(b, column).WriteString(" LIKE ").Arg(pattern)
+		escape(b)
+	})
+}
+
+// ilike returns case-insensitive LIKE predicate: ILIKE of Postgres and LOWER of both sides of others
+func ilike(column string, pattern interface{}) *sql.Predicate {
+	return sql.P(func(b *sql.Builder) {
+		if b.Dialect() == dialect.Postgres {
+			ident(b, column).WriteString(" ILIKE ").Arg(pattern)
+			return
+		}
+		b.WriteString("LOWER(")
+		ident(b, column).WriteString(") LIKE LOWER(").Arg(pattern).WriteString(")")
+		escape(b)
+	})
+}
+
+// escape writes ESCAPE clause of LIKE for SQLite, it has no default escape character
+// of wildcards escaped in values of LIKE methods
+func escape(b *sql.Builder) {
+	if b.Dialect() == dialect.SQLite {
+		b.WriteString(` ESCAPE '\'`)
+	}
+}
+
+// raw returns predicate of expression with `?` placeholders, they are replaced by placeholders of dialect
+func raw(exp string, args []interface{}) *sql.Predicate {
+	return sql.P(func(b *sql.Builder) {
+		parts := strings.Split(exp, "?")
+		for i, part := range parts {
+			if i > 0 {
+				b.Arg(args[i-1])
+			}
+			b.WriteString(part)
+		}
+	})
+}
+
+// columns returns quoted columns of names
+func columns(b *sql.Builder, q *rqp.Query, names []string) []string {
+	columns := make([]string, len(names))
+	for i, name := range names {
+		columns[i] = quote(b, q.Column(name))
+	}
+	return columns
+}
+
+// ident writes identifier of column, parts of `table.column` are quoted separately
+func ident(b *sql.Builder, column string) *sql.Builder {
+	b.WriteString(quote(b, column))
+	return b
+}
+
+// quote returns identifier of column quoted by dialect of b
+func quote(b *sql.Builder, column string) string {
+	parts := strings.Split(column, ".")
+	for i := range parts {
+		parts[i] = b.Quote(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
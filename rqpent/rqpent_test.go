@@ -0,0 +1,197 @@
+package rqpent
+
+import (
+	"context"
+	stdsql "database/sql"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// parse returns parsed Query of raw query of URL
+func parse(t *testing.T, rawQuery string) *rqp.Query {
+	q := rqp.NewQV(nil, rqp.Validations{
+		"fields":    rqp.In("id", "name"),
+		"sort":      rqp.In("id", "name"),
+		"id:int":    nil,
+		"name":      nil,
+		"status":    nil,
+		"price:int": nil,
+	})
+	assert.NoError(t, q.SetUrlString(rawQuery))
+	assert.NoError(t, q.Parse())
+	return q
+}
+
+func TestPredicate(t *testing.T) {
+	cases := []struct {
+		url   string
+		query string
+		args  []interface{}
+	}{
+		{
+			url:   "?",
+			query: `SELECT * FROM "users"`,
+		},
+		{
+			url:   "?id[in]=1,2&name[like]=tim*|status[is]=null&price[between]=1,5",
+			query: `SELECT * FROM "users" WHERE "id" IN ($1, $2) AND ("name" LIKE $3 OR "status" IS NULL) AND ("price" >= $4 AND "price" <= $5)`,
+			args:  []interface{}{1, 2, "tim%", 1, 5},
+		},
+		{
+			url:   "?name[ilike]=*a&status[nin]=x&id[ne]=1&price=(1,5]",
+			query: `SELECT * FROM "users" WHERE "id" <> $1 AND "name" ILIKE $2 AND ("price" > $3 AND "price" <= $4) AND "status" NOT IN ($5)`,
+			args:  []interface{}{1, "%a", 1, 5, "x"},
+		},
+		{
+			url:   "?name[nlike]=a*&status[not]=null&id[gte]=1&price[lt]=2",
+			query: `SELECT * FROM "users" WHERE "id" >= $1 AND (NOT ("name" LIKE $2)) AND "price" < $3 AND "status" IS NOT NULL`,
+			args:  []interface{}{1, "a%", 2},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			where, err := Predicate(parse(t, c.url))
+			assert.NoError(t, err)
+			s := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("users"))
+			where(s)
+			query, args := s.Query()
+			assert.Equal(t, c.query, query)
+			assert.Equal(t, c.args, args)
+		})
+	}
+
+	// top level OR, forced filters and dialect of selector
+	q := parse(t, "?id=1&name[ilike]=a")
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	q.AddForcedFilter("tenant_id", rqp.EQ, 7)
+	where, err := Predicate(q)
+	assert.NoError(t, err)
+	s := sql.Dialect(dialect.MySQL).Select("*").From(sql.Table("users"))
+	where(s)
+	query, args := s.Query()
+	assert.Equal(t, "SELECT * FROM `users` WHERE (`id` = ? OR LOWER(`name`) LIKE LOWER(?)) AND `tenant_id` = ?", query)
+	assert.Equal(t, []interface{}{1, "a", 7}, args)
+
+	// wildcards of values are escaped by backslash, SQLite has no default escape character
+	where, err = Predicate(parse(t, "?name[like]=a_*&status[nilike]=b"))
+	assert.NoError(t, err)
+	s = sql.Dialect(dialect.SQLite).Select("*").From(sql.Table("users"))
+	where(s)
+	query, args = s.Query()
+	assert.Equal(t, "SELECT * FROM `users` WHERE `name` LIKE ? ESCAPE '\\' AND (NOT (LOWER(`status`) LIKE LOWER(?) ESCAPE '\\'))", query)
+	assert.Equal(t, []interface{}{`a\_%`, "b"}, args)
+
+	q = rqp.NewQV(nil, rqp.Validations{"name:regex": nil})
+	assert.NoError(t, q.SetUrlString("?name[regex]=a"))
+	assert.NoError(t, q.Parse())
+	_, err = Predicate(q)
+	assert.EqualError(t, err, "name: method are not allowed")
+
+	// raw filters aren't skipped
+	_, err = Predicate(rqp.New().AddFilterRaw("1 = 1"))
+	assert.EqualError(t, err, "1 = 1: method are not allowed")
+}
+
+func TestApply(t *testing.T) {
+	q := parse(t, "?id[gt]=1&name=tim&fields=id,name&sort=-name,id&limit=10&offset=20")
+	q.SetNameMapping(rqp.Replacer{"name": "users.name"})
+
+	s := sql.Dialect(dialect.Postgres).Select().From(sql.Table("users"))
+	assert.NoError(t, Apply(s, q))
+	query, args := s.Query()
+	assert.Equal(t, `SELECT "id", "users"."name" FROM "users" WHERE "id" > $1 AND "users"."name" = $2 ORDER BY "users"."name" DESC, "id" ASC LIMIT 10 OFFSET 20`, query)
+	assert.Equal(t, []interface{}{1, "tim"}, args)
+}
+
+func TestApplyGroupAndRelations(t *testing.T) {
+	// group and having
+	q := rqp.NewQV(nil, rqp.Validations{"group": rqp.In("author_id"), "count(*):int": nil})
+	assert.NoError(t, q.SetUrlString("?group=author_id&having[count(*)][gte]=2"))
+	assert.NoError(t, q.Parse())
+
+	s := sql.Dialect(dialect.Postgres).Select().From(sql.Table("posts"))
+	assert.NoError(t, Apply(s, q))
+	query, args := s.Query()
+	assert.Equal(t, `SELECT "author_id" FROM "posts" GROUP BY "author_id" HAVING count(*) >= $1`, query)
+	assert.Equal(t, []interface{}{2}, args)
+
+	// distinct
+	q = rqp.NewQV(nil, rqp.Validations{"fields": rqp.In("author_id"), "distinct": nil})
+	assert.NoError(t, q.SetUrlString("?fields=author_id&distinct=true"))
+	assert.NoError(t, q.Parse())
+
+	s = sql.Dialect(dialect.Postgres).Select().From(sql.Table("posts"))
+	assert.NoError(t, Apply(s, q))
+	query, _ = s.Query()
+	assert.Equal(t, `SELECT DISTINCT "author_id" FROM "posts"`, query)
+
+	// relations are joined to the table of the selector
+	q = rqp.NewQV(nil, rqp.Validations{"author.name": nil, "id:int": nil}).SetRelations(map[string]rqp.Relation{
+		"author": {Table: "users", ForeignKey: "author_id", Join: "inner"},
+	})
+	assert.NoError(t, q.SetUrlString("?author.name=tim&id[gt]=1"))
+	assert.NoError(t, q.Parse())
+
+	s = sql.Dialect(dialect.Postgres).Select().From(sql.Table("posts"))
+	assert.NoError(t, Apply(s, q))
+	query, args = s.Query()
+	assert.Equal(t, `SELECT "posts".* FROM "posts" JOIN "users" AS "author" ON "author"."id" = "posts"."author_id" WHERE "author"."name" = $1 AND "posts"."id" > $2`, query)
+	assert.Equal(t, []interface{}{"tim", 1}, args)
+
+	// table isn't known
+	assert.Equal(t, rqp.ErrNotQualified, Apply(sql.Dialect(dialect.Postgres).Select(), q))
+}
+
+func TestSQLite(t *testing.T) {
+	sqldb, err := stdsql.Open("sqlite", ":memory:")
+	if !assert.NoError(t, err) {
+		return
+	}
+	db := sql.OpenDB(dialect.SQLite, sqldb)
+	defer db.Close()
+
+	ctx := context.Background()
+	for _, stmt := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, author_id INTEGER, title TEXT)",
+		`INSERT INTO users (id, name) VALUES (1, 'a_b'), (2, 'axb')`,
+		`INSERT INTO posts (id, author_id, title) VALUES (1, 1, '100%'), (2, 1, '100'), (3, 2, '100%')`,
+	} {
+		assert.NoError(t, db.Exec(ctx, stmt, []interface{}{}, nil))
+	}
+
+	// ids returns ids of posts selected by the raw query of URL
+	ids := func(rawQuery string) []int {
+		q := rqp.NewQV(nil, rqp.Validations{"title": nil, "author.name": nil}).SetRelations(map[string]rqp.Relation{
+			"author": {Table: "users", ForeignKey: "author_id"},
+		})
+		assert.NoError(t, q.SetUrlString(rawQuery))
+		assert.NoError(t, q.Parse())
+
+		posts := sql.Dialect(dialect.SQLite).Table("posts")
+		s := sql.Dialect(dialect.SQLite).Select(posts.C("id")).From(posts)
+		assert.NoError(t, Apply(s, q))
+		query, args := s.Query()
+
+		var rows sql.Rows
+		assert.NoError(t, db.Query(ctx, query, args, &rows))
+		defer rows.Close()
+		var ids []int
+		for rows.Next() {
+			var id int
+			assert.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	// wildcards in values are matched literally
+	assert.Equal(t, []int{1, 3}, ids("?title[like]=*%25"))
+	assert.Equal(t, []int{1, 2}, ids("?author.name[ilike]=A_*"))
+	assert.Equal(t, []int{2}, ids("?title[nlike]=*%25"))
+}
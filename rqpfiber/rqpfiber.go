@@ -0,0 +1,87 @@
+// Package rqpfiber integrates rest-query-parser with Fiber: query args of fiber.Ctx
+// are parsed directly without conversion of the request to net/http.
+package rqpfiber
+
+import (
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// ContextKey is a key of the Query in locals of fiber.Ctx
+const ContextKey = "rqp.query"
+
+// ErrorResponse is JSON body of 400 Bad Request response for parse errors
+type ErrorResponse struct {
+	Error string `json:"error"`         // full text of the error, eg. "id: bad format"
+	Key   string `json:"key,omitempty"` // key of query part of URL, eg. "id"
+	Cause string `json:"cause"`         // cause of the error, eg. "bad format"
+}
+
+// Values returns query args of request of c as url.Values
+func Values(c *fiber.Ctx) url.Values {
+	values := make(url.Values)
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		k := string(key)
+		values[k] = append(values[k], string(value))
+	})
+	return values
+}
+
+// Parse parses query args of request of c by q
+func Parse(c *fiber.Ctx, q *rqp.Query) error {
+	return q.SetUrlQuery(Values(c)).Parse()
+}
+
+// NewParse creates new Query with Validations v and parses query args of request of c
+func NewParse(c *fiber.Ctx, v rqp.Validations) (*rqp.Query, error) {
+	q := rqp.New().SetValidations(v)
+	return q, Parse(c, q)
+}
+
+// Middleware returns Fiber handler which parses query args of every request by Validations v
+// and options set by configure functions (see rqp.NewFactory). Requests with bad query get
+// 400 Bad Request with ErrorResponse, next handlers get the Query by Query.
+// Example:
+//
+//	app.Get("/users", rqpfiber.Middleware(rqp.Validations{"id:int": nil}), func(c *fiber.Ctx) error {
+//		q := rqpfiber.MustQuery(c)
+//		rows, err := db.Query(q.SQL("users"), q.Args()...)
+//	})
+func Middleware(v rqp.Validations, configure ...func(q *rqp.Query)) fiber.Handler {
+	f := rqp.NewFactory(v, configure...)
+	return func(c *fiber.Ctx) error {
+		q := f.New()
+		if err := Parse(c, q); err != nil {
+			return SendError(c, err)
+		}
+		c.Locals(ContextKey, q)
+		return c.Next()
+	}
+}
+
+// Query returns the Query stored in locals of c by Middleware
+func Query(c *fiber.Ctx) (*rqp.Query, bool) {
+	q, ok := c.Locals(ContextKey).(*rqp.Query)
+	return q, ok
+}
+
+// MustQuery returns the Query stored in locals of c by Middleware, it panics if there is no Query
+func MustQuery(c *fiber.Ctx) *rqp.Query {
+	q, ok := Query(c)
+	if !ok {
+		panic("rqpfiber: query isn't stored in locals, use Middleware")
+	}
+	return q
+}
+
+// SendError responds 400 Bad Request with ErrorResponse of parse error err
+func SendError(c *fiber.Ctx, err error) error {
+	key, cause := rqp.ErrorDetails(err)
+	return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+		Error: err.Error(),
+		Key:   key,
+		Cause: cause.Error(),
+	})
+}
@@ -0,0 +1,61 @@
+package rqpfiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// get returns status and body of response of app for GET request of target
+func get(t *testing.T, app *fiber.App, target string) (int, string) {
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Get("/users", Middleware(rqp.Validations{"id:int": nil, "name": nil}, func(q *rqp.Query) {
+		q.SetPlaceholder(rqp.PlaceholderDollar)
+	}), func(c *fiber.Ctx) error {
+		q := MustQuery(c)
+		return c.SendString(q.Where())
+	})
+
+	code, body := get(t, app, "/users?id[in]=1,2&name=tim|name=bob")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "id IN ($1, $2) AND (name = $3 OR name = $4)", body)
+
+	code, body = get(t, app, "/users?id=a")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.JSONEq(t, `{"error":"id: bad format","key":"id","cause":"bad format"}`, body)
+}
+
+func TestNewParse(t *testing.T) {
+	app := fiber.New()
+	app.Get("/users", func(c *fiber.Ctx) error {
+		q, err := NewParse(c, rqp.Validations{"id:int": nil})
+		if err != nil {
+			return SendError(c, err)
+		}
+		_, ok := Query(c)
+		assert.False(t, ok)
+		return c.SendString(q.Where())
+	})
+
+	code, body := get(t, app, "/users?id=1&id=2")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "id = ? AND id = ?", body)
+
+	code, body = get(t, app, "/users?name=tim")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.JSONEq(t, `{"error":"name: filter not found","key":"name","cause":"filter not found"}`, body)
+}
@@ -0,0 +1,82 @@
+// Package rqpgin integrates rest-query-parser with Gin: middleware parses query of requests
+// by Validations of the route and attaches the Query to gin.Context.
+package rqpgin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// ContextKey is a key of the Query in gin.Context
+const ContextKey = "rqp.query"
+
+// ErrorResponse is JSON body of 400 Bad Request response for parse errors
+type ErrorResponse struct {
+	Error string `json:"error"`         // full text of the error, eg. "id: bad format"
+	Key   string `json:"key,omitempty"` // key of query part of URL, eg. "id"
+	Cause string `json:"cause"`         // cause of the error, eg. "bad format"
+}
+
+// Middleware returns Gin middleware which parses query of every request by Validations v
+// and options set by configure functions (see rqp.NewFactory). Requests with bad query
+// are aborted by AbortWithError, handlers of others get the Query by Query.
+// Example:
+//
+//	r.GET("/users", rqpgin.Middleware(rqp.Validations{"id:int": nil}), func(c *gin.Context) {
+//		q := rqpgin.MustQuery(c)
+//		rows, err := db.Query(q.SQL("users"), q.Args()...)
+//	})
+func Middleware(v rqp.Validations, configure ...func(q *rqp.Query)) gin.HandlerFunc {
+	f := rqp.NewFactory(v, configure...)
+	return func(c *gin.Context) {
+		q := f.New()
+		if err := q.ParseRequest(c.Request); err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		c.Set(ContextKey, q)
+		c.Next()
+	}
+}
+
+// Bind parses query of request of c by Validations v and attaches the Query to c.
+// Unlike Middleware it doesn't abort the request, the error is returned to handler.
+func Bind(c *gin.Context, v rqp.Validations) (*rqp.Query, error) {
+	q, err := rqp.NewParseRequest(c.Request, v)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(ContextKey, q)
+	return q, nil
+}
+
+// Query returns the Query attached to c by Middleware or Bind
+func Query(c *gin.Context) (*rqp.Query, bool) {
+	v, ok := c.Get(ContextKey)
+	if !ok {
+		return nil, false
+	}
+	q, ok := v.(*rqp.Query)
+	return q, ok
+}
+
+// MustQuery returns the Query attached to c by Middleware or Bind, it panics if there is no Query
+func MustQuery(c *gin.Context) *rqp.Query {
+	q, ok := Query(c)
+	if !ok {
+		panic("rqpgin: query isn't attached to context, use Middleware or Bind")
+	}
+	return q
+}
+
+// AbortWithError aborts request of c with 400 Bad Request and ErrorResponse of parse error err
+func AbortWithError(c *gin.Context, err error) {
+	key, cause := rqp.ErrorDetails(err)
+	c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{
+		Error: err.Error(),
+		Key:   key,
+		Cause: cause.Error(),
+	})
+}
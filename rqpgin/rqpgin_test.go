@@ -0,0 +1,63 @@
+package rqpgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestMiddleware(t *testing.T) {
+	r := gin.New()
+	r.GET("/users", Middleware(rqp.Validations{"id:int": nil}, func(q *rqp.Query) {
+		q.SetPlaceholder(rqp.PlaceholderDollar)
+	}), func(c *gin.Context) {
+		c.String(http.StatusOK, MustQuery(c).Where())
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?id=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id = $1", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?id=a", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"id: bad format","key":"id","cause":"bad format"}`, w.Body.String())
+}
+
+func TestBind(t *testing.T) {
+	r := gin.New()
+	r.GET("/users", func(c *gin.Context) {
+		if _, err := Bind(c, rqp.Validations{"id:int": nil}); err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		q, ok := Query(c)
+		assert.True(t, ok)
+		c.String(http.StatusOK, q.Where())
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?id=1", nil))
+	assert.Equal(t, "id = ?", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?name=tim", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"name: filter not found","key":"name","cause":"filter not found"}`, w.Body.String())
+}
+
+func TestQueryNotAttached(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	_, ok := Query(c)
+	assert.False(t, ok)
+	assert.Panics(t, func() { MustQuery(c) })
+}
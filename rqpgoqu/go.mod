@@ -0,0 +1,19 @@
+module github.com/timsolov/rest-query-parser/rqpgoqu
+
+go 1.20
+
+require (
+	github.com/doug-martin/goqu/v9 v9.19.0
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.8.3
+	github.com/timsolov/rest-query-parser v0.0.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/timsolov/rest-query-parser => ../
@@ -0,0 +1,258 @@
+// Package rqpgoqu converts parsed rest-query-parser Query to goqu expressions,
+// so statements are built by goqu with its dialects and could be composed with other datasets.
+package rqpgoqu
+
+import (
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/pkg/errors"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// Expression returns filters of the Query as goqu expression for Where. OR statements are goqu.Or,
+// top level filters are joined like in rqp.SetTopLevelJoin, forced filters are joined by AND.
+// Names of columns could be qualified by table: "users.name" (see rqp.SetNameMapping).
+// LIKE methods have ESCAPE clause if q.SetDialect is rqp.DialectSQLite or rqp.DialectMSSQL.
+// Raw filters and cursor (see rqp.Groups), fts, contains and custom methods are ErrMethodNotAllowed.
+func Expression(q *rqp.Query) (exp.ExpressionList, error) {
+	return expressions(q, q.Dialect() == rqp.DialectSQLite || q.Dialect() == rqp.DialectMSSQL)
+}
+
+// expressions returns filters of q as goqu expression, escape adds ESCAPE clause to LIKE methods
+func expressions(q *rqp.Query, escape bool) (exp.ExpressionList, error) {
+	filters, err := q.Groups()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]exp.Expression, 0, len(filters))
+	for _, group := range filters {
+		or := make([]exp.Expression, len(group))
+		for i, f := range group {
+			e, err := expression(q, f, escape)
+			if err != nil {
+				return nil, err
+			}
+			or[i] = e
+		}
+		if len(or) == 1 {
+			groups = append(groups, or[0])
+		} else {
+			groups = append(groups, goqu.Or(or...))
+		}
+	}
+
+	and := goqu.And()
+	if q.TopLevelJoin() == "OR" && len(groups) > 1 {
+		and = and.Append(goqu.Or(groups...))
+	} else {
+		and = and.Append(groups...)
+	}
+	for _, f := range q.ForcedFilters() {
+		e, err := expression(q, f, escape)
+		if err != nil {
+			return nil, err
+		}
+		and = and.Append(e)
+	}
+	return and, nil
+}
+
+// Order returns ordered expressions of Sorts of the Query
+func Order(q *rqp.Query) []exp.OrderedExpression {
+	order := make([]exp.OrderedExpression, len(q.Sorts))
+	for i, s := range q.Sorts {
+		if s.Desc {
+			order[i] = goqu.I(q.Column(s.By)).Desc()
+		} else {
+			order[i] = goqu.I(q.Column(s.By)).Asc()
+		}
+	}
+	return order
+}
+
+// Apply adds fields, distinct, relations, filters, group, having, sorts, limit and offset of the Query to ds.
+// Example:
+//
+//	ds, err := rqpgoqu.Apply(goqu.Dialect("postgres").From("users").Prepared(true), q)
+//	if err != nil { ... }
+//	sql, args, err := ds.ToSQL()
+//
+// Relations are joined to the table of ds (or to the qualifier of q.Qualified) and then columns are qualified.
+// LIKE methods have ESCAPE clause for dialects "sqlite3" and "sqlserver" of goqu.
+func Apply(ds *goqu.SelectDataset, q *rqp.Query) (*goqu.SelectDataset, error) {
+	q, err := qualified(ds, q)
+	if err != nil {
+		return nil, err
+	}
+	dialect := ds.Dialect().Dialect()
+	where, err := expressions(q, dialect == "sqlite3" || dialect == "sqlserver")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(q.Fields) > 0:
+		ds = ds.Select(columns(q, q.Fields)...)
+	case len(q.Group) > 0:
+		ds = ds.Select(columns(q, q.Group)...)
+	case q.Qualifier() != "" && ds.GetClauses().IsDefaultSelect():
+		ds = ds.Select(goqu.T(q.Qualifier()).All())
+	}
+	if q.Distinct {
+		ds = ds.Distinct()
+	}
+	if ds, err = join(ds, q); err != nil {
+		return nil, err
+	}
+	if !where.IsEmpty() {
+		ds = ds.Where(where)
+	}
+	if len(q.Group) > 0 {
+		ds = ds.GroupBy(columns(q, q.Group)...)
+	}
+	if having, args := q.HavingQuestion(); having != "" {
+		ds = ds.Having(goqu.L(having, args...))
+	}
+	if len(q.Sorts) > 0 {
+		ds = ds.Order(Order(q)...)
+	}
+	if q.Limit > 0 {
+		ds = ds.Limit(uint(q.Limit))
+	}
+	if q.Offset > 0 {
+		ds = ds.Offset(uint(q.Offset))
+	}
+	return ds, nil
+}
+
+// qualified returns q qualified by the table of ds if relations are used and q isn't qualified yet
+func qualified(ds *goqu.SelectDataset, q *rqp.Query) (*rqp.Query, error) {
+	if q.Qualifier() != "" || len(q.Relations()) == 0 {
+		return q, nil
+	}
+	if table := table(ds); table != "" {
+		return q.Qualified(table), nil
+	}
+	return nil, rqp.ErrNotQualified
+}
+
+// table returns alias or name of the first table of ds, it's empty if it isn't known
+func table(ds *goqu.SelectDataset) string {
+	from := ds.GetClauses().From()
+	if from == nil || from.IsEmpty() {
+		return ""
+	}
+	switch t := from.Columns()[0].(type) {
+	case exp.AliasedExpression:
+		return t.GetAs().GetTable()
+	case exp.IdentifierExpression:
+		if col, ok := t.GetCol().(string); ok {
+			return col
+		}
+	}
+	return ""
+}
+
+// join adds JOIN clauses of relations used by q to ds
+func join(ds *goqu.SelectDataset, q *rqp.Query) (*goqu.SelectDataset, error) {
+	for _, name := range q.Relations() {
+		r, _ := q.Relation(name)
+		t := goqu.T(r.Table).As(name)
+		on := goqu.On(goqu.I(name + "." + r.References).Eq(goqu.I(q.Qualifier() + "." + r.ForeignKey)))
+		switch strings.TrimSuffix(r.Join, " OUTER") {
+		case "INNER":
+			ds = ds.InnerJoin(t, on)
+		case "LEFT":
+			ds = ds.LeftJoin(t, on)
+		case "RIGHT":
+			ds = ds.RightJoin(t, on)
+		case "FULL":
+			ds = ds.FullJoin(t, on)
+		default:
+			return nil, errors.Wrapf(rqp.ErrMethodNotAllowed, "%s JOIN of %s", r.Join, name)
+		}
+	}
+	return ds, nil
+}
+
+// columns returns identifiers of columns of names
+func columns(q *rqp.Query, names []string) []interface{} {
+	columns := make([]interface{}, len(names))
+	for i, name := range names {
+		columns[i] = goqu.I(q.Column(name))
+	}
+	return columns
+}
+
+// expression returns expression of single filter
+func expression(q *rqp.Query, f *rqp.Filter, escape bool) (exp.Expression, error) {
+	c := goqu.I(q.Column(f.Name))
+
+	args, err := q.FilterArgs(f)
+	if err != nil {
+		return nil, errors.Wrap(err, f.Name)
+	}
+
+	switch f.Method {
+	case rqp.EQ:
+		return c.Eq(args[0]), nil
+	case rqp.NE:
+		return c.Neq(args[0]), nil
+	case rqp.GT:
+		return c.Gt(args[0]), nil
+	case rqp.LT:
+		return c.Lt(args[0]), nil
+	case rqp.GTE:
+		return c.Gte(args[0]), nil
+	case rqp.LTE:
+		return c.Lte(args[0]), nil
+	case rqp.IS:
+		return c.IsNull(), nil
+	case rqp.NOT:
+		return c.IsNotNull(), nil
+	case rqp.IN:
+		return c.In(args...), nil
+	case rqp.NIN:
+		return c.NotIn(args...), nil
+	case rqp.LIKE, rqp.STARTS, rqp.ENDS, rqp.CONTAINS_STR:
+		if escape {
+			return goqu.L(`? LIKE ? ESCAPE '\'`, c, args[0]), nil
+		}
+		return c.Like(args[0]), nil
+	case rqp.NLIKE:
+		if escape {
+			return goqu.L(`? NOT LIKE ? ESCAPE '\'`, c, args[0]), nil
+		}
+		return c.NotLike(args[0]), nil
+	case rqp.ILIKE:
+		// LIKE of SQLite and SQL Server is case-insensitive, goqu renders ILIKE as LIKE for them as well
+		if escape {
+			return goqu.L(`? LIKE ? ESCAPE '\'`, c, args[0]), nil
+		}
+		return c.ILike(args[0]), nil
+	case rqp.NILIKE:
+		if escape {
+			return goqu.L(`? NOT LIKE ? ESCAPE '\'`, c, args[0]), nil
+		}
+		return c.NotILike(args[0]), nil
+	case rqp.REGEX:
+		return c.RegexpLike(args[0]), nil
+	case rqp.BETWEEN:
+		return c.Between(exp.NewRangeVal(args[0], args[1])), nil
+	case rqp.RANGE:
+		r, _ := f.Value.(rqp.Range)
+		from, to := c.Gte(args[0]), c.Lte(args[1])
+		if r.ExcludeFrom {
+			from = c.Gt(args[0])
+		}
+		if r.ExcludeTo {
+			to = c.Lt(args[1])
+		}
+		return goqu.And(from, to), nil
+	default:
+		return nil, errors.Wrap(rqp.ErrMethodNotAllowed, f.Name)
+	}
+}
@@ -0,0 +1,161 @@
+package rqpgoqu
+
+import (
+	"testing"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/mysql"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlserver"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// parse returns parsed Query of raw query of URL
+func parse(t *testing.T, rawQuery string) *rqp.Query {
+	q := rqp.NewQV(nil, rqp.Validations{
+		"fields":     rqp.In("id", "name"),
+		"sort":       rqp.In("id", "name"),
+		"id:int":     nil,
+		"name":       nil,
+		"status":     nil,
+		"price:int":  nil,
+		"code:regex": nil,
+	})
+	assert.NoError(t, q.SetUrlString(rawQuery))
+	assert.NoError(t, q.Parse())
+	return q
+}
+
+func TestExpression(t *testing.T) {
+	cases := []struct {
+		url  string
+		sql  string
+		args []interface{}
+	}{
+		{
+			url:  "?",
+			sql:  `SELECT * FROM "users"`,
+			args: []interface{}{},
+		},
+		{
+			url:  "?id[in]=1,2&name[like]=tim*|status[is]=null&price[between]=1,5",
+			sql:  `SELECT * FROM "users" WHERE (("id" IN ($1, $2)) AND (("name" LIKE $3) OR ("status" IS NULL)) AND ("price" BETWEEN $4 AND $5))`,
+			args: []interface{}{int64(1), int64(2), "tim%", int64(1), int64(5)},
+		},
+		{
+			url:  "?name[ilike]=*a&status[nin]=x&id[ne]=1&price=(1,5]&code[regex]=^a",
+			sql:  `SELECT * FROM "users" WHERE (("code" ~ $1) AND ("id" != $2) AND ("name" ILIKE $3) AND (("price" > $4) AND ("price" <= $5)) AND ("status" NOT IN ($6)))`,
+			args: []interface{}{"^a", int64(1), "%a", int64(1), int64(5), "x"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			ds, err := Apply(goqu.Dialect("postgres").From("users").Prepared(true), parse(t, c.url))
+			assert.NoError(t, err)
+			sql, args, err := ds.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, c.sql, sql)
+			assert.Equal(t, c.args, args)
+		})
+	}
+
+	// top level OR, forced filters and other dialect
+	q := parse(t, "?id=1&name[nlike]=a")
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	q.AddForcedFilter("tenant_id", rqp.EQ, 7)
+	where, err := Expression(q)
+	assert.NoError(t, err)
+	sql, _, err := goqu.Dialect("mysql").From("users").Where(where).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE (((`id` = 1) OR (`name` NOT LIKE BINARY 'a')) AND (`tenant_id` = 7))", sql)
+
+	// wildcards of values are escaped by backslash, SQLite and SQL Server have no default escape character
+	ds, err := Apply(goqu.Dialect("sqlite3").From("users"), parse(t, "?name[like]=a_*&status[nilike]=b"))
+	assert.NoError(t, err)
+	sql, _, err = ds.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE (`name` LIKE 'a\\_%' ESCAPE '\\' AND `status` NOT LIKE 'b' ESCAPE '\\')", sql)
+
+	ds, err = Apply(goqu.Dialect("sqlserver").From("users").Prepared(true), parse(t, "?name[ilike]=a*&status[nlike]=b"))
+	assert.NoError(t, err)
+	sql, args, err := ds.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE ("name" LIKE @p1 ESCAPE '\' AND "status" NOT LIKE @p2 ESCAPE '\')`, sql)
+	assert.Equal(t, []interface{}{"a%", "b"}, args)
+
+	q = parse(t, "?name[like]=a*")
+	q.SetDialect(rqp.DialectSQLite)
+	where, err = Expression(q)
+	assert.NoError(t, err)
+	sql, _, err = goqu.Dialect("sqlite3").From("users").Where(where).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE `name` LIKE 'a%' ESCAPE '\\'", sql)
+
+	_, err = Expression(parse(t, "?name[fts]=a"))
+	assert.Error(t, err)
+
+	// raw filters aren't skipped
+	_, err = Expression(rqp.New().AddFilterRaw("1 = 1"))
+	assert.EqualError(t, err, "1 = 1: method are not allowed")
+}
+
+func TestApply(t *testing.T) {
+	q := parse(t, "?id[gt]=1&name=tim&fields=id,name&sort=-name,id&limit=10&offset=20")
+	q.SetNameMapping(rqp.Replacer{"name": "users.name"})
+
+	ds, err := Apply(goqu.From("users"), q)
+	assert.NoError(t, err)
+	sql, _, err := ds.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "id", "users"."name" FROM "users" WHERE (("id" > 1) AND ("users"."name" = 'tim')) ORDER BY "users"."name" DESC, "id" ASC LIMIT 10 OFFSET 20`, sql)
+}
+
+func TestApplyGroupAndRelations(t *testing.T) {
+	// group and having
+	q := rqp.NewQV(nil, rqp.Validations{"group": rqp.In("author_id"), "count(*):int": nil})
+	assert.NoError(t, q.SetUrlString("?group=author_id&having[count(*)][gte]=2"))
+	assert.NoError(t, q.Parse())
+
+	ds, err := Apply(goqu.Dialect("postgres").From("posts").Prepared(true), q)
+	assert.NoError(t, err)
+	sql, args, err := ds.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "author_id" FROM "posts" GROUP BY "author_id" HAVING count(*) >= $1`, sql)
+	assert.Equal(t, []interface{}{int64(2)}, args)
+
+	// distinct
+	q = rqp.NewQV(nil, rqp.Validations{"fields": rqp.In("author_id"), "distinct": nil})
+	assert.NoError(t, q.SetUrlString("?fields=author_id&distinct=true"))
+	assert.NoError(t, q.Parse())
+
+	ds, err = Apply(goqu.From("posts"), q)
+	assert.NoError(t, err)
+	sql, _, err = ds.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT DISTINCT "author_id" FROM "posts"`, sql)
+
+	// relations are joined to the table of the dataset
+	q = rqp.NewQV(nil, rqp.Validations{"author.name": nil, "id:int": nil}).SetRelations(map[string]rqp.Relation{
+		"author": {Table: "users", ForeignKey: "author_id", Join: "inner"},
+	})
+	assert.NoError(t, q.SetUrlString("?author.name=tim&id[gt]=1"))
+	assert.NoError(t, q.Parse())
+
+	ds, err = Apply(goqu.From("posts"), q)
+	assert.NoError(t, err)
+	sql, _, err = ds.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "posts".* FROM "posts" INNER JOIN "users" AS "author" ON ("author"."id" = "posts"."author_id") WHERE (("author"."name" = 'tim') AND ("posts"."id" > 1))`, sql)
+
+	ds, err = Apply(goqu.From(goqu.T("posts").As("p")), q)
+	assert.NoError(t, err)
+	sql, _, err = ds.ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "p".* FROM "posts" AS "p" INNER JOIN "users" AS "author" ON ("author"."id" = "p"."author_id") WHERE (("author"."name" = 'tim') AND ("p"."id" > 1))`, sql)
+
+	// table isn't known
+	_, err = Apply(goqu.From(), q)
+	assert.Equal(t, rqp.ErrNotQualified, err)
+}
@@ -0,0 +1,93 @@
+// Package rqpgorm applies parsed rest-query-parser Query to GORM queries:
+// Select, Joins, Where, Group, Having, Order, Limit and Offset are added with args bound by GORM.
+package rqpgorm
+
+import (
+	rqp "github.com/timsolov/rest-query-parser"
+	"gorm.io/gorm"
+)
+
+// Apply adds fields, distinct, relations, filters, group, having, sorts, limit and offset of the Query to db.
+// Statements are rendered with `?` placeholders whatever placeholder of q is,
+// GORM replaces them by placeholders of its dialect. Relations (see rqp.SetRelations) are joined
+// to the table of db.Table or db.Model, columns of the table are qualified by it. Example:
+//
+//	var users []User
+//	err := rqpgorm.Apply(db.Model(&User{}), q).Find(&users).Error
+func Apply(db *gorm.DB, q *rqp.Query) *gorm.DB {
+	db, q = qualified(db, q)
+	if q.Distinct {
+		db = db.Distinct()
+	}
+	if len(q.Fields) > 0 || len(q.Group) > 0 || q.Qualifier() != "" {
+		db = db.Select(q.Select())
+	}
+	db = where(db, q)
+	if group := q.GroupBy(); group != "" {
+		db = db.Group(group)
+	}
+	if having, args := q.HavingQuestion(); having != "" {
+		db = db.Having(having, args...)
+	}
+	if order := q.Order(); order != "" {
+		db = db.Order(order)
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+	return db
+}
+
+// Where adds only relations and filters of the Query to db, eg. to count rows of all pages:
+//
+//	var total int64
+//	err := rqpgorm.Where(db.Model(&User{}), q).Count(&total).Error
+func Where(db *gorm.DB, q *rqp.Query) *gorm.DB {
+	return where(qualified(db, q))
+}
+
+// Scope returns scope of GORM which applies the Query, see Apply:
+//
+//	err := db.Scopes(rqpgorm.Scope(q)).Find(&users).Error
+func Scope(q *rqp.Query) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return Apply(db, q)
+	}
+}
+
+// qualified returns q qualified by table of db if relations are joined (see rqp.Qualified).
+// rqp.ErrNotQualified is added to db if the table isn't known.
+func qualified(db *gorm.DB, q *rqp.Query) (*gorm.DB, *rqp.Query) {
+	if q.Qualifier() != "" || len(q.Relations()) == 0 {
+		return db, q
+	}
+
+	stmt := db.Statement
+	if stmt.Table == "" && stmt.Model != nil {
+		if err := stmt.Parse(stmt.Model); err != nil {
+			db = db.Scopes()
+			_ = db.AddError(err)
+			return db, q
+		}
+	}
+	if stmt.Table == "" {
+		db = db.Scopes()
+		_ = db.AddError(rqp.ErrNotQualified)
+		return db, q
+	}
+	return db, q.Qualified(stmt.Table)
+}
+
+// where adds relations and filters of q to db
+func where(db *gorm.DB, q *rqp.Query) *gorm.DB {
+	if join := q.Join(q.Qualifier()); join != "" {
+		db = db.Joins(join)
+	}
+	if w, args := q.WhereQuestion(); w != "" {
+		db = db.Where(w, args...)
+	}
+	return db
+}
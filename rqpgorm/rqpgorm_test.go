@@ -0,0 +1,120 @@
+package rqpgorm
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+	"gorm.io/gorm"
+)
+
+type user struct {
+	ID     int
+	Name   string
+	Status string
+}
+
+// open returns in-memory database with users
+func open(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&user{}))
+	assert.NoError(t, db.Create([]user{
+		{ID: 1, Name: "tim", Status: "a"},
+		{ID: 2, Name: "tom", Status: "b"},
+		{ID: 3, Name: "bob", Status: "a"},
+		{ID: 4, Name: "tina", Status: "c"},
+	}).Error)
+	return db
+}
+
+func TestApply(t *testing.T) {
+	db := open(t)
+	validations := rqp.Validations{
+		"fields": rqp.In("id", "name"),
+		"sort":   rqp.In("id", "name"),
+		"id:int": nil,
+		"name":   nil,
+		"status": nil,
+	}
+
+	q := rqp.NewQV(nil, validations).SetPlaceholder(rqp.PlaceholderDollar)
+	assert.NoError(t, q.SetUrlString("?name[like]=t*&status[in]=a,c|id=2&fields=id,name&sort=-id&limit=2&offset=1"))
+	assert.NoError(t, q.Parse())
+
+	var users []user
+	assert.NoError(t, Apply(db.Model(&user{}), q).Find(&users).Error)
+	assert.Equal(t, []user{{ID: 2, Name: "tom"}, {ID: 1, Name: "tim"}}, users)
+	// placeholder of q isn't changed
+	assert.Equal(t, rqp.PlaceholderDollar, q.Placeholder())
+
+	var total int64
+	assert.NoError(t, Where(db.Model(&user{}), q).Count(&total).Error)
+	assert.Equal(t, int64(3), total)
+
+	users = nil
+	assert.NoError(t, db.Scopes(Scope(rqp.New())).Order("id").Find(&users).Error)
+	assert.Len(t, users, 4)
+
+	stmt := Apply(db.Session(&gorm.Session{DryRun: true}), q).Find(&[]user{}).Statement
+	assert.Equal(t, "SELECT id, name FROM `users` WHERE name LIKE ? AND (status IN (?, ?) OR id = ?) ORDER BY id DESC LIMIT 2 OFFSET 1", stmt.SQL.String())
+	assert.Equal(t, []interface{}{"t%", "a", "c", 2}, stmt.Vars)
+}
+
+type post struct {
+	ID       int
+	AuthorID int
+	Title    string
+}
+
+func TestApplyGroupAndRelations(t *testing.T) {
+	db := open(t)
+	assert.NoError(t, db.AutoMigrate(&post{}))
+	assert.NoError(t, db.Create([]post{
+		{ID: 1, AuthorID: 1, Title: "a"},
+		{ID: 2, AuthorID: 1, Title: "b"},
+		{ID: 3, AuthorID: 2, Title: "c"},
+	}).Error)
+
+	// group and having
+	q := rqp.NewQV(nil, rqp.Validations{"group": rqp.In("author_id"), "count(*):int": nil})
+	assert.NoError(t, q.SetUrlString("?group=author_id&having[count(*)][gte]=2"))
+	assert.NoError(t, q.Parse())
+
+	var ids []int
+	assert.NoError(t, Apply(db.Model(&post{}), q).Pluck("author_id", &ids).Error)
+	assert.Equal(t, []int{1}, ids)
+
+	stmt := Apply(db.Session(&gorm.Session{DryRun: true}).Model(&post{}), q).Find(&[]post{}).Statement
+	assert.Equal(t, "SELECT `author_id` FROM `posts` GROUP BY `author_id` HAVING count(*) >= ?", stmt.SQL.String())
+	assert.Equal(t, []interface{}{2}, stmt.Vars)
+
+	// distinct
+	q = rqp.NewQV(nil, rqp.Validations{"fields": rqp.In("author_id"), "distinct": nil})
+	assert.NoError(t, q.SetUrlString("?fields=author_id&distinct=true"))
+	assert.NoError(t, q.Parse())
+	stmt = Apply(db.Session(&gorm.Session{DryRun: true}).Model(&post{}), q).Find(&[]post{}).Statement
+	assert.Equal(t, "SELECT DISTINCT `author_id` FROM `posts`", stmt.SQL.String())
+
+	// relations are joined to the table of the model
+	q = rqp.NewQV(nil, rqp.Validations{"author.name": nil, "id:int": nil}).SetRelations(map[string]rqp.Relation{
+		"author": {Table: "users", ForeignKey: "author_id", Join: "inner"},
+	})
+	assert.NoError(t, q.SetUrlString("?author.name=tim&id[gt]=1"))
+	assert.NoError(t, q.Parse())
+
+	var posts []post
+	assert.NoError(t, Apply(db.Model(&post{}), q).Find(&posts).Error)
+	assert.Equal(t, []post{{ID: 2, AuthorID: 1, Title: "b"}}, posts)
+
+	var total int64
+	assert.NoError(t, Where(db.Table("posts"), q).Count(&total).Error)
+	assert.Equal(t, int64(1), total)
+
+	stmt = Apply(db.Session(&gorm.Session{DryRun: true}).Model(&post{}), q).Find(&[]post{}).Statement
+	assert.Equal(t, "SELECT posts.* FROM `posts` INNER JOIN users AS author ON author.id = posts.author_id WHERE author.name = ? AND posts.id > ?", stmt.SQL.String())
+
+	// table isn't known
+	assert.Equal(t, rqp.ErrNotQualified, Apply(db, q).Find(&posts).Error)
+}
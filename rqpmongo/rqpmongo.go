@@ -0,0 +1,207 @@
+// Package rqpmongo renders parsed rest-query-parser Query for MongoDB: filters are
+// rendered as bson.M and sort, fields, limit and offset as options.FindOptions,
+// so the same validations and query syntax could back MongoDB collections.
+package rqpmongo
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	rqp "github.com/timsolov/rest-query-parser"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mongo returns filter and options of Find for the Query.
+// Example:
+//
+//	filter, opts, err := rqpmongo.Mongo(q)
+//	cur, err := db.Collection("users").Find(ctx, filter, opts)
+func Mongo(q *rqp.Query) (bson.M, *options.FindOptions, error) {
+	filter, err := Filter(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return filter, FindOptions(q), nil
+}
+
+// Filter returns filters of the Query as filter document of MongoDB.
+// OR statements are `$or`, top level filters are joined by `$and` or `$or` (see rqp.SetTopLevelJoin),
+// forced filters are always joined by `$and`. LIKE methods are regular expressions,
+// FTS is `$text` search. Raw filters can't be rendered (see rqp.Groups) so they are ErrMethodNotAllowed,
+// custom methods of SetMethodSQL too.
+func Filter(q *rqp.Query) (bson.M, error) {
+	filters, err := q.Groups()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := bson.A{}
+	for _, group := range filters {
+		or := make(bson.A, 0, len(group))
+		for _, f := range group {
+			c, err := condition(q, f)
+			if err != nil {
+				return nil, err
+			}
+			or = append(or, c)
+		}
+		if len(or) == 1 {
+			groups = append(groups, or[0])
+		} else {
+			groups = append(groups, bson.M{"$or": or})
+		}
+	}
+
+	and := bson.A{}
+	if q.TopLevelJoin() == "OR" && len(groups) > 1 {
+		and = append(and, bson.M{"$or": groups})
+	} else {
+		and = append(and, groups...)
+	}
+	for _, f := range q.ForcedFilters() {
+		c, err := condition(q, f)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, c)
+	}
+
+	switch len(and) {
+	case 0:
+		return bson.M{}, nil
+	case 1:
+		return and[0].(bson.M), nil
+	default:
+		return bson.M{"$and": and}, nil
+	}
+}
+
+// FindOptions returns sort, projection of fields, limit and offset of the Query as options of Find
+func FindOptions(q *rqp.Query) *options.FindOptions {
+	opts := options.Find()
+	if len(q.Sorts) > 0 {
+		sort := make(bson.D, len(q.Sorts))
+		for i, s := range q.Sorts {
+			order := 1
+			if s.Desc {
+				order = -1
+			}
+			sort[i] = bson.E{Key: q.Column(s.By), Value: order}
+		}
+		opts.SetSort(sort)
+	}
+	if len(q.Fields) > 0 {
+		projection := make(bson.D, len(q.Fields))
+		for i, field := range q.Fields {
+			projection[i] = bson.E{Key: q.Column(field), Value: 1}
+		}
+		opts.SetProjection(projection)
+	}
+	if q.Limit > 0 {
+		opts.SetLimit(int64(q.Limit))
+	}
+	if q.Offset > 0 {
+		opts.SetSkip(int64(q.Offset))
+	}
+	return opts
+}
+
+// operators are operators of methods with single argument
+var operators = map[rqp.Method]string{
+	rqp.EQ:  "$eq",
+	rqp.NE:  "$ne",
+	rqp.GT:  "$gt",
+	rqp.LT:  "$lt",
+	rqp.GTE: "$gte",
+	rqp.LTE: "$lte",
+}
+
+// condition returns filter document of single filter
+func condition(q *rqp.Query, f *rqp.Filter) (bson.M, error) {
+	name := q.Column(f.Name)
+
+	args, err := q.FilterArgs(f)
+	if err != nil {
+		return nil, errors.Wrap(err, f.Name)
+	}
+
+	switch f.Method {
+	case rqp.EQ, rqp.NE, rqp.GT, rqp.LT, rqp.GTE, rqp.LTE:
+		return bson.M{name: bson.M{operators[f.Method]: args[0]}}, nil
+	case rqp.IS:
+		return bson.M{name: bson.M{"$eq": nil}}, nil
+	case rqp.NOT:
+		return bson.M{name: bson.M{"$ne": nil}}, nil
+	case rqp.IN:
+		return bson.M{name: bson.M{"$in": bson.A(args)}}, nil
+	case rqp.NIN:
+		return bson.M{name: bson.M{"$nin": bson.A(args)}}, nil
+	case rqp.CONTAINS:
+		return bson.M{name: bson.M{"$all": bson.A(args)}}, nil
+	case rqp.LIKE, rqp.STARTS, rqp.ENDS, rqp.CONTAINS_STR:
+		return bson.M{name: likeRegex(args[0], "")}, nil
+	case rqp.ILIKE:
+		return bson.M{name: likeRegex(args[0], "i")}, nil
+	case rqp.NLIKE:
+		return bson.M{name: bson.M{"$not": likeRegex(args[0], "")}}, nil
+	case rqp.NILIKE:
+		return bson.M{name: bson.M{"$not": likeRegex(args[0], "i")}}, nil
+	case rqp.REGEX:
+		s, _ := args[0].(string)
+		return bson.M{name: primitive.Regex{Pattern: s}}, nil
+	case rqp.FTS:
+		return bson.M{"$text": bson.M{"$search": args[0]}}, nil
+	case rqp.BETWEEN:
+		return bson.M{name: bson.M{"$gte": args[0], "$lte": args[1]}}, nil
+	case rqp.RANGE:
+		r, _ := f.Value.(rqp.Range)
+		from, to := "$gte", "$lte"
+		if r.ExcludeFrom {
+			from = "$gt"
+		}
+		if r.ExcludeTo {
+			to = "$lt"
+		}
+		return bson.M{name: bson.M{from: args[0], to: args[1]}}, nil
+	default:
+		return nil, errors.Wrap(rqp.ErrMethodNotAllowed, f.Name)
+	}
+}
+
+// likeRegex returns regular expression of LIKE pattern with `%` and `_` wildcards,
+// `\` escapes the next character of pattern
+func likeRegex(pattern interface{}, options string) primitive.Regex {
+	s, _ := pattern.(string)
+
+	var b strings.Builder
+	b.WriteByte('^')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteByte('.')
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				c = s[i]
+			}
+			b.WriteString(regexpQuote(c))
+		default:
+			b.WriteString(regexpQuote(c))
+		}
+	}
+	b.WriteByte('$')
+
+	return primitive.Regex{Pattern: b.String(), Options: options}
+}
+
+// regexpQuote escapes metacharacter of regular expression
+func regexpQuote(c byte) string {
+	if strings.IndexByte(`\.+*?()|[]{}^$`, c) != -1 {
+		return `\` + string(c)
+	}
+	return string(c)
+}
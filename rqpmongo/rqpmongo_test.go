@@ -0,0 +1,104 @@
+package rqpmongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMongo(t *testing.T) {
+	validations := rqp.Validations{
+		"fields":      rqp.In("id", "name"),
+		"sort":        rqp.In("id", "createdAt"),
+		"id:int":      nil,
+		"age:int":     nil,
+		"name":        nil,
+		"status":      nil,
+		"deleted_at":  nil,
+		"price:float": nil,
+		"createdAt":   nil,
+	}
+
+	q := rqp.NewQV(nil, validations).SetNameMapping(rqp.Replacer{"createdAt": "created_at"})
+	assert.NoError(t, q.SetUrlString("?id[in]=1,2&age[gte]=18&name[like]=ti_m*|status=a&deleted_at[is]=null&price[between]=1,9.5&fields=id,name&sort=-createdAt,id&limit=10&offset=20"))
+	assert.NoError(t, q.Parse())
+	q.AddForcedFilter("tenant", rqp.EQ, 7)
+
+	filter, opts, err := Mongo(q)
+	assert.NoError(t, err)
+	assert.Equal(t, bson.M{"$and": bson.A{
+		bson.M{"age": bson.M{"$gte": 18}},
+		bson.M{"deleted_at": bson.M{"$eq": nil}},
+		bson.M{"id": bson.M{"$in": bson.A{1, 2}}},
+		bson.M{"$or": bson.A{
+			bson.M{"name": primitive.Regex{Pattern: `^ti_m.*$`}},
+			bson.M{"status": bson.M{"$eq": "a"}},
+		}},
+		bson.M{"price": bson.M{"$gte": 1.0, "$lte": 9.5}},
+		bson.M{"tenant": bson.M{"$eq": 7}},
+	}}, filter)
+	assert.Equal(t, bson.D{{Key: "created_at", Value: -1}, {Key: "id", Value: 1}}, opts.Sort)
+	assert.Equal(t, bson.D{{Key: "id", Value: 1}, {Key: "name", Value: 1}}, opts.Projection)
+	assert.Equal(t, int64(10), *opts.Limit)
+	assert.Equal(t, int64(20), *opts.Skip)
+}
+
+func TestFilter(t *testing.T) {
+	validations := rqp.Validations{
+		"id:int":    nil,
+		"name":      nil,
+		"status":    nil,
+		"price:int": nil,
+	}
+
+	cases := []struct {
+		url    string
+		filter bson.M
+	}{
+		{url: "?", filter: bson.M{}},
+		{url: "?name[ilike]=a.b*", filter: bson.M{"name": primitive.Regex{Pattern: `^a\.b.*$`, Options: "i"}}},
+		{url: "?name[nlike]=*a", filter: bson.M{"name": bson.M{"$not": primitive.Regex{Pattern: `^.*a$`}}}},
+		{url: "?name[contains_str]=a%25", filter: bson.M{"name": primitive.Regex{Pattern: `^.*a%.*$`}}},
+		{url: "?status[nin]=a,b", filter: bson.M{"status": bson.M{"$nin": bson.A{"a", "b"}}}},
+		{url: "?status[not]=null", filter: bson.M{"status": bson.M{"$ne": nil}}},
+		{url: "?price=[1,5)", filter: bson.M{"price": bson.M{"$gte": 1, "$lt": 5}}},
+		{url: "?id[ne]=1", filter: bson.M{"id": bson.M{"$ne": 1}}},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := rqp.NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			filter, err := Filter(q)
+			assert.NoError(t, err)
+			assert.Equal(t, c.filter, filter)
+		})
+	}
+
+	// top level OR
+	q := rqp.NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?id=1&name=a"))
+	assert.NoError(t, q.Parse())
+	assert.NoError(t, q.SetTopLevelJoin("OR"))
+	q.AddForcedFilter("status", rqp.EQ, "a")
+	filter, err := Filter(q)
+	assert.NoError(t, err)
+	assert.Equal(t, bson.M{"$and": bson.A{
+		bson.M{"$or": bson.A{bson.M{"id": bson.M{"$eq": 1}}, bson.M{"name": bson.M{"$eq": "a"}}}},
+		bson.M{"status": bson.M{"$eq": "a"}},
+	}}, filter)
+
+	// custom methods are SQL
+	q = rqp.NewQV(nil, validations).SetMethodSQL("SIMILAR", "SIMILAR TO")
+	assert.NoError(t, q.SetUrlString("?name[similar]=a"))
+	assert.NoError(t, q.Parse())
+	_, err = Filter(q)
+	assert.EqualError(t, err, "name: method are not allowed")
+
+	// raw filters are SQL too
+	_, err = Filter(rqp.New().AddFilterRaw("1 = 1"))
+	assert.EqualError(t, err, "1 = 1: method are not allowed")
+}
@@ -0,0 +1,90 @@
+// Package rqpsquirrel exposes parsed rest-query-parser Query for squirrel:
+// filters are squirrel.Sqlizer and the whole Query could be applied to SelectBuilder,
+// so joins, CTEs and other conditions could be composed around it.
+package rqpsquirrel
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// Sqlizer returns filters of the Query as squirrel.Sqlizer with `?` placeholders,
+// they are replaced by PlaceholderFormat of the builder. Without filters it is `(1=1)`.
+// Example:
+//
+//	sql, args, err := sq.Select("*").From("users").
+//		Where(rqpsquirrel.Sqlizer(q)).
+//		Where(sq.Eq{"tenant_id": tenantID}).
+//		PlaceholderFormat(sq.Dollar).ToSql()
+func Sqlizer(q *rqp.Query) sq.Sqlizer {
+	where, args := q.WhereQuestion()
+	if where == "" {
+		return sq.And{}
+	}
+	return sq.Expr(where, args...)
+}
+
+// Apply adds fields, distinct, relations, filters, group, having, sorts, limit and offset of the Query to b:
+//
+//	b := rqpsquirrel.Apply(sq.Select().From("users u").Join("teams t ON t.id = u.team_id"), q)
+//
+// The main table of b isn't known, so relations (see rqp.SetRelations) are joined only
+// if the Query is qualified by it: `rqpsquirrel.Apply(b, q.Qualified("posts"))`,
+// otherwise ToSql of b returns rqp.ErrNotQualified.
+func Apply(b sq.SelectBuilder, q *rqp.Query) sq.SelectBuilder {
+	if q.Qualifier() == "" && len(q.Relations()) > 0 {
+		return b.Where(errSqlizer{rqp.ErrNotQualified})
+	}
+	if len(q.Fields) > 0 || len(q.Group) > 0 || q.Qualifier() != "" {
+		b = b.Columns(q.Select())
+	}
+	return apply(b, q)
+}
+
+// Select returns SELECT statement of the Query from table, fields are `*` if they aren't set.
+// Relations are joined to table.
+func Select(q *rqp.Query, table string) sq.SelectBuilder {
+	if q.Qualifier() == "" && len(q.Relations()) > 0 {
+		q = q.Qualified(table)
+	}
+	return apply(sq.Select(q.Select()).From(table), q)
+}
+
+// apply adds distinct, relations, filters, group, having, sorts, limit and offset of q to b
+func apply(b sq.SelectBuilder, q *rqp.Query) sq.SelectBuilder {
+	if q.Distinct {
+		b = b.Distinct()
+	}
+	if join := q.Join(q.Qualifier()); join != "" {
+		b = b.JoinClause(join)
+	}
+	if where, args := q.WhereQuestion(); where != "" {
+		b = b.Where(where, args...)
+	}
+	if group := q.GroupBy(); group != "" {
+		b = b.GroupBy(group)
+	}
+	if having, args := q.HavingQuestion(); having != "" {
+		b = b.Having(having, args...)
+	}
+	if order := q.Order(); order != "" {
+		b = b.OrderBy(order)
+	}
+	if q.Limit > 0 {
+		b = b.Limit(uint64(q.Limit))
+	}
+	if q.Offset > 0 {
+		b = b.Offset(uint64(q.Offset))
+	}
+	return b
+}
+
+// errSqlizer is Sqlizer which fails building of statement with err
+type errSqlizer struct {
+	err error
+}
+
+// ToSql returns error of errSqlizer
+func (e errSqlizer) ToSql() (string, []interface{}, error) {
+	return "", nil, e.err
+}
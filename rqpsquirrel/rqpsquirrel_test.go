@@ -0,0 +1,85 @@
+package rqpsquirrel
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	rqp "github.com/timsolov/rest-query-parser"
+)
+
+// parse returns parsed Query of raw query of URL
+func parse(t *testing.T, rawQuery string) *rqp.Query {
+	q := rqp.NewQV(nil, rqp.Validations{
+		"fields": rqp.In("id", "name"),
+		"sort":   rqp.In("id", "name"),
+		"id:int": nil,
+		"name":   nil,
+	}).SetPlaceholder(rqp.PlaceholderDollar)
+	assert.NoError(t, q.SetUrlString(rawQuery))
+	assert.NoError(t, q.Parse())
+	return q
+}
+
+func TestSqlizer(t *testing.T) {
+	q := parse(t, "?id[in]=1,2&name=tim|name=bob")
+
+	sql, args, err := sq.Select("*").From("users").
+		Where(Sqlizer(q)).
+		Where(sq.Eq{"tenant_id": 7}).
+		PlaceholderFormat(sq.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id IN ($1, $2) AND (name = $3 OR name = $4) AND tenant_id = $5", sql)
+	assert.Equal(t, []interface{}{1, 2, "tim", "bob", 7}, args)
+
+	sql, args, err = sq.Select("*").From("users").Where(Sqlizer(parse(t, "?"))).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (1=1)", sql)
+	assert.Empty(t, args)
+}
+
+func TestApply(t *testing.T) {
+	q := parse(t, "?id[gt]=1&fields=id,name&sort=-name&limit=10&offset=20")
+
+	sql, args, err := Apply(sq.Select().From("users u").Join("teams t ON t.id = u.team_id"), q).
+		PlaceholderFormat(sq.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users u JOIN teams t ON t.id = u.team_id WHERE id > $1 ORDER BY name DESC LIMIT 10 OFFSET 20", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Select(parse(t, "?name=tim"), "users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE name = ?", sql)
+	assert.Equal(t, []interface{}{"tim"}, args)
+}
+
+func TestApplyGroupAndRelations(t *testing.T) {
+	q := rqp.NewQV(nil, rqp.Validations{"group": rqp.In("author_id"), "count(*):int": nil, "distinct": nil})
+	assert.NoError(t, q.SetUrlString("?group=author_id&having[count(*)][gte]=2&distinct=true"))
+	assert.NoError(t, q.Parse())
+
+	sql, args, err := Apply(sq.Select().From("posts"), q).PlaceholderFormat(sq.Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT author_id FROM posts GROUP BY author_id HAVING count(*) >= $1", sql)
+	assert.Equal(t, []interface{}{2}, args)
+
+	q = rqp.NewQV(nil, rqp.Validations{"author.name": nil, "id:int": nil}).SetRelations(map[string]rqp.Relation{
+		"author": {Table: "users", ForeignKey: "author_id"},
+	}).SetPlaceholder(rqp.PlaceholderDollar)
+	assert.NoError(t, q.SetUrlString("?author.name=tim&id[gt]=1"))
+	assert.NoError(t, q.Parse())
+
+	const expected = "SELECT posts.* FROM posts LEFT JOIN users AS author ON author.id = posts.author_id WHERE author.name = ? AND posts.id > ?"
+	sql, args, err = Select(q, "posts").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, sql)
+	assert.Equal(t, []interface{}{"tim", 1}, args)
+
+	sql, _, err = Apply(sq.Select().From("posts"), q.Qualified("posts")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, sql)
+
+	// main table isn't known
+	_, _, err = Apply(sq.Select("*").From("posts"), q).ToSql()
+	assert.Equal(t, rqp.ErrNotQualified, err)
+}
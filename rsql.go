@@ -0,0 +1,257 @@
+package rqp
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// rsqlKey is a key of RSQL expression in query
+const rsqlKey = "filter"
+
+// rsqlMethods are methods of FIQL operators `=gt=`, etc. and their short forms,
+// other operators `=method=` are methods of the default syntax, eg. `=ilike=`
+var rsqlMethods = map[string]Method{
+	"==":  EQ,
+	"!=":  NE,
+	"gt":  GT,
+	">":   GT,
+	"ge":  GTE,
+	">=":  GTE,
+	"lt":  LT,
+	"<":   LT,
+	"le":  LTE,
+	"<=":  LTE,
+	"in":  IN,
+	"out": NIN,
+}
+
+// rsqlReserved are characters which could not be in unquoted values
+const rsqlReserved = `"'();,=!~<> `
+
+// rsqlQuery translates query of RSQL syntax:
+//
+//	filter=name==Kill*;year=gt=2003 -> name[like]=Kill*&year[gt]=2003
+//	filter=status=in=(a,b),age<18   -> status[in]=a,b|age[lt]=18
+//
+// Other parameters (sort, fields, limit, etc.) are parsed as usual.
+func (q *Query) rsqlQuery() (url.Values, error) {
+	values := make(url.Values, len(q.query))
+
+	for key, list := range q.query {
+		if key != rsqlKey {
+			values[key] = append(values[key], list...)
+			continue
+		}
+		for _, s := range list {
+			p := &rsqlParser{s: s}
+			groups, err := p.parse()
+			if err == nil {
+				err = q.addConditions(values, groups)
+			}
+			if err != nil {
+				return nil, errors.Wrapf(ErrBadFormat, "%s: %v", rsqlKey, err)
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// rsqlParser parses RSQL expression
+type rsqlParser struct {
+	s   string
+	pos int
+}
+
+// next returns the next character without moving to it or 0 at the end
+func (p *rsqlParser) next() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+// expect moves to the next character if it's c
+func (p *rsqlParser) expect(c byte) error {
+	if p.next() != c {
+		return errors.Errorf("expected %q at %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+// parse returns conditions of expression grouped by OR statements, groups are joined by AND
+func (p *rsqlParser) parse() ([][]condition, error) {
+	groups, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, errors.Errorf("unexpected %q at %d", p.s[p.pos], p.pos)
+	}
+	return groups, nil
+}
+
+// or parses `and-expression,and-expression...`
+func (p *rsqlParser) or() ([][]condition, error) {
+	groups, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	if p.next() != ',' {
+		return groups, nil
+	}
+
+	var or []condition
+	for {
+		// only single conditions or OR statements could be joined by OR
+		if len(groups) > 1 {
+			return nil, errors.New("; inside of , must be in parentheses of its own filter")
+		}
+		or = append(or, groups[0]...)
+		if p.next() != ',' {
+			return [][]condition{or}, nil
+		}
+		p.pos++
+		if groups, err = p.and(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// and parses `constraint;constraint...`
+func (p *rsqlParser) and() ([][]condition, error) {
+	var groups [][]condition
+	for {
+		g, err := p.constraint()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g...)
+		if p.next() != ';' {
+			return groups, nil
+		}
+		p.pos++
+	}
+}
+
+// constraint parses expression in parentheses or comparison `selector operator arguments`
+func (p *rsqlParser) constraint() ([][]condition, error) {
+	if p.next() == '(' {
+		p.pos++
+		groups, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return groups, p.expect(')')
+	}
+
+	name := p.unquoted()
+	if name == "" {
+		return nil, errors.Errorf("expected selector at %d", p.pos)
+	}
+
+	op, err := p.operator()
+	if err != nil {
+		return nil, err
+	}
+	m, ok := rsqlMethods[op]
+	if !ok {
+		m = Method(strings.ToUpper(op))
+	}
+
+	var values []string
+	if p.next() == '(' {
+		p.pos++
+		for {
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if p.next() != ',' {
+				break
+			}
+			p.pos++
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+	} else {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		// wildcards of equality are LIKE and NOT LIKE
+		if strings.Contains(value, "*") {
+			switch m {
+			case EQ:
+				m = LIKE
+			case NE:
+				m = NLIKE
+			}
+		}
+		values = []string{value}
+	}
+
+	return [][]condition{{{name: name, method: m, values: values}}}, nil
+}
+
+// operator parses comparison operator: `==`, `!=`, `<`, `<=`, `>`, `>=` or `=name=`
+func (p *rsqlParser) operator() (string, error) {
+	rest := p.s[p.pos:]
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op, nil
+		}
+	}
+	if strings.HasPrefix(rest, "=") {
+		if i := strings.IndexByte(rest[1:], '='); i > 0 {
+			op := rest[1 : i+1]
+			if strings.IndexAny(op, rsqlReserved) == -1 {
+				p.pos += i + 2
+				return strings.ToLower(op), nil
+			}
+		}
+	}
+	return "", errors.Errorf("expected operator at %d", p.pos)
+}
+
+// value parses argument: unquoted value or string in single or double quotes, `\` escapes quotes
+func (p *rsqlParser) value() (string, error) {
+	quote := p.next()
+	if quote != '"' && quote != '\'' {
+		value := p.unquoted()
+		if value == "" {
+			return "", errors.Errorf("expected value at %d", p.pos)
+		}
+		return value, nil
+	}
+
+	var b strings.Builder
+	for i := p.pos + 1; i < len(p.s); i++ {
+		switch c := p.s[i]; {
+		case c == '\\' && i+1 < len(p.s):
+			i++
+			b.WriteByte(p.s[i])
+		case c == quote:
+			p.pos = i + 1
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.Errorf("unterminated string at %d", p.pos)
+}
+
+// unquoted parses characters until reserved one
+func (p *rsqlParser) unquoted() string {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(rsqlReserved, p.s[p.pos]) == -1 {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
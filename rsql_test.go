@@ -0,0 +1,86 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxRSQL(t *testing.T) {
+	validations := Validations{
+		"fields":   In("id", "name"),
+		"sort":     In("id", "year"),
+		"name":     nil,
+		"year:int": nil,
+		"status":   nil,
+		"genre":    nil,
+	}
+
+	cases := []struct {
+		filter string
+		where  string
+		args   []interface{}
+	}{
+		{
+			filter: "name==Kill*;year=gt=2003",
+			where:  "name LIKE ? AND year > ?",
+			args:   []interface{}{"Kill%", 2003},
+		},
+		{
+			filter: "year>=2000;year<2010;name!=Kill*",
+			where:  "name NOT LIKE ? AND year >= ? AND year < ?",
+			args:   []interface{}{"Kill%", 2000, 2010},
+		},
+		{
+			filter: "(status==a,genre=in=(drama,'sci fi'));year=le=1999",
+			where:  "(status = ? OR genre IN (?, ?)) AND year <= ?",
+			args:   []interface{}{"a", "drama", "sci fi", 1999},
+		},
+		{
+			filter: `status==a,name=="Tom \"T\"",(year<1,year>100)`,
+			where:  "(status = ? OR name = ? OR year < ? OR year > ?)",
+			args:   []interface{}{"a", `Tom "T"`, 1, 100},
+		},
+		{
+			filter: "genre=out=(a,b);name=ilike=tim*;year!=1",
+			where:  "genre NOT IN (?, ?) AND name ILIKE ? AND year != ?",
+			args:   []interface{}{"a", "b", "tim%", 1},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.filter, func(t *testing.T) {
+			q := NewQV(nil, validations).SetSyntax(SyntaxRSQL)
+			q.SetUrlQuery(map[string][]string{"filter": {c.filter}})
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	q := NewQV(nil, validations).SetSyntax(SyntaxRSQL)
+	assert.NoError(t, q.SetUrlString("?filter=year=ge=2000&fields=id,name&sort=-year&limit=5"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "SELECT id, name FROM t WHERE year >= ? ORDER BY year DESC LIMIT 5", q.SQL("t"))
+
+	errs := []struct {
+		filter string
+		err    string
+	}{
+		{"name", `filter: expected operator at 4: bad format`},
+		{"==a", `filter: expected selector at 0: bad format`},
+		{"name==", `filter: expected value at 6: bad format`},
+		{"name=='a", `filter: unterminated string at 6: bad format`},
+		{"(name==a", `filter: expected ')' at 8: bad format`},
+		{"name==a)", `filter: unexpected ')' at 7: bad format`},
+		{"name==a;year==1,status==b", `filter: ; inside of , must be in parentheses of its own filter: bad format`},
+		{"year=gt=a", `year[gt]: bad format`},
+		{"year=foo=1", `year[foo]: unknown method`},
+		{"name=='x|id=5'", `filter: value "x|id=5" contains "|": bad format`},
+	}
+	for _, c := range errs {
+		t.Run(c.filter, func(t *testing.T) {
+			q.SetUrlQuery(map[string][]string{"filter": {c.filter}})
+			assert.EqualError(t, q.Parse(), c.err)
+		})
+	}
+}
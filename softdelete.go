@@ -0,0 +1,59 @@
+package rqp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// withDeletedKey is the key of query part of URL to include soft deleted rows
+const withDeletedKey = "with_deleted"
+
+// SetSoftDelete sets column of soft deleted rows. Condition `column IS NULL` is added
+// to WHERE statement with AND like forced filters (see AddForcedFilter) unless
+// the client passes `with_deleted=true` which must be allowed by validations:
+//
+//	q := rqp.NewQV(nil, rqp.Validations{"with_deleted": nil}).SetSoftDelete("deleted_at")
+//	// ?id=1                   -> WHERE id = ? AND deleted_at IS NULL
+//	// ?id=1&with_deleted=true -> WHERE id = ?
+func (q *Query) SetSoftDelete(column string) *Query {
+	q.invalidate()
+	q.softDelete = column
+	return q
+}
+
+// isWithDeletedKey returns true if key includes soft deleted rows and it's allowed
+func (q *Query) isWithDeletedKey(key string) bool {
+	if q.softDelete == "" || key != withDeletedKey {
+		return false
+	}
+	_, ok := q.detectValidation(key, EQ)
+	return ok
+}
+
+// parseWithDeleted parses value of with_deleted parameter
+func (q *Query) parseWithDeleted(value []string) error {
+	value, err := q.singleValue(value)
+	if err != nil {
+		return err
+	}
+
+	b, err := strconv.ParseBool(strings.TrimSpace(value[0]))
+	if err != nil {
+		return ErrBadFormat
+	}
+
+	q.withDeleted = b
+	return nil
+}
+
+// forcedFilters returns forced filters with condition of soft delete
+func (q *Query) forcedFilters() []*Filter {
+	if q.softDelete == "" || q.withDeleted {
+		return q.forced
+	}
+	return append(append([]*Filter{}, q.forced...), &Filter{
+		Name:   q.softDelete,
+		Method: IS,
+		Value:  NULL,
+	})
+}
@@ -0,0 +1,43 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetSoftDelete(t *testing.T) {
+	q := NewQV(nil, Validations{"id:int": nil, "with_deleted": nil}).SetSoftDelete("deleted_at")
+
+	assert.NoError(t, q.SetUrlString("?id=1"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id = ? AND deleted_at IS NULL", q.Where())
+	assert.Equal(t, []interface{}{1}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?id=1&with_deleted=true"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id = ?", q.Where())
+	assert.Equal(t, "id = ?", q.Clone().Where())
+
+	assert.NoError(t, q.SetUrlString("?id=1&with_deleted=false"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "id = ? AND deleted_at IS NULL", q.Where())
+
+	assert.NoError(t, q.SetUrlString("?with_deleted=yes"))
+	assert.EqualError(t, q.Parse(), "with_deleted: bad format")
+
+	// with_deleted isn't allowed without validation
+	q = NewQV(nil, Validations{"id:int": nil}).SetSoftDelete("deleted_at")
+	assert.NoError(t, q.SetUrlString("?with_deleted=true"))
+	assert.EqualError(t, q.Parse(), "with_deleted: filter not found")
+
+	type row struct {
+		DeletedAt *string `db:"deleted_at"`
+	}
+	deleted := "2024-01-01"
+	assert.NoError(t, q.SetUrlString("?"))
+	assert.NoError(t, q.Parse())
+	assert.True(t, q.Match(row{}))
+	assert.False(t, q.Match(row{DeletedAt: &deleted}))
+	assert.Len(t, q.ForcedFilters(), 1)
+}
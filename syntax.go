@@ -0,0 +1,192 @@
+package rqp
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Syntax is a syntax of query part of URL. Queries of all syntaxes are translated
+// to the default one before parsing, so validations and options are the same.
+type Syntax byte
+
+// Syntaxes:
+const (
+	// SyntaxDefault is `?name[like]=tim*&sort=-id&limit=10`
+	SyntaxDefault Syntax = iota
+	// SyntaxJSONAPI is JSON:API conventions: `?filter[name][like]=tim*&sort=-id&page[number]=2&page[size]=10`.
+	// Pages are `page[number]` and `page[size]` (or `page[offset]` and `page[limit]`),
+	// sparse fieldsets `fields[users]=id,name` are fields. Keys without `filter` are parsed as usual.
+	SyntaxJSONAPI
+	// SyntaxOData is OData query options: `?$filter=age gt 18 and name eq 'Bob'&$orderby=created_at desc&$top=10`.
+	// `$select` is fields, `$top` and `$skip` are limit and offset, `$search` is search (see SetSearchColumns).
+	// Filter supports eq, ne, gt, ge, lt, le, in, contains(), startswith(), endswith(), and, or
+	// and parentheses, OR statements must be in parentheses if they are joined with other filters.
+	SyntaxOData
+	// SyntaxRSQL is RSQL (FIQL) expression of `filter` parameter: `?filter=name==Kill*;year=gt=2003&sort=-id`.
+	// `;` is AND, `,` is OR, `==` with wildcard `*` is LIKE, `=in=(a,b)` and `=out=(a,b)` are IN and NIN,
+	// `=method=` is any method of the default syntax, eg. `=ilike=`. OR statements must be in parentheses
+	// if they are joined with other filters.
+	SyntaxRSQL
+	// SyntaxColon is method at the value side: `?price=gte:100&name=like:Jo*|name=like:Ma*`.
+	// Values without known method before colon (eg. `time=10:30`) and bracketed keys are parsed as usual.
+	SyntaxColon
+)
+
+// SetSyntax sets syntax of query part of URL
+func (q *Query) SetSyntax(s Syntax) *Query {
+	q.syntax = s
+	return q
+}
+
+// syntaxQuery returns query translated from syntax of q to the default one
+func (q *Query) syntaxQuery() (url.Values, error) {
+	switch q.syntax {
+	case SyntaxJSONAPI:
+		return q.jsonapiQuery(), nil
+	case SyntaxOData:
+		return q.odataQuery()
+	case SyntaxRSQL:
+		return q.rsqlQuery()
+	case SyntaxColon:
+		return q.colonQuery(), nil
+	default:
+		return q.query, nil
+	}
+}
+
+// condition is a comparison of filter expression of syntax
+type condition struct {
+	name   string
+	method Method
+	values []string
+}
+
+// key returns key of condition in query of the default syntax
+func (c condition) key() string {
+	return c.name + "[" + strings.ToLower(string(c.method)) + "]"
+}
+
+// addConditions adds groups of conditions joined by OR to values,
+// group is encoded as OR statement of the default syntax: `a[eq]=1|b[gt]=2`.
+// Values containing delimiter of OR or delimiter of IN of lists can't be encoded, so they are errors.
+func (q *Query) addConditions(values url.Values, groups [][]condition) error {
+	for _, group := range groups {
+		parts := make([]string, len(group))
+		for i, c := range group {
+			if err := q.checkDelimiters(c.method, c.values); err != nil {
+				return err
+			}
+			value := strings.Join(c.values, q.delimiterIN)
+			if i > 0 {
+				value = c.key() + "=" + value
+			}
+			parts[i] = value
+		}
+		values.Add(group[0].key(), strings.Join(parts, q.delimiterOR))
+	}
+	return nil
+}
+
+// checkDelimiters returns error if values contain delimiter of OR or values of list method contain delimiter of IN
+func (q *Query) checkDelimiters(method Method, values []string) error {
+	for _, v := range values {
+		if strings.Contains(v, q.delimiterOR) {
+			return errors.Errorf("value %q contains %q", v, q.delimiterOR)
+		}
+		if isListMethod(method) && strings.Contains(v, q.delimiterIN) {
+			return errors.Errorf("value %q contains %q", v, q.delimiterIN)
+		}
+	}
+	return nil
+}
+
+// jsonapiPages are keys of page parameters of JSON:API
+var jsonapiPages = map[string]string{
+	"page[number]": "page",
+	"page[size]":   "per_page",
+	"page[offset]": "offset",
+	"page[limit]":  "limit",
+}
+
+// jsonapiQuery translates query of JSON:API syntax
+func (q *Query) jsonapiQuery() url.Values {
+	values := make(url.Values, len(q.query))
+	var fields []string
+
+	// sorted keys keep order of fieldsets
+	keys := make([]string, 0, len(q.query))
+	for key := range q.query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		list := q.query[key]
+		switch {
+		case strings.HasPrefix(key, "filter[") && strings.Contains(key, "]"):
+			// filter[name][eq] -> name[eq]
+			i := strings.Index(key, "]")
+			name := key[len("filter["):i] + key[i+1:]
+			values[name] = append(values[name], list...)
+		case strings.HasPrefix(key, "fields[") && strings.HasSuffix(key, "]"):
+			fields = append(fields, list...)
+		case jsonapiPages[key] != "":
+			name := jsonapiPages[key]
+			values[name] = append(values[name], list...)
+		default:
+			values[key] = append(values[key], list...)
+		}
+	}
+
+	if len(fields) > 0 {
+		values["fields"] = append(values["fields"], strings.Join(fields, q.delimiterIN))
+	}
+
+	return values
+}
+
+// colonQuery translates query of colon syntax: `price=gte:100` -> `price[gte]=100`,
+// OR statements are translated too: `name=like:Jo*|name=like:Ma*` -> `name[like]=Jo*|name[like]=Ma*`
+func (q *Query) colonQuery() url.Values {
+	values := make(url.Values, len(q.query))
+
+	for key, list := range q.query {
+		for _, value := range list {
+			parts := strings.Split(value, q.delimiterOR)
+			k, v := q.colonPart(key, parts[0])
+			parts[0] = v
+			for i, part := range parts[1:] {
+				if p := strings.Index(part, "="); p != -1 {
+					pk, pv := q.colonPart(part[:p], part[p+1:])
+					parts[i+1] = pk + "=" + pv
+				}
+			}
+			values.Add(k, strings.Join(parts, q.delimiterOR))
+		}
+	}
+
+	return values
+}
+
+// colonPart moves method of value to key if the key hasn't got method
+func (q *Query) colonPart(key, value string) (string, string) {
+	p := strings.Index(value, ":")
+	if p < 1 || strings.Contains(key, "[") {
+		return key, value
+	}
+
+	m := Method(strings.ToUpper(value[:p]))
+	if alias, ok := methodAliases[m]; ok {
+		m = alias
+	}
+	if _, ok := translateMethods[m]; !ok {
+		if _, ok := q.methods[m]; !ok {
+			return key, value
+		}
+	}
+
+	return key + "[" + strings.ToLower(value[:p]) + "]", value[p+1:]
+}
@@ -0,0 +1,108 @@
+package rqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyntaxJSONAPI(t *testing.T) {
+	validations := Validations{
+		"fields":  In("id", "name", "title"),
+		"sort":    In("id", "created_at"),
+		"name":    nil,
+		"age:int": nil,
+		"id:int":  nil,
+	}
+
+	cases := []struct {
+		url  string
+		sql  string
+		args []interface{}
+	}{
+		{
+			url:  "?filter[name][like]=tim*&filter[age][gte]=18&sort=-created_at,id&page[number]=3&page[size]=10",
+			sql:  "SELECT * FROM t WHERE age >= ? AND name LIKE ? ORDER BY created_at DESC, id LIMIT 10 OFFSET 20",
+			args: []interface{}{18, "tim%"},
+		},
+		{
+			url:  "?filter[id]=1&id[gt]=0&page[offset]=5&page[limit]=10",
+			sql:  "SELECT * FROM t WHERE id = ? AND id > ? LIMIT 10 OFFSET 5",
+			args: []interface{}{1, 0},
+		},
+		{
+			url:  "?fields[users]=id,name&fields[articles]=title",
+			sql:  "SELECT title, id, name FROM t",
+			args: []interface{}{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations).SetSyntax(SyntaxJSONAPI)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.sql, q.SQL("t"))
+			assert.Equal(t, c.args, q.Args())
+			QueryEqual(t, q, q.Clone())
+		})
+	}
+
+	q := NewQV(nil, validations).SetSyntax(SyntaxJSONAPI)
+	assert.NoError(t, q.SetUrlString("?filter[email]=a"))
+	assert.EqualError(t, q.Parse(), "email: filter not found")
+
+	// default syntax doesn't know filter parameter
+	q = NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?filter[name]=tim"))
+	assert.Error(t, q.Parse())
+}
+
+func TestSyntaxColon(t *testing.T) {
+	validations := Validations{
+		"sort":        In("id", "price"),
+		"price:float": nil,
+		"name":        nil,
+		"time":        nil,
+		"id:int":      nil,
+	}
+
+	cases := []struct {
+		url  string
+		sql  string
+		args []interface{}
+	}{
+		{
+			url:  "?price=gte:100&name=like:Jo*&sort=-price",
+			sql:  "SELECT * FROM t WHERE name LIKE ? AND price >= ? ORDER BY price DESC",
+			args: []interface{}{"Jo%", 100.0},
+		},
+		{
+			url:  "?name=like:Jo*|name=sw:Ma|id=in:1,2",
+			sql:  "SELECT * FROM t WHERE (name LIKE ? OR name LIKE ? OR id IN (?, ?))",
+			args: []interface{}{"Jo%", "Ma%", 1, 2},
+		},
+		{
+			url:  "?time=10:30&name=is:null&id[gt]=1",
+			sql:  "SELECT * FROM t WHERE id > ? AND name IS NULL AND time = ?",
+			args: []interface{}{1, "10:30"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations).SetSyntax(SyntaxColon)
+			assert.NoError(t, q.SetUrlString(c.url))
+			assert.NoError(t, q.Parse())
+			assert.Equal(t, c.sql, q.SQL("t"))
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	// methods of SetMethodSQL are known
+	q := NewQV(nil, validations).SetSyntax(SyntaxColon).SetMethodSQL("SIMILAR", "SIMILAR TO")
+	assert.NoError(t, q.SetUrlString("?name=similar:%25a%25"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "name SIMILAR TO ?", q.Where())
+
+	assert.NoError(t, q.SetUrlString("?price=gt:a"))
+	assert.EqualError(t, q.Parse(), "price[gt]: bad format")
+}
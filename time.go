@@ -0,0 +1,207 @@
+package rqp
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default layouts of `time` and `date` types
+var (
+	defaultTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+	defaultDateLayouts = []string{"2006-01-02"}
+)
+
+// SetClock sets function which returns current time for relative values of `time` and `date` filters
+// like `now-7d`, time.Now is used by default. It's useful for tests and to set time zone of `today`:
+//
+//	q.SetClock(func() time.Time { return time.Now().In(loc) })
+func (q *Query) SetClock(clock func() time.Time) *Query {
+	q.clock = clock
+	return q
+}
+
+// now returns current time of q
+func (q *Query) now() time.Time {
+	if q.clock != nil {
+		return q.clock()
+	}
+	return time.Now()
+}
+
+// relativeTime parses relative value: keyword with optional offset, eg. `now`, `today-1d`, `start_of_month+1M`.
+// Keywords are now, today, yesterday, tomorrow, start_of_week (Monday), start_of_month and start_of_year.
+// Units of offset are s, m, h, d, w, M (months) and y. Space is the same as `+` because `+` is space in URL.
+func (q *Query) relativeTime(value string) (time.Time, bool) {
+	keyword, offset := value, ""
+	if i := strings.IndexAny(value, "+- "); i != -1 {
+		keyword, offset = value[:i], value[i:]
+	}
+
+	now := q.now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var t time.Time
+	switch keyword {
+	case "now":
+		t = now
+	case "today":
+		t = day
+	case "yesterday":
+		t = day.AddDate(0, 0, -1)
+	case "tomorrow":
+		t = day.AddDate(0, 0, 1)
+	case "start_of_week":
+		t = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "start_of_month":
+		t = day.AddDate(0, 0, 1-day.Day())
+	case "start_of_year":
+		t = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Time{}, false
+	}
+
+	if offset == "" {
+		return t, true
+	}
+
+	sign := 1
+	if offset[0] == '-' {
+		sign = -1
+	}
+	offset = offset[1:]
+	if len(offset) < 2 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(offset[:len(offset)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	n *= sign
+
+	switch offset[len(offset)-1] {
+	case 's':
+		return t.Add(time.Duration(n) * time.Second), true
+	case 'm':
+		return t.Add(time.Duration(n) * time.Minute), true
+	case 'h':
+		return t.Add(time.Duration(n) * time.Hour), true
+	case 'd':
+		return t.AddDate(0, 0, n), true
+	case 'w':
+		return t.AddDate(0, 0, 7*n), true
+	case 'M':
+		return t.AddDate(0, n, 0), true
+	case 'y':
+		return t.AddDate(n, 0, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// SetTimeLayouts sets layouts of values of filters with `time` type, they are tried in order.
+// Default layouts are time.RFC3339, "2006-01-02T15:04:05" and "2006-01-02".
+// Values without time zone are in UTC. Integers which don't match layouts are Unix time
+// in seconds or in milliseconds if they are greater than 1e12.
+func (q *Query) SetTimeLayouts(layouts ...string) *Query {
+	q.timeLayouts = layouts
+	return q
+}
+
+// SetDateLayouts sets layouts of values of filters with `date` type, they are tried in order.
+// Default layout is "2006-01-02".
+func (q *Query) SetDateLayouts(layouts ...string) *Query {
+	q.dateLayouts = layouts
+	return q
+}
+
+// parseTime parses value of `time` or `date` type by layouts of q
+func (q *Query) parseTime(valueType, value string) (time.Time, error) {
+	layouts := q.timeLayouts
+	if len(layouts) == 0 {
+		layouts = defaultTimeLayouts
+	}
+	if valueType == "date" {
+		layouts = q.dateLayouts
+		if len(layouts) == 0 {
+			layouts = defaultDateLayouts
+		}
+	}
+
+	if t, ok := q.relativeTime(value); ok {
+		if valueType == "date" {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		return t, nil
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if valueType == "date" {
+				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			}
+			return t, nil
+		}
+	}
+
+	if t, ok := epochTime(value); ok {
+		if valueType == "date" {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, ErrBadFormat
+}
+
+// epochMillis is the lowest value of Unix time which is treated as milliseconds (2001-09-09 in ms)
+const epochMillis = 1e12
+
+// epochTime parses Unix time in seconds or milliseconds if value isn't lower than epochMillis
+func epochTime(value string) (time.Time, bool) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if n >= epochMillis || n <= -epochMillis {
+		return time.Unix(n/1000, n%1000*int64(time.Millisecond)).UTC(), true
+	}
+	return time.Unix(n, 0).UTC(), true
+}
+
+func (f *Filter) setTime(q *Query, valueType string, list []string) error {
+	times := make([]time.Time, len(list))
+	for i, s := range list {
+		t, err := q.parseTime(valueType, s)
+		if err != nil {
+			return err
+		}
+		times[i] = t
+	}
+
+	switch {
+	case len(list) == 1 && isNumericMethod(f.Method):
+		f.Value = times[0]
+	case len(list) > 1 && (f.Method == IN || f.Method == NIN):
+		f.Value = times
+	case f.Method == BETWEEN:
+		if len(times) != 2 {
+			return ErrBadFormat
+		}
+		if times[0].After(times[1]) {
+			return ErrNotInScope
+		}
+		f.Value = times
+	default:
+		return ErrMethodNotAllowed
+	}
+	return nil
+}
+
+// formatTime returns t as date if it's midnight in UTC and in time.RFC3339Nano otherwise
+func formatTime(t time.Time) string {
+	if t.Location() == time.UTC && t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)) {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,137 @@
+package rqp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeTypes(t *testing.T) {
+	validations := Validations{"created_at:time": nil, "day:date": nil}
+
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	moment := time.Date(2024, 1, 2, 10, 30, 0, 0, time.FixedZone("", 3*3600))
+
+	cases := []struct {
+		url   string
+		where string
+		args  []interface{}
+		err   error
+	}{
+		{url: "?created_at[gte]=2024-01-01", where: "created_at >= ?", args: []interface{}{day(2024, 1, 1)}},
+		{url: "?created_at=2024-01-02T10:30:00%2B03:00", where: "created_at = ?", args: []interface{}{moment}},
+		{url: "?created_at[lt]=2024-01-02T10:30:00", where: "created_at < ?", args: []interface{}{time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)}},
+		{url: "?day[in]=2024-01-01,2024-01-03", where: "day IN (?, ?)", args: []interface{}{day(2024, 1, 1), day(2024, 1, 3)}},
+		{url: "?day[between]=2024-01-01,2024-02-01", where: "day BETWEEN ? AND ?", args: []interface{}{day(2024, 1, 1), day(2024, 2, 1)}},
+		{url: "?day=[2024-01-01,2024-02-01)", where: "(day >= ? AND day < ?)", args: []interface{}{day(2024, 1, 1), day(2024, 2, 1)}},
+		{url: "?day[is]=null", where: "day IS NULL", args: []interface{}{}},
+		{url: "?day=2024-01-02T10:30:00Z", err: ErrBadFormat},
+		{url: "?created_at=someday", err: ErrBadFormat},
+		{url: "?day[like]=2024-01-01", err: ErrMethodNotAllowed},
+		{url: "?day[between]=2024-02-01,2024-01-01", err: ErrNotInScope},
+	}
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			q := NewQV(nil, validations)
+			assert.NoError(t, q.SetUrlString(c.url))
+			err := q.Parse()
+			if c.err != nil {
+				assert.Equal(t, c.err, errors.Cause(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.where, q.Where())
+			assert.Equal(t, c.args, q.Args())
+		})
+	}
+
+	// custom layouts
+	q := NewQV(nil, validations).SetDateLayouts("02.01.2006").SetTimeLayouts(time.RFC1123)
+	assert.NoError(t, q.SetUrlString("?day=02.01.2024"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{day(2024, 1, 2)}, q.Args())
+	assert.NoError(t, q.SetUrlString("?day=2024-01-02"))
+	assert.Equal(t, ErrBadFormat, errors.Cause(q.Clone().Parse()))
+
+	// query string and matching
+	q = NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?day[gte]=2024-01-01&created_at[lt]=2024-01-02T10:30:00Z"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "created_at%5Blt%5D=2024-01-02T10%3A30%3A00Z&day%5Bgte%5D=2024-01-01", q.ToQueryString())
+	assert.True(t, q.Match(struct {
+		Day       time.Time
+		CreatedAt time.Time `db:"created_at"`
+	}{day(2024, 1, 5), day(2024, 1, 2)}))
+}
+
+func TestRelativeTime(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 5, 15, 13, 45, 10, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	cases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"now", now},
+		{"now-7d", now.AddDate(0, 0, -7)},
+		{"now+1h", now.Add(time.Hour)},
+		{"now 30m", now.Add(30 * time.Minute)},
+		{"now-10s", now.Add(-10 * time.Second)},
+		{"today", day(2024, 5, 15)},
+		{"yesterday", day(2024, 5, 14)},
+		{"tomorrow", day(2024, 5, 16)},
+		{"start_of_week", day(2024, 5, 13)},
+		{"start_of_month", day(2024, 5, 1)},
+		{"start_of_month-1M", day(2024, 4, 1)},
+		{"start_of_year", day(2024, 1, 1)},
+		{"today-2w", day(2024, 5, 1)},
+		{"today+1y", day(2025, 5, 15)},
+	}
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			q := New().SetClock(func() time.Time { return now })
+			v, ok := q.relativeTime(c.value)
+			assert.True(t, ok)
+			assert.Equal(t, c.expected, v)
+		})
+	}
+
+	for _, value := range []string{"later", "now-", "now-d", "now-7x", "now--7d", "2024-01-01"} {
+		_, ok := New().relativeTime(value)
+		assert.False(t, ok, value)
+	}
+
+	// Sunday belongs to the week started on Monday
+	q := New().SetClock(func() time.Time { return day(2024, 5, 19) })
+	v, _ := q.relativeTime("start_of_week")
+	assert.Equal(t, day(2024, 5, 13), v)
+
+	q = NewQV(nil, Validations{"created_at:time": nil, "day:date": nil}).SetClock(func() time.Time { return now })
+	assert.NoError(t, q.SetUrlString("?created_at[gte]=now-7d&day=yesterday"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{now.AddDate(0, 0, -7), day(2024, 5, 14)}, q.Args())
+	assert.Equal(t, q.Args(), q.Clone().Args())
+}
+
+func TestEpochTime(t *testing.T) {
+	q := NewQV(nil, Validations{"created_at:time": nil, "day:date": nil})
+	assert.NoError(t, q.SetUrlString("?created_at[gte]=1719878400&created_at[lt]=1719878400123&day=1719900000"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{
+		time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 2, 0, 0, 0, 123*int(time.Millisecond), time.UTC),
+		time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC),
+	}, q.Args())
+
+	// layouts are tried first
+	q = NewQV(nil, Validations{"day:date": nil}).SetDateLayouts("20060102")
+	assert.NoError(t, q.SetUrlString("?day=20240101"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, q.Args())
+
+	_, ok := epochTime("17198784.5")
+	assert.False(t, ok)
+}
@@ -0,0 +1,73 @@
+package rqp
+
+import "sync"
+
+// TypeConverter converts one value of filter from query part of URL to value of custom type
+type TypeConverter func(value string) (interface{}, error)
+
+var (
+	typesMu sync.RWMutex
+	types   = map[string]TypeConverter{}
+)
+
+// builtinTypes are type tags of validation keys which are handled by the package
+var builtinTypes = map[string]bool{
+	"string": true, "int": true, "i": true, "bool": true, "b": true, "float": true, "f": true,
+	"decimal": true, "time": true, "date": true, "uuid": true,
+}
+
+// RegisterType registers custom type of values which could be used in keys of validations.
+// Error of conv is returned by Parse. Built-in types (int, bool, etc.) can't be overridden:
+// RegisterType panics for them like for nil conv.
+// Filters of custom types could be compared by `eq, ne, gt, lt, gte, lte, in, nin` methods.
+//
+//	rqp.RegisterType("money", func(s string) (interface{}, error) {
+//		return decimal.NewFromString(s)
+//	})
+//	q := rqp.NewQV(nil, rqp.Validations{"price:money": nil})
+func RegisterType(name string, conv TypeConverter) {
+	if builtinTypes[name] {
+		panic("rqp: RegisterType of built-in type " + name)
+	}
+	if conv == nil {
+		panic("rqp: RegisterType converter is nil")
+	}
+
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	types[name] = conv
+}
+
+// registeredType returns converter of custom type
+func registeredType(name string) (TypeConverter, bool) {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	conv, ok := types[name]
+	return conv, ok
+}
+
+// setCustom sets values converted by conv of custom type
+func (f *Filter) setCustom(conv TypeConverter, list []string) error {
+	switch {
+	case len(list) == 1 && isNumericMethod(f.Method):
+	case len(list) > 1 && (f.Method == IN || f.Method == NIN):
+	default:
+		return ErrMethodNotAllowed
+	}
+
+	values := make([]interface{}, len(list))
+	for i, s := range list {
+		v, err := conv(s)
+		if err != nil {
+			return err
+		}
+		values[i] = v
+	}
+
+	if len(values) == 1 {
+		f.Value = values[0]
+	} else {
+		f.Value = values
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package rqp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSlug string
+
+func TestRegisterType(t *testing.T) {
+	RegisterType("slug", func(s string) (interface{}, error) {
+		if s == "" || strings.ToLower(s) != s {
+			return nil, errors.New("bad slug")
+		}
+		return testSlug(s), nil
+	})
+	defer func() {
+		typesMu.Lock()
+		delete(types, "slug")
+		typesMu.Unlock()
+	}()
+
+	validations := Validations{
+		"category:slug": func(value interface{}) error {
+			if value.(testSlug) == "hidden" {
+				return ErrNotInScope
+			}
+			return nil
+		},
+	}
+
+	q := NewQV(nil, validations)
+	assert.NoError(t, q.SetUrlString("?category=books"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "category = ?", q.Where())
+	assert.Equal(t, []interface{}{testSlug("books")}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?category[in]=books,music"))
+	assert.NoError(t, q.Parse())
+	assert.Equal(t, "category IN (?, ?)", q.Where())
+	assert.Equal(t, []interface{}{testSlug("books"), testSlug("music")}, q.Args())
+
+	assert.NoError(t, q.SetUrlString("?category=Books"))
+	assert.EqualError(t, q.Parse(), "category: bad slug")
+
+	assert.NoError(t, q.SetUrlString("?category[in]=books,hidden"))
+	assert.EqualError(t, q.Parse(), "category[in]: not in scope")
+
+	assert.NoError(t, q.SetUrlString("?category[like]=b*"))
+	assert.EqualError(t, q.Parse(), "category[like]: method are not allowed")
+
+	// built-in types can't be overridden
+	assert.PanicsWithValue(t, "rqp: RegisterType of built-in type int", func() {
+		RegisterType("int", func(s string) (interface{}, error) { return s, nil })
+	})
+	assert.Panics(t, func() { RegisterType("money", nil) })
+	_, ok := registeredType("int")
+	assert.False(t, ok)
+}
@@ -1,6 +1,17 @@
 package rqp
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// isIdentifier returns true if s is a valid SQL identifier which could be qualified by dots
+// eg. "id", "users.id"
+func isIdentifier(s string) bool {
+	return identifierRegexp.MatchString(s)
+}
 
 func cleanSliceString(list []string) []string {
 	var clean []string
@@ -10,3 +10,12 @@ func Test_stringInSlice(t *testing.T) {
 		assert.Equal(t, false, stringInSlice("", nil))
 	})
 }
+
+func Test_isIdentifier(t *testing.T) {
+	assert.True(t, isIdentifier("id"))
+	assert.True(t, isIdentifier("users.user_id"))
+	assert.False(t, isIdentifier("1id"))
+	assert.False(t, isIdentifier("users."))
+	assert.False(t, isIdentifier("id; DROP TABLE users"))
+	assert.False(t, isIdentifier(""))
+}